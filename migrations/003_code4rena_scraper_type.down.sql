@@ -0,0 +1,3 @@
+UPDATE sources
+SET scraper_type = NULL
+WHERE index_page_url = 'https://code4rena.com/reports';
@@ -0,0 +1,4 @@
+-- Route the Code4rena source to its scraper implementation
+UPDATE sources
+SET scraper_type = 'code4rena'
+WHERE index_page_url = 'https://code4rena.com/reports';
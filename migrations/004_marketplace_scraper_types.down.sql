@@ -0,0 +1,3 @@
+UPDATE sources
+SET scraper_type = NULL
+WHERE scraper_type IN ('sherlock', 'cantina', 'codehawks');
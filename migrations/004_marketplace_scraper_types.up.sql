@@ -0,0 +1,17 @@
+-- Route the Sherlock, Cantina and CodeHawks sources to their scraper implementations
+UPDATE sources
+SET scraper_type = 'sherlock'
+WHERE index_page_url = 'https://audits.sherlock.xyz/api/contests?per_page=1000';
+
+UPDATE sources
+SET scraper_type = 'cantina'
+WHERE index_page_url IN (
+    'https://cantina.xyz/portfolio',
+    'https://cantina.xyz/portfolio?section=cantina-competitions',
+    'https://cantina.xyz/portfolio?section=cantina-solo',
+    'https://cantina.xyz/portfolio?section=spearbit-guild'
+);
+
+UPDATE sources
+SET scraper_type = 'codehawks'
+WHERE index_page_url = 'https://codehawks.cyfrin.io/contests';
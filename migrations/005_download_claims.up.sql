@@ -0,0 +1,2 @@
+-- Worker that last claimed the download, set by the atomic claim
+ALTER TABLE downloads ADD COLUMN claimed_by VARCHAR(255);
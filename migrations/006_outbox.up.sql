@@ -0,0 +1,18 @@
+-- Events written in the same transaction as the state change that produced
+-- them, published to their queue by the outbox relay
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    target VARCHAR(255) NOT NULL,      -- queue or topic name
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- Publish tracking
+    attempt_count INT NOT NULL DEFAULT 0 CHECK (attempt_count >= 0),
+    last_error TEXT,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_unpublished ON outbox(created_at) WHERE published_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_downloads_completed_hash;
+DROP TABLE IF EXISTS report_blobs;
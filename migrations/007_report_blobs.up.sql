@@ -0,0 +1,20 @@
+-- Links each report to the stored blob holding its file. Reports whose files
+-- have the same content hash share a single blob.
+CREATE TABLE report_blobs (
+    id BIGSERIAL PRIMARY KEY,
+    report_id BIGINT NOT NULL UNIQUE REFERENCES audit_reports(id) ON DELETE CASCADE,
+    download_id BIGINT NOT NULL REFERENCES downloads(id) ON DELETE CASCADE,
+    file_hash VARCHAR(64) NOT NULL,
+    storage_path VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_blobs_file_hash ON report_blobs(file_hash);
+CREATE INDEX idx_report_blobs_storage_path ON report_blobs(storage_path);
+CREATE INDEX idx_downloads_completed_hash ON downloads(file_hash) WHERE status = 'completed';
+
+-- Downloads completed so far each own their blob
+INSERT INTO report_blobs (report_id, download_id, file_hash, storage_path, created_at)
+SELECT report_id, id, file_hash, storage_path, COALESCE(completed_at, updated_at)
+FROM downloads
+WHERE status = 'completed' AND file_hash IS NOT NULL AND storage_path IS NOT NULL;
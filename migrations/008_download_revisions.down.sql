@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS download_versions;
+ALTER TABLE downloads DROP COLUMN IF EXISTS checked_at;
+ALTER TABLE downloads DROP COLUMN IF EXISTS version;
+ALTER TABLE downloads DROP COLUMN IF EXISTS last_modified;
+ALTER TABLE downloads DROP COLUMN IF EXISTS etag;
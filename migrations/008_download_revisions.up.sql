@@ -0,0 +1,22 @@
+-- HTTP validators of the stored file, sent back on re-checks so an unchanged
+-- report answers 304 instead of being downloaded again
+ALTER TABLE downloads ADD COLUMN etag VARCHAR(255);
+ALTER TABLE downloads ADD COLUMN last_modified VARCHAR(64);
+ALTER TABLE downloads ADD COLUMN version INT NOT NULL DEFAULT 1 CHECK (version >= 1);
+ALTER TABLE downloads ADD COLUMN checked_at TIMESTAMP;
+
+-- Previous versions of revised files; their blobs are kept in storage
+CREATE TABLE download_versions (
+    id BIGSERIAL PRIMARY KEY,
+    download_id BIGINT NOT NULL REFERENCES downloads(id) ON DELETE CASCADE,
+    version INT NOT NULL CHECK (version >= 1),
+    storage_path VARCHAR(500) NOT NULL,
+    file_hash VARCHAR(64) NOT NULL,
+    file_extension VARCHAR(10) NOT NULL,
+    etag VARCHAR(255),
+    last_modified VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (download_id, version)
+);
+
+CREATE INDEX idx_download_versions_storage_path ON download_versions(storage_path);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_downloads_next_attempt;
+ALTER TABLE downloads DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE downloads DROP COLUMN IF EXISTS max_attempts;
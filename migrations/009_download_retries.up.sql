@@ -0,0 +1,5 @@
+-- Attempts allowed to the download, and when the next one is due once it failed
+ALTER TABLE downloads ADD COLUMN max_attempts INT NOT NULL DEFAULT 3 CHECK (max_attempts >= 1);
+ALTER TABLE downloads ADD COLUMN next_attempt_at TIMESTAMP;
+
+CREATE INDEX idx_downloads_next_attempt ON downloads(next_attempt_at) WHERE status = 'failed';
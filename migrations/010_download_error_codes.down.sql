@@ -0,0 +1,10 @@
+-- Rejected downloads stay failed, with no attempt left
+UPDATE downloads
+SET status = 'failed', max_attempts = GREATEST(attempt_count, 1)
+WHERE status = 'rejected';
+
+ALTER TABLE downloads DROP CONSTRAINT IF EXISTS downloads_status_check;
+ALTER TABLE downloads ADD CONSTRAINT downloads_status_check
+    CHECK (status IN ('pending', 'in_progress', 'completed', 'failed'));
+
+ALTER TABLE downloads DROP COLUMN IF EXISTS error_code;
@@ -0,0 +1,6 @@
+-- Category of the last failure; permanent ones move the download to rejected
+ALTER TABLE downloads ADD COLUMN error_code VARCHAR(32);
+
+ALTER TABLE downloads DROP CONSTRAINT IF EXISTS downloads_status_check;
+ALTER TABLE downloads ADD CONSTRAINT downloads_status_check
+    CHECK (status IN ('pending', 'in_progress', 'completed', 'failed', 'rejected'));
@@ -0,0 +1,2 @@
+-- Type detected from the first bytes of the file, next to its extension
+ALTER TABLE downloads ADD COLUMN mime_type VARCHAR(100);
@@ -0,0 +1,2 @@
+-- Copy of the report page a file was resolved from, stored next to the file
+ALTER TABLE downloads ADD COLUMN snapshot_path VARCHAR(500);
@@ -0,0 +1,6 @@
+ALTER TABLE downloads
+    DROP COLUMN IF EXISTS archive_ref,
+    DROP COLUMN IF EXISTS archive_hash,
+    DROP COLUMN IF EXISTS archive_path;
+
+ALTER TABLE audit_reports DROP COLUMN IF EXISTS repository_ref;
@@ -0,0 +1,8 @@
+-- Commit or tag of the repository the audit covered, when the report states it
+ALTER TABLE audit_reports ADD COLUMN repository_ref VARCHAR(100);
+
+-- Tarball of the audited repository, stored next to the report file
+ALTER TABLE downloads
+    ADD COLUMN archive_path VARCHAR(500),
+    ADD COLUMN archive_hash VARCHAR(64),
+    ADD COLUMN archive_ref VARCHAR(100);
@@ -0,0 +1,14 @@
+-- Only the report artifacts fit the one download per report model
+DELETE FROM downloads WHERE kind <> 'report';
+
+DROP INDEX IF EXISTS idx_report_blobs_report_id;
+ALTER TABLE report_blobs DROP CONSTRAINT IF EXISTS report_blobs_download_id_key;
+ALTER TABLE report_blobs ADD CONSTRAINT report_blobs_report_id_key UNIQUE (report_id);
+
+ALTER TABLE downloads DROP CONSTRAINT IF EXISTS downloads_report_id_kind_key;
+ALTER TABLE downloads ADD CONSTRAINT downloads_report_id_key UNIQUE (report_id);
+
+ALTER TABLE downloads DROP CONSTRAINT IF EXISTS downloads_kind_check;
+ALTER TABLE downloads
+    DROP COLUMN IF EXISTS source_url,
+    DROP COLUMN IF EXISTS kind;
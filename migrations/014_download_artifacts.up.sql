@@ -0,0 +1,16 @@
+-- A report may have several artifacts: the report itself, findings published
+-- apart, a fix review. Each one is a download.
+ALTER TABLE downloads
+    ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'report',
+    ADD COLUMN source_url TEXT;
+
+ALTER TABLE downloads ADD CONSTRAINT downloads_kind_check
+    CHECK (kind IN ('report', 'findings', 'fix_review'));
+
+ALTER TABLE downloads DROP CONSTRAINT downloads_report_id_key;
+ALTER TABLE downloads ADD CONSTRAINT downloads_report_id_kind_key UNIQUE (report_id, kind);
+
+-- Blobs are linked per artifact rather than per report
+ALTER TABLE report_blobs DROP CONSTRAINT report_blobs_report_id_key;
+ALTER TABLE report_blobs ADD CONSTRAINT report_blobs_download_id_key UNIQUE (download_id);
+CREATE INDEX idx_report_blobs_report_id ON report_blobs(report_id);
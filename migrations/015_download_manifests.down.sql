@@ -0,0 +1 @@
+ALTER TABLE downloads DROP COLUMN IF EXISTS manifest_path;
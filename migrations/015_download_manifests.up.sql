@@ -0,0 +1,2 @@
+-- Manifest of the PDF and markdown files expanded out of a downloaded archive
+ALTER TABLE downloads ADD COLUMN manifest_path VARCHAR(500);
@@ -0,0 +1,4 @@
+ALTER TABLE audit_providers DROP CONSTRAINT IF EXISTS audit_providers_crawl_policy_check;
+ALTER TABLE audit_providers
+    DROP COLUMN IF EXISTS crawl_delay_seconds,
+    DROP COLUMN IF EXISTS crawl_policy;
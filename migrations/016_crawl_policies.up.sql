@@ -0,0 +1,9 @@
+-- How the terms of a provider let us crawl it: follow its robots.txt, skip it
+-- when an agreement allows us more, or fetch nothing at all. A crawl delay
+-- set here replaces the one of robots.txt.
+ALTER TABLE audit_providers
+    ADD COLUMN crawl_policy VARCHAR(20) NOT NULL DEFAULT 'robots',
+    ADD COLUMN crawl_delay_seconds NUMERIC(6, 2);
+
+ALTER TABLE audit_providers ADD CONSTRAINT audit_providers_crawl_policy_check
+    CHECK (crawl_policy IN ('robots', 'allow', 'deny'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_report_details_search_vector;
+DROP INDEX IF EXISTS idx_audit_reports_search_vector;
+
+ALTER TABLE audit_report_details DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE audit_reports DROP COLUMN IF EXISTS search_vector;
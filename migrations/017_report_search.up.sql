@@ -0,0 +1,17 @@
+-- Full-text search over reports: title and client weigh most, then the short
+-- summary, then the full text extracted into audit_report_details. The
+-- vectors are generated, they follow the text without any trigger.
+ALTER TABLE audit_reports ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(client_company, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(summary, '')), 'B')
+    ) STORED;
+
+ALTER TABLE audit_report_details ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(full_summary, '')), 'C')
+    ) STORED;
+
+CREATE INDEX idx_audit_reports_search_vector ON audit_reports USING GIN (search_vector);
+CREATE INDEX idx_audit_report_details_search_vector ON audit_report_details USING GIN (search_vector);
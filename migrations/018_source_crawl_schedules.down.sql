@@ -0,0 +1 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS crawl_scheduled_at;
@@ -0,0 +1,3 @@
+-- When the orchestrator last requested a crawl of the index page, so a crawl
+-- failing or still running is not requested again on every tick
+ALTER TABLE sources ADD COLUMN crawl_scheduled_at TIMESTAMP;
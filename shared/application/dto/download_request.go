@@ -5,6 +5,14 @@ import (
 	"time"
 )
 
+// Download event types, carried in DownloadRequest.EventType
+const (
+	DownloadEventRequested = "download.requested"
+	DownloadEventRetry     = "download.retry"
+	// DownloadEventRecheck asks to revalidate the file of a completed download
+	DownloadEventRecheck = "download.recheck"
+)
+
 // DownloadRequest represents the message payload for requesting a file download
 type DownloadRequest struct {
 	// EventID is the unique identifier for this message (for idempotency)
@@ -17,6 +25,11 @@ type DownloadRequest struct {
 	// DownloadID is the database ID of the download record to process
 	DownloadID int64 `json:"download_id"`
 
+	// Kind is the artifact of the report the download holds. The downloader
+	// checks it against the record; empty on events published before artifacts
+	// existed, which address the report itself.
+	Kind string `json:"kind,omitempty"`
+
 	// Timestamp when the message was created
 	Timestamp time.Time `json:"timestamp"`
 }
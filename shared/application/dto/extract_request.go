@@ -1,9 +1,20 @@
 package dto
 
 import (
+	"errors"
 	"time"
 )
 
+// Extraction stages, carried in ExtractRequest.EventType
+const (
+	ExtractStageRoot         = "root"
+	ExtractStageIntermediate = "intermediate"
+	ExtractStageLeaf         = "leaf"
+)
+
+// MetadataSourceID is the metadata key holding the sources.id being crawled
+const MetadataSourceID = "source_id"
+
 // ExtractRequest represents the message payload for requesting extraction of audit reports
 type ExtractRequest struct {
 	// EventID is the unique identifier for this message (for idempotency)
@@ -24,6 +35,27 @@ type ExtractRequest struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+func (r *ExtractRequest) Validate() error {
+	switch r.EventType {
+	case ExtractStageRoot, ExtractStageIntermediate, ExtractStageLeaf:
+	default:
+		return errors.New("event type must be root, intermediate or leaf")
+	}
+	if r.URL == "" {
+		return errors.New("URL is required")
+	}
+	if r.GetMetadataInt64(MetadataSourceID) <= 0 {
+		return errors.New("source ID metadata must be positive")
+	}
+	if r.EventID == "" {
+		return errors.New("event ID is required")
+	}
+	if r.Timestamp.IsZero() {
+		return errors.New("timestamp is required")
+	}
+	return nil
+}
+
 // GetMetadataString safely retrieves a string value from metadata
 func (r *ExtractRequest) GetMetadataString(key string) string {
 	if r.Metadata == nil {
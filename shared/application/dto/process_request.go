@@ -1,6 +1,13 @@
 package dto
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ProcessEventRequested is the type of the event asking to process a file,
+// carried in ProcessRequest.EventType
+const ProcessEventRequested = "process.requested"
 
 // ProcessRequest represents the message payload for requesting file processing
 type ProcessRequest struct {
@@ -16,3 +23,16 @@ type ProcessRequest struct {
 	// Timestamp when the message was created
 	Timestamp time.Time `json:"timestamp"`
 }
+
+func (r *ProcessRequest) Validate() error {
+	if r.ProcessID <= 0 {
+		return errors.New("process ID must be positive")
+	}
+	if r.EventID == "" {
+		return errors.New("event ID is required")
+	}
+	if r.Timestamp.IsZero() {
+		return errors.New("timestamp is required")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package dto
+
+// SearchHitView is a report matched by a search, with excerpts of the text
+// that matched as HTML: the text is escaped and the terms wrapped in <mark>
+type SearchHitView struct {
+	Report    ReportView `json:"report"`
+	Rank      float64    `json:"rank"`
+	Highlight string     `json:"highlight"`
+}
+
+// SearchPage is one page of search results, best ranked first; NextOffset
+// fetches the next one and is absent on the last page
+type SearchPage struct {
+	Results    []SearchHitView `json:"results"`
+	NextOffset *int            `json:"next_offset,omitempty"`
+}
@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"shared/domain/entity"
+)
+
+// ProcessView is the status of the processing of a downloaded file
+type ProcessView struct {
+	ID           int64      `json:"id"`
+	Status       string     `json:"status"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+	AttemptCount int        `json:"attempt_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// DownloadView is the status of the download of one artifact of a report,
+// with its processing once the file is stored
+type DownloadView struct {
+	ID            int64        `json:"id"`
+	ReportID      int64        `json:"report_id"`
+	Kind          string       `json:"kind"`
+	Status        string       `json:"status"`
+	ErrorCode     *string      `json:"error_code,omitempty"`
+	ErrorMessage  *string      `json:"error_message,omitempty"`
+	AttemptCount  int          `json:"attempt_count"`
+	MaxAttempts   int          `json:"max_attempts"`
+	NextAttemptAt *time.Time   `json:"next_attempt_at,omitempty"`
+	StoragePath   *string      `json:"storage_path,omitempty"`
+	FileHash      *string      `json:"file_hash,omitempty"`
+	MIMEType      *string      `json:"mime_type,omitempty"`
+	Version       int          `json:"version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	StartedAt     *time.Time   `json:"started_at,omitempty"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"`
+	CheckedAt     *time.Time   `json:"checked_at,omitempty"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	Process       *ProcessView `json:"process,omitempty"`
+}
+
+// ReportView is an audit report with the downloads of its artifacts
+type ReportView struct {
+	ID                int64           `json:"id"`
+	Provider          string          `json:"provider,omitempty"`
+	ProviderID        int64           `json:"provider_id"`
+	SourceID          int64           `json:"source_id"`
+	Title             string          `json:"title"`
+	EngagementType    string          `json:"engagement_type"`
+	ClientCompany     *string         `json:"client_company,omitempty"`
+	AuditStartDate    *time.Time      `json:"audit_start_date,omitempty"`
+	AuditEndDate      *time.Time      `json:"audit_end_date,omitempty"`
+	DetailsPageURL    string          `json:"details_page_url"`
+	SourceDownloadURL string          `json:"source_download_url"`
+	RepositoryURL     *string         `json:"repository_url,omitempty"`
+	FindingsSummary   json.RawMessage `json:"findings_summary,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	Downloads         []DownloadView  `json:"downloads"`
+}
+
+// ReportPage is one page of a report listing; NextCursor fetches the next
+// one and is empty on the last page
+type ReportPage struct {
+	Reports    []ReportView `json:"reports"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+func NewProcessView(p *entity.Process) *ProcessView {
+	if p == nil {
+		return nil
+	}
+	return &ProcessView{
+		ID:           p.ID,
+		Status:       string(p.Status),
+		ErrorMessage: p.ErrorMessage,
+		AttemptCount: p.AttemptCount,
+		CreatedAt:    p.CreatedAt,
+		StartedAt:    p.StartedAt,
+		CompletedAt:  p.CompletedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+// NewDownloadView describes d, and p when the file was handed to processing
+func NewDownloadView(d *entity.Download, p *entity.Process) DownloadView {
+	var errorCode *string
+	if d.ErrorCode != nil {
+		code := string(*d.ErrorCode)
+		errorCode = &code
+	}
+
+	return DownloadView{
+		ID:            d.ID,
+		ReportID:      d.ReportID,
+		Kind:          string(d.Kind),
+		Status:        string(d.Status),
+		ErrorCode:     errorCode,
+		ErrorMessage:  d.ErrorMessage,
+		AttemptCount:  d.AttemptCount,
+		MaxAttempts:   d.AttemptLimit,
+		NextAttemptAt: d.NextAttemptAt,
+		StoragePath:   d.StoragePath,
+		FileHash:      d.FileHash,
+		MIMEType:      d.MIMEType,
+		Version:       d.Version,
+		CreatedAt:     d.CreatedAt,
+		StartedAt:     d.StartedAt,
+		CompletedAt:   d.CompletedAt,
+		CheckedAt:     d.CheckedAt,
+		UpdatedAt:     d.UpdatedAt,
+		Process:       NewProcessView(p),
+	}
+}
+
+// NewReportView describes r, published by the provider with slug
+func NewReportView(r *entity.AuditReport, provider string, downloads []DownloadView) ReportView {
+	var findings json.RawMessage
+	if r.FindingsSummary != nil {
+		findings = json.RawMessage(*r.FindingsSummary)
+	}
+	if downloads == nil {
+		downloads = []DownloadView{}
+	}
+
+	return ReportView{
+		ID:                r.ID,
+		Provider:          provider,
+		ProviderID:        r.ProviderID,
+		SourceID:          r.SourceID,
+		Title:             r.Title,
+		EngagementType:    string(r.EngagementType),
+		ClientCompany:     r.ClientCompany,
+		AuditStartDate:    r.AuditStartDate,
+		AuditEndDate:      r.AuditEndDate,
+		DetailsPageURL:    r.DetailsPageURL,
+		SourceDownloadURL: r.SourceDownloadURL,
+		RepositoryURL:     r.RepositoryURL,
+		FindingsSummary:   findings,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+		Downloads:         downloads,
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMissingRequestID = errors.New("request ID is required")
+	ErrPayloadNotObject = errors.New("payload must be a JSON object")
+)
+
+func ErrPanic(recovered any) error {
+	return fmt.Errorf("handler panicked: %v", recovered)
+}
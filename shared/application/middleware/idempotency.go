@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"shared/application/ports"
+)
+
+// Idempotency answers success to requests already handled within ttl, so that
+// a redelivered event is not handled twice. Events are keyed by the event_id
+// of their payload; requests without one, like scheduled ticks whose ID is
+// the same every time, are always handled. Only successes and rejections are
+// remembered: a failed request is handled again when it is delivered again.
+// A store that cannot be read lets requests through, the handlers being
+// idempotent themselves.
+func Idempotency(store ports.IdempotencyStore, ttl time.Duration, logger ports.Logger, metrics ports.Metrics) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		if store == nil || ttl <= 0 {
+			return next
+		}
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+			eventID := req.EventID()
+			if eventID == "" {
+				return next.Handle(ctx, req)
+			}
+			key := "event:" + eventID
+
+			handled, err := store.Handled(ctx, key)
+			if err != nil {
+				logger.Error("Failed to check request idempotency",
+					"request_id", req.ID,
+					"key", key,
+					"error", err.Error())
+			}
+			if handled {
+				logger.Info("Request already handled, skipping",
+					"request_id", req.ID,
+					"key", key)
+				metrics.IncrementCounter("handler.duplicates", map[string]string{"type": requestType(req)})
+				return ports.RuntimeResponse{Success: true}, nil
+			}
+
+			resp, err := next.Handle(ctx, req)
+			if err == nil && (resp.Success || resp.Ack) {
+				if err := store.Remember(ctx, key, ttl); err != nil {
+					logger.Error("Failed to remember handled request",
+						"request_id", req.ID,
+						"key", key,
+						"error", err.Error())
+				}
+			}
+			return resp, err
+		})
+	}
+}
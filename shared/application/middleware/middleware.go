@@ -0,0 +1,143 @@
+// Package middleware wraps ports.Handler with the behaviour every runtime
+// applies to the requests it hands over: recovery, logging, metrics,
+// validation, idempotency and timeouts.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"shared/application/ports"
+)
+
+// Chain wraps handler with middlewares, the first one outermost
+func Chain(handler ports.Handler, middlewares ...ports.Middleware) ports.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Recovery turns a panic of the handler into an error, so that the request
+// fails and is delivered again instead of crashing the process
+func Recovery(logger ports.Logger, metrics ports.Metrics) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (resp ports.RuntimeResponse, err error) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					logger.Error("Handler panicked",
+						"request_id", req.ID,
+						"panic", fmt.Sprint(recovered),
+						"stack", string(debug.Stack()))
+					metrics.IncrementCounter("handler.panics", map[string]string{"type": requestType(req)})
+
+					err = ErrPanic(recovered)
+					resp = ports.RuntimeResponse{Success: false, Error: err.Error()}
+				}
+			}()
+			return next.Handle(ctx, req)
+		})
+	}
+}
+
+// Logging logs every request and its outcome, and tags the context with the
+// request ID for the logs of the handler
+func Logging(logger ports.Logger) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+			startTime := time.Now()
+			logger.Info("Handling request",
+				"request_id", req.ID,
+				"type", requestType(req),
+				"source", req.Source)
+
+			resp, err := next.Handle(ports.WithRequestID(ctx, req.ID), req)
+
+			duration := time.Since(startTime).Milliseconds()
+			switch {
+			case err != nil:
+				logger.Error("Request failed",
+					"request_id", req.ID,
+					"duration_ms", duration,
+					"error", err.Error())
+			case !resp.Success:
+				logger.Error("Request unsuccessful",
+					"request_id", req.ID,
+					"duration_ms", duration,
+					"ack", resp.Ack,
+					"error", resp.Error)
+			default:
+				logger.Info("Request handled",
+					"request_id", req.ID,
+					"duration_ms", duration)
+			}
+			return resp, err
+		})
+	}
+}
+
+// Metrics counts requests and records their latency, by type and outcome
+func Metrics(metrics ports.Metrics) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+			startTime := time.Now()
+			resp, err := next.Handle(ctx, req)
+
+			tags := map[string]string{"type": requestType(req), "outcome": outcome(resp, err)}
+			metrics.IncrementCounter("handler.requests", tags)
+			metrics.RecordHistogram("handler.duration_ms", float64(time.Since(startTime).Milliseconds()), tags)
+			return resp, err
+		})
+	}
+}
+
+// Timeout bounds the time the handler is given for each request; zero
+// leaves it unbounded
+func Timeout(timeout time.Duration) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.Handle(ctx, req)
+		})
+	}
+}
+
+// requestType labels req by its type, or the type of its event when the
+// runtime did not set one
+func requestType(req ports.RuntimeRequest) string {
+	if req.Type != "" {
+		return req.Type
+	}
+	if eventType := req.EventType(); eventType != "" {
+		return eventType
+	}
+	return "unknown"
+}
+
+func outcome(resp ports.RuntimeResponse, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case resp.Success:
+		return "success"
+	case resp.Ack:
+		return "rejected"
+	default:
+		return "failure"
+	}
+}
+
+// rejected answers a failure that delivering the request again cannot fix
+func rejected(err error) ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   err.Error(),
+		Ack:     true,
+	}
+}
@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"shared/application/ports"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestObservability(t *testing.T) (ports.Logger, ports.Metrics) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+	logger, metrics, err := obs.ComponentsScoped("middleware.test")
+	require.NoError(t, err)
+	return logger, metrics
+}
+
+// respond is a handler answering resp and err, counting its calls
+type respond struct {
+	resp  ports.RuntimeResponse
+	err   error
+	calls int
+}
+
+func (h *respond) Handle(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	h.calls++
+	return h.resp, h.err
+}
+
+// memoryStore is a ports.IdempotencyStore recording the keys remembered
+type memoryStore struct {
+	keys       map[string]time.Duration
+	handledErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]time.Duration)}
+}
+
+func (s *memoryStore) Handled(ctx context.Context, key string) (bool, error) {
+	if s.handledErr != nil {
+		return false, s.handledErr
+	}
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+func (s *memoryStore) Remember(ctx context.Context, key string, ttl time.Duration) error {
+	s.keys[key] = ttl
+	return nil
+}
+
+func eventRequest(eventID string) ports.RuntimeRequest {
+	payload, _ := json.Marshal(map[string]string{"event_id": eventID, "event_type": "download.requested"})
+	return ports.RuntimeRequest{ID: "msg-" + eventID, Payload: payload}
+}
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	trace := func(name string) ports.Middleware {
+		return func(next ports.Handler) ports.Handler {
+			return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+				order = append(order, name+" in")
+				resp, err := next.Handle(ctx, req)
+				order = append(order, name+" out")
+				return resp, err
+			})
+		}
+	}
+	handler := ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+		order = append(order, "handler")
+		return ports.RuntimeResponse{Success: true}, nil
+	})
+
+	_, err := Chain(handler, trace("first"), trace("second"), trace("third")).Handle(context.Background(), ports.RuntimeRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"first in", "second in", "third in",
+		"handler",
+		"third out", "second out", "first out",
+	}, order)
+}
+
+func TestChain_Empty(t *testing.T) {
+	handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+	resp, err := Chain(handler).Handle(context.Background(), ports.RuntimeRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, 1, handler.calls)
+}
+
+func TestRecovery(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	handler := ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+		panic("nil map")
+	})
+
+	resp, err := Recovery(logger, metrics)(handler).Handle(context.Background(), ports.RuntimeRequest{ID: "1"})
+	require.Error(t, err)
+	assert.Equal(t, ErrPanic("nil map").Error(), err.Error())
+	assert.False(t, resp.Success)
+	assert.False(t, resp.Ack, "a panicked request is delivered again")
+	assert.Equal(t, err.Error(), resp.Error)
+}
+
+func TestRecovery_PassesThrough(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	failure := errors.New("failure")
+	handler := &respond{resp: ports.RuntimeResponse{Error: "failure"}, err: failure}
+
+	resp, err := Recovery(logger, metrics)(handler).Handle(context.Background(), ports.RuntimeRequest{ID: "1"})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, "failure", resp.Error)
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ports.RuntimeRequest
+		err  error
+	}{
+		{name: "valid", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(`{"event_id":"e1"}`)}},
+		{name: "empty object", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(` {} `)}},
+		{name: "empty ID", req: ports.RuntimeRequest{Payload: json.RawMessage(`{}`)}, err: ErrMissingRequestID},
+		{name: "no payload", req: ports.RuntimeRequest{ID: "1"}, err: ErrPayloadNotObject},
+		{name: "array payload", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(`[{}]`)}, err: ErrPayloadNotObject},
+		{name: "string payload", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(`"{}"`)}, err: ErrPayloadNotObject},
+		{name: "null payload", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(`null`)}, err: ErrPayloadNotObject},
+		{name: "truncated object", req: ports.RuntimeRequest{ID: "1", Payload: json.RawMessage(`{"event_id":`)}, err: ErrPayloadNotObject},
+	}
+
+	logger, metrics := newTestObservability(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+
+			resp, err := Validation(logger, metrics)(handler).Handle(context.Background(), tt.req)
+			require.NoError(t, err)
+			if tt.err == nil {
+				assert.True(t, resp.Success)
+				assert.Equal(t, 1, handler.calls)
+				return
+			}
+			assert.False(t, resp.Success)
+			assert.True(t, resp.Ack, "an unreadable request must not be delivered again")
+			assert.Equal(t, tt.err.Error(), resp.Error)
+			assert.Zero(t, handler.calls)
+		})
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       ports.RuntimeResponse
+		err        error
+		remembered bool
+	}{
+		{name: "success", resp: ports.RuntimeResponse{Success: true}, remembered: true},
+		{name: "rejection", resp: ports.RuntimeResponse{Error: "invalid", Ack: true}, remembered: true},
+		{name: "failure", resp: ports.RuntimeResponse{Error: "unavailable"}},
+		{name: "error", resp: ports.RuntimeResponse{Success: true}, err: errors.New("unavailable")},
+	}
+
+	logger, metrics := newTestObservability(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			handler := &respond{resp: tt.resp, err: tt.err}
+			idempotent := Idempotency(store, time.Hour, logger, metrics)(handler)
+
+			_, err := idempotent.Handle(context.Background(), eventRequest("e1"))
+			assert.Equal(t, tt.err, err)
+
+			ttl, remembered := store.keys["event:e1"]
+			assert.Equal(t, tt.remembered, remembered)
+			if remembered {
+				assert.Equal(t, time.Hour, ttl)
+			}
+
+			// Delivered again: skipped only once remembered
+			resp, _ := idempotent.Handle(context.Background(), eventRequest("e1"))
+			if tt.remembered {
+				assert.Equal(t, 1, handler.calls)
+				assert.True(t, resp.Success)
+			} else {
+				assert.Equal(t, 2, handler.calls)
+			}
+		})
+	}
+}
+
+func TestIdempotency_WithoutEventID(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	store := newMemoryStore()
+	handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+	idempotent := Idempotency(store, time.Hour, logger, metrics)(handler)
+
+	tick := ports.RuntimeRequest{ID: "schedule", Payload: json.RawMessage(`{}`)}
+	for range 3 {
+		_, err := idempotent.Handle(context.Background(), tick)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, handler.calls)
+	assert.Empty(t, store.keys)
+}
+
+func TestIdempotency_OtherEvents(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+	idempotent := Idempotency(newMemoryStore(), time.Hour, logger, metrics)(handler)
+
+	for _, eventID := range []string{"e1", "e2", "e1", "e3", "e2"} {
+		_, err := idempotent.Handle(context.Background(), eventRequest(eventID))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, handler.calls)
+}
+
+func TestIdempotency_StoreUnavailable(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	store := newMemoryStore()
+	store.keys["event:e1"] = time.Hour
+	store.handledErr = errors.New("store unavailable")
+	handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+
+	_, err := Idempotency(store, time.Hour, logger, metrics)(handler).Handle(context.Background(), eventRequest("e1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, handler.calls, "a store that cannot be read lets requests through")
+}
+
+func TestIdempotency_Disabled(t *testing.T) {
+	logger, metrics := newTestObservability(t)
+	handler := &respond{resp: ports.RuntimeResponse{Success: true}}
+
+	assert.Same(t, handler, Idempotency(nil, time.Hour, logger, metrics)(handler))
+	assert.Same(t, handler, Idempotency(newMemoryStore(), 0, logger, metrics)(handler))
+}
+
+func TestTimeout(t *testing.T) {
+	handler := ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		<-ctx.Done()
+		return ports.RuntimeResponse{}, ctx.Err()
+	})
+
+	start := time.Now()
+	_, err := Timeout(10*time.Millisecond)(handler).Handle(context.Background(), ports.RuntimeRequest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeout_Unbounded(t *testing.T) {
+	handler := ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return ports.RuntimeResponse{Success: true}, nil
+	})
+
+	resp, err := Timeout(0)(handler).Handle(context.Background(), ports.RuntimeRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+}
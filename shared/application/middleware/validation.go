@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"shared/application/ports"
+)
+
+// Validation rejects, without delivering them again, requests no handler can
+// read: those without an ID, or whose payload is not a JSON object. The
+// fields of the payload are left to the handler.
+func Validation(logger ports.Logger, metrics ports.Metrics) ports.Middleware {
+	return func(next ports.Handler) ports.Handler {
+		return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+			if err := validate(req); err != nil {
+				logger.Error("Invalid request",
+					"request_id", req.ID,
+					"source", req.Source,
+					"error", err.Error())
+				metrics.IncrementCounter("handler.invalid", map[string]string{"type": requestType(req)})
+				return rejected(err), nil
+			}
+			return next.Handle(ctx, req)
+		})
+	}
+}
+
+func validate(req ports.RuntimeRequest) error {
+	if req.ID == "" {
+		return ErrMissingRequestID
+	}
+
+	payload := bytes.TrimSpace(req.Payload)
+	if len(payload) == 0 || payload[0] != '{' || !json.Valid(payload) {
+		return ErrPayloadNotObject
+	}
+	return nil
+}
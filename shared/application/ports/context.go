@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+)
+
+type providerSlugKey struct{}
+
+type requestIDKey struct{}
+
+type redirectCheckKey struct{}
+
+// WithProviderSlug tags ctx with the provider whose site is requested, so the
+// HTTPClient can apply the rate and crawl policy configured for it
+func WithProviderSlug(ctx context.Context, slug string) context.Context {
+	return context.WithValue(ctx, providerSlugKey{}, slug)
+}
+
+// ProviderSlug returns the provider ctx was tagged with, or an empty string
+func ProviderSlug(ctx context.Context) string {
+	slug, _ := ctx.Value(providerSlugKey{}).(string)
+	return slug
+}
+
+// WithRequestID tags ctx with the ID of the runtime request it handles
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the runtime request ctx was tagged with, or an empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRedirectCheck tags ctx with a check every redirect of a request made
+// with it must pass before it is followed, see CheckRedirect. A nil check
+// removes the one ctx was tagged with.
+func WithRedirectCheck(ctx context.Context, check func(req *http.Request) error) context.Context {
+	return context.WithValue(ctx, redirectCheckKey{}, check)
+}
+
+// CheckRedirect is an http.Client CheckRedirect running the check the
+// context of req was tagged with, if any
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	check, _ := req.Context().Value(redirectCheckKey{}).(func(req *http.Request) error)
+	if check == nil {
+		return nil
+	}
+	return check(req)
+}
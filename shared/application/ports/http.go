@@ -1,7 +1,26 @@
 package ports
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
 
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
+
+// ErrDisallowed is returned by an HTTPClient refusing a URL that robots.txt
+// or the terms of its provider keep us away from
+var ErrDisallowed = errors.New("URL disallowed by crawl policy")
+
+// CrawlDelayError is returned by an HTTPClient when the crawl delay of a host
+// leaves no time to send the request now
+type CrawlDelayError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CrawlDelayError) Error() string {
+	return fmt.Sprintf("crawl delay of remote host, retry after %s", e.RetryAfter)
+}
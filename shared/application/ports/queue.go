@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 )
 
 // Message represents a message to be published to a queue
@@ -10,6 +11,8 @@ type QueueMessage struct {
 	Target string
 	// Message body (will be JSON encoded)
 	Body interface{}
+	// Delay postpones the delivery of the message; zero delivers it at once
+	Delay time.Duration
 }
 
 // Queue defines the interface for message queue operations
@@ -2,9 +2,21 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"shared/domain/entity"
+	"shared/domain/entity/auditreport"
+	"shared/domain/entity/download"
+	"shared/domain/entity/process"
+	"time"
 )
 
+// ErrNotFound is returned by Get when no row has the ID
+var ErrNotFound = errors.New("entity not found")
+
+// ErrAlreadyExists is returned by Create when a row with the same unique key
+// is already stored
+var ErrAlreadyExists = errors.New("entity already exists")
+
 // BaseRepository defines common operations for all repositories
 type BaseRepository[T any] interface {
 	Create(ctx context.Context, entity *T) error
@@ -24,23 +36,139 @@ type AuditProviderRepository interface {
 type AuditReportRepository interface {
 	BaseRepository[entity.AuditReport]
 	ExistsByURL(ctx context.Context, sourceID int64, detailsURL string) (bool, error)
+	// List returns the reports matching filter, newest first
+	List(ctx context.Context, filter AuditReportFilter) ([]*entity.AuditReport, error)
+	// Search returns the reports matching search, best ranked first
+	Search(ctx context.Context, search ReportSearch) ([]*ReportSearchHit, error)
+}
+
+// AuditReportFilter narrows AuditReportRepository.List; zero fields match everything
+type AuditReportFilter struct {
+	ProviderSlug string
+	Status       download.Status // of the download of the report itself
+	Since        time.Time       // created at or after
+	BeforeID     int64           // cursor, only reports with a lower ID
+	Limit        int
+}
+
+// ReportSearch is a full-text query over reports and the text extracted from
+// them, narrowed by filters; zero filters match everything
+type ReportSearch struct {
+	Query          string // web search syntax: words, "quoted phrases", or, -excluded
+	ProviderSlug   string
+	EngagementType auditreport.EngagementType
+	EndedAfter     time.Time // audit end date range, inclusive
+	EndedBefore    time.Time
+	MinFindings    auditreport.FindingsSummary // least findings of each severity
+	Offset         int
+	Limit          int
+}
+
+// ReportSearchHit is a report matched by a search
+type ReportSearchHit struct {
+	Report   *entity.AuditReport
+	Rank     float64
+	Headline string // excerpts of the matching text as escaped HTML, terms wrapped in <mark>
+}
+
+type AuditReportDetailRepository interface {
+	BaseRepository[entity.AuditReportDetail]
+	GetByReportID(ctx context.Context, reportID int64) (*entity.AuditReportDetail, error)
+	Upsert(ctx context.Context, detail *entity.AuditReportDetail) error
 }
 
 type DownloadRepository interface {
 	BaseRepository[entity.Download]
+	// GetByReportID returns the download of the report itself
 	GetByReportID(ctx context.Context, reportID int64) (*entity.Download, error)
-	GetPendingDownloads(ctx context.Context, limit int) ([]*entity.Download, error)
+	// ListByReportID returns the downloads of every artifact of the report
+	ListByReportID(ctx context.Context, reportID int64) ([]*entity.Download, error)
+	// List returns the downloads matching filter, by report then kind
+	List(ctx context.Context, filter DownloadFilter) ([]*entity.Download, error)
+	// Claim atomically starts the download for workerID. It returns
+	// download.ErrClaimedElsewhere when the row is no longer startable.
+	Claim(ctx context.Context, id int64, workerID string) (*entity.Download, error)
+	// UpdateFromStatus updates the download only if its stored status is still from,
+	// and reports whether it did
+	UpdateFromStatus(ctx context.Context, dl *entity.Download, from download.Status) (bool, error)
+	// GetPendingDownloads returns pending downloads neither updated nor postponed past updatedBefore, oldest first
+	GetPendingDownloads(ctx context.Context, updatedBefore time.Time, limit int) ([]*entity.Download, error)
+	// FindCompletedByHash returns a completed download other than excludeID whose
+	// file has the given hash, or nil when there is none
+	FindCompletedByHash(ctx context.Context, fileHash string, excludeID int64) (*entity.Download, error)
+	// ClaimRecheck atomically reserves the re-check of a completed download not
+	// checked since checkedBefore. It returns download.ErrClaimedElsewhere otherwise.
+	ClaimRecheck(ctx context.Context, id int64, workerID string, checkedBefore time.Time) (*entity.Download, error)
+	// GetDueForRecheck returns completed downloads not checked since checkedBefore, least recently checked first
+	GetDueForRecheck(ctx context.Context, checkedBefore time.Time, limit int) ([]*entity.Download, error)
+	// GetDueRetries returns failed downloads with attempts left whose retry was
+	// due before dueBefore, oldest first
+	GetDueRetries(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.Download, error)
+	// GetStuckDownloads returns in progress downloads started before startedBefore
+	GetStuckDownloads(ctx context.Context, startedBefore time.Time, limit int) ([]*entity.Download, error)
+}
+
+// DownloadFilter narrows DownloadRepository.List; zero fields match everything
+type DownloadFilter struct {
+	ReportIDs []int64
+	Kind      download.ArtifactKind
+	Status    download.Status
 }
 
 type ProcessRepository interface {
 	BaseRepository[entity.Process]
 	GetByDownloadID(ctx context.Context, downloadID int64) (*entity.Process, error)
-	GetPendingProcesses(ctx context.Context, limit int) ([]*entity.Process, error)
+	// Claim atomically starts the process. It returns
+	// process.ErrClaimedElsewhere when the row is no longer startable.
+	Claim(ctx context.Context, id int64) (*entity.Process, error)
+	// UpdateFromStatus updates the process only if its stored status is still from,
+	// and reports whether it did
+	UpdateFromStatus(ctx context.Context, proc *entity.Process, from process.ProcessStatus) (bool, error)
+	// GetPendingProcesses returns pending processes not updated since updatedBefore, oldest first
+	GetPendingProcesses(ctx context.Context, updatedBefore time.Time, limit int) ([]*entity.Process, error)
+	// GetStuckProcesses returns in progress processes started before startedBefore
+	GetStuckProcesses(ctx context.Context, startedBefore time.Time, limit int) ([]*entity.Process, error)
+}
+
+type SourceRepository interface {
+	BaseRepository[entity.Source]
+	// ListDueForVisit returns up to limit active sources with a scraper that
+	// were not visited since visitedBefore nor scheduled since scheduledBefore
+	ListDueForVisit(ctx context.Context, visitedBefore, scheduledBefore time.Time, limit int) ([]*entity.Source, error)
+	// ScheduleCrawl stores the crawl_scheduled_at of source only if no crawl
+	// was scheduled since scheduledBefore, and reports whether it did
+	ScheduleCrawl(ctx context.Context, source *entity.Source, scheduledBefore time.Time) (bool, error)
+}
+
+type OutboxRepository interface {
+	BaseRepository[entity.OutboxMessage]
+	// GetUnpublished returns messages created before createdBefore that were
+	// never published, oldest first
+	GetUnpublished(ctx context.Context, createdBefore time.Time, limit int) ([]*entity.OutboxMessage, error)
+}
+
+type DownloadVersionRepository interface {
+	BaseRepository[entity.DownloadVersion]
+	ListByDownloadID(ctx context.Context, downloadID int64) ([]*entity.DownloadVersion, error)
+}
+
+type ReportBlobRepository interface {
+	BaseRepository[entity.ReportBlob]
+	// CountByStoragePath returns how many downloads, current or previous
+	// versions, reference the blob at storagePath
+	CountByStoragePath(ctx context.Context, storagePath string) (int64, error)
 }
 
 type Repositories interface {
 	AuditReport() AuditReportRepository
+	AuditReportDetail() AuditReportDetailRepository
 	Download() DownloadRepository
+	DownloadVersion() DownloadVersionRepository
 	Process() ProcessRepository
 	AuditProvider() AuditProviderRepository
+	Source() SourceRepository
+	Outbox() OutboxRepository
+	ReportBlob() ReportBlobRepository
+	// WithTx returns the same repositories running inside tx
+	WithTx(tx Transaction) Repositories
 }
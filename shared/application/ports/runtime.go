@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"time"
 )
 
@@ -19,10 +20,37 @@ func (r *RuntimeRequest) Unmarshal(v interface{}) error {
 	return json.Unmarshal(r.Payload, v)
 }
 
+// EventType returns the event_type of the payload, carried by every event
+// the workers publish; empty when the payload is not a JSON object
+func (r *RuntimeRequest) EventType() string {
+	var event struct {
+		EventType string `json:"event_type"`
+	}
+	if err := json.Unmarshal(r.Payload, &event); err != nil {
+		return ""
+	}
+	return event.EventType
+}
+
+// EventID returns the event_id of the payload, which stays the same when an
+// event is delivered again; empty when the payload has none
+func (r *RuntimeRequest) EventID() string {
+	var event struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(r.Payload, &event); err != nil {
+		return ""
+	}
+	return event.EventID
+}
+
 type RuntimeResponse struct {
 	Success bool            `json:"success"`
 	Data    json.RawMessage `json:"data,omitempty"`
 	Error   string          `json:"error,omitempty"`
+	// Ack acknowledges an unsuccessful request anyway, for failures that
+	// delivering the request again cannot fix
+	Ack bool `json:"ack,omitempty"`
 }
 
 // Handler processes requests through middleware chains
@@ -30,6 +58,31 @@ type Handler interface {
 	Handle(ctx context.Context, req RuntimeRequest) (RuntimeResponse, error)
 }
 
+// HandlerFunc adapts a function to a Handler
+type HandlerFunc func(ctx context.Context, req RuntimeRequest) (RuntimeResponse, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, req RuntimeRequest) (RuntimeResponse, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps a Handler with behaviour shared by every request, like
+// logging or timeouts
+type Middleware func(Handler) Handler
+
+// IdempotencyStore remembers the requests already handled, by key
+type IdempotencyStore interface {
+	// Handled tells if the request with key was remembered as handled
+	Handled(ctx context.Context, key string) (bool, error)
+	// Remember records the request with key as handled for ttl
+	Remember(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// Endpoints are read-only HTTP routes the HTTP runtime serves next to the
+// handler
+type Endpoints interface {
+	Register(mux *http.ServeMux)
+}
+
 // Adapter interface for platform-specific adapters
 type Runtime interface {
 	Start() error
@@ -10,6 +10,15 @@ const (
 	ProviderTypeIndividual  ProviderType = "individual"
 )
 
+// CrawlPolicy is what the terms of a provider let us fetch from its sites
+type CrawlPolicy string
+
+const (
+	CrawlPolicyRobots CrawlPolicy = "robots" // follow robots.txt
+	CrawlPolicyAllow  CrawlPolicy = "allow"  // an agreement lets us fetch beyond robots.txt
+	CrawlPolicyDeny   CrawlPolicy = "deny"   // the terms forbid automated fetching
+)
+
 type AuditProvider struct {
 	ID           int64        `db:"id"`
 	Name         string       `db:"name"`
@@ -20,4 +29,16 @@ type AuditProvider struct {
 	IsActive     bool         `db:"is_active"`
 	CreatedAt    time.Time    `db:"created_at"`
 	UpdatedAt    time.Time    `db:"updated_at"`
+	CrawlPolicy  CrawlPolicy  `db:"crawl_policy"`
+	// CrawlDelaySeconds replaces the Crawl-delay of robots.txt when set
+	CrawlDelaySeconds *float64 `db:"crawl_delay_seconds"`
+}
+
+// CrawlDelay returns the delay to leave between two requests to the sites of
+// the provider, and whether one is set
+func (p *AuditProvider) CrawlDelay() (time.Duration, bool) {
+	if p.CrawlDelaySeconds == nil {
+		return 0, false
+	}
+	return time.Duration(*p.CrawlDelaySeconds * float64(time.Second)), true
 }
@@ -1,6 +1,7 @@
 package auditreport
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"shared/domain/entity/auditprovider"
@@ -17,6 +18,16 @@ const (
 	EngagementTypeSolo        EngagementType = "solo"
 )
 
+// IsValid checks if the type is one the audit_reports table accepts
+func (t EngagementType) IsValid() bool {
+	switch t {
+	case EngagementTypeCompetition, EngagementTypePrivate, EngagementTypeBugBounty, EngagementTypeSolo:
+		return true
+	default:
+		return false
+	}
+}
+
 type AuditReport struct {
 	ID                int64          `db:"id"`
 	SourceID          int64          `db:"source_id"`
@@ -29,16 +40,56 @@ type AuditReport struct {
 	DetailsPageURL    string         `db:"details_page_url"`
 	SourceDownloadURL string         `db:"source_download_url"`
 	RepositoryURL     *string        `db:"repository_url"`
+	RepositoryRef     *string        `db:"repository_ref"` // commit or tag the audit covered
 	Summary           *string        `db:"summary"`
 	FindingsSummary   *string        `db:"findings_summary"` // JSONB as string
 	CreatedAt         time.Time      `db:"created_at"`
 	UpdatedAt         time.Time      `db:"updated_at"`
 }
 
+// FindingsSummary counts findings per severity, stored as findings_summary JSONB
+type FindingsSummary struct {
+	High          int `json:"high"`
+	Medium        int `json:"medium"`
+	Low           int `json:"low"`
+	Informational int `json:"informational"`
+}
+
+func NewAuditReport(sourceID, providerID int64, title string, engagementType EngagementType, detailsPageURL, sourceDownloadURL string) *AuditReport {
+	now := time.Now()
+	return &AuditReport{
+		SourceID:          sourceID,
+		ProviderID:        providerID,
+		Title:             title,
+		EngagementType:    engagementType,
+		DetailsPageURL:    detailsPageURL,
+		SourceDownloadURL: sourceDownloadURL,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// SetFindingsSummary serializes the severity counts into the JSONB column
+func (r *AuditReport) SetFindingsSummary(summary FindingsSummary) error {
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode findings summary: %w", err)
+	}
+	value := string(encoded)
+	r.FindingsSummary = &value
+	return nil
+}
+
 func (r *AuditReport) StoragePath(provider *auditprovider.AuditProvider, extension string) string {
 	return r.generatePath(provider.Slug, extension)
 }
 
+// ArtifactStoragePath returns where an artifact of the report is stored: one
+// folder per report, one file per kind
+func (r *AuditReport) ArtifactStoragePath(provider *auditprovider.AuditProvider, kind string, extension string) string {
+	return r.generatePath(provider.Slug, "/"+kind+extension)
+}
+
 func (r *AuditReport) generatePath(providerSlug string, extension string) string {
 	sanitizedTitle := r.sanitizeTitle()
 	return fmt.Sprintf(
@@ -0,0 +1,29 @@
+package auditreportdetail
+
+import "time"
+
+// AuditReportDetail holds the lengthy content of a report, kept apart from
+// audit_reports so listing queries stay light
+type AuditReportDetail struct {
+	ID          int64     `db:"id"`
+	ReportID    int64     `db:"report_id"`
+	FullSummary *string   `db:"full_summary"`
+	RawContent  *string   `db:"raw_content"` // JSONB as string
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func NewAuditReportDetail(reportID int64) *AuditReportDetail {
+	now := time.Now()
+	return &AuditReportDetail{
+		ReportID:  reportID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetFullSummary replaces the full text of the report
+func (d *AuditReportDetail) SetFullSummary(text string) {
+	d.FullSummary = &text
+	d.UpdatedAt = time.Now()
+}
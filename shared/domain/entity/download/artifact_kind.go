@@ -0,0 +1,21 @@
+package download
+
+// ArtifactKind tells the files of one audit report apart; a report has at
+// most one download per kind
+type ArtifactKind string
+
+const (
+	ArtifactKindReport    ArtifactKind = "report"     // the audit report itself
+	ArtifactKindFindings  ArtifactKind = "findings"   // findings published apart, e.g. as markdown
+	ArtifactKindFixReview ArtifactKind = "fix_review" // addendum reviewing the fixes
+)
+
+// IsValid checks if the kind is one the downloads table accepts
+func (k ArtifactKind) IsValid() bool {
+	switch k {
+	case ArtifactKindReport, ArtifactKindFindings, ArtifactKindFixReview:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,37 +2,83 @@ package download
 
 import (
 	"fmt"
+	"path"
+	"strings"
 	"time"
 )
 
 type Download struct {
-	ID            int64      `db:"id"`
-	ReportID      int64      `db:"report_id"`
-	StoragePath   *string    `db:"storage_path"`
-	FileHash      *string    `db:"file_hash"`
-	FileExtension *string    `db:"file_extension"`
-	Status        Status     `db:"status"`
-	ErrorMessage  *string    `db:"error_message"`
-	AttemptCount  int        `db:"attempt_count"`
-	CreatedAt     time.Time  `db:"created_at"`
-	StartedAt     *time.Time `db:"started_at"`
-	CompletedAt   *time.Time `db:"completed_at"`
-	UpdatedAt     time.Time  `db:"updated_at"`
+	ID            int64        `db:"id"`
+	ReportID      int64        `db:"report_id"`
+	Kind          ArtifactKind `db:"kind"`
+	SourceURL     *string      `db:"source_url"` // where to fetch the artifact, the report download URL when nil
+	StoragePath   *string      `db:"storage_path"`
+	FileHash      *string      `db:"file_hash"`
+	FileExtension *string      `db:"file_extension"`
+	MIMEType      *string      `db:"mime_type"`     // detected from the content, not declared by the host
+	SnapshotPath  *string      `db:"snapshot_path"` // report page the file was resolved from
+	ManifestPath  *string      `db:"manifest_path"` // files expanded out of an archive
+	Status        Status       `db:"status"`
+	ErrorMessage  *string      `db:"error_message"`
+	ErrorCode     *ErrorCode   `db:"error_code"` // category of the last failure
+	AttemptCount  int          `db:"attempt_count"`
+	CreatedAt     time.Time    `db:"created_at"`
+	StartedAt     *time.Time   `db:"started_at"`
+	CompletedAt   *time.Time   `db:"completed_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+	ClaimedBy     *string      `db:"claimed_by"` // worker that last claimed the download
+	ETag          *string      `db:"etag"`
+	LastModified  *string      `db:"last_modified"`
+	Version       int          `db:"version"`    // bumped each time the remote file is revised
+	CheckedAt     *time.Time   `db:"checked_at"` // last time the remote file was fetched or revalidated
+	AttemptLimit  int          `db:"max_attempts"`
+	NextAttemptAt *time.Time   `db:"next_attempt_at"` // when a failed download may be retried
+
+	// Tarball of the audited repository, stored next to the report file
+	ArchivePath *string `db:"archive_path"`
+	ArchiveHash *string `db:"archive_hash"`
+	ArchiveRef  *string `db:"archive_ref"` // commit, tag or branch the tarball was taken at
 }
 
+// DefaultMaxAttempts is the number of attempts before a download stays failed
+const DefaultMaxAttempts = 3
+
 func NewDownload(reportID int64, maxAttempts int) *Download {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
 	now := time.Now()
 	return &Download{
 		ReportID:     reportID,
+		Kind:         ArtifactKindReport,
 		Status:       StatusPending,
 		AttemptCount: 0,
+		AttemptLimit: maxAttempts,
+		Version:      1,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 }
 
 func NewDownloadWithDefaults(reportID int64) *Download {
-	return NewDownload(reportID, 3)
+	return NewDownload(reportID, DefaultMaxAttempts)
+}
+
+// NewArtifactDownload creates the download of a secondary artifact of a
+// report, fetched from sourceURL
+func NewArtifactDownload(reportID int64, kind ArtifactKind, sourceURL string, maxAttempts int) (*Download, error) {
+	if !kind.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArtifactKind, kind)
+	}
+	if sourceURL == "" {
+		return nil, ErrEmptySourceURL
+	}
+
+	d := NewDownload(reportID, maxAttempts)
+	d.Kind = kind
+	d.SourceURL = &sourceURL
+	return d, nil
 }
 
 // ============================================================================
@@ -41,8 +87,7 @@ func NewDownloadWithDefaults(reportID int64) *Download {
 
 // CanStart checks if the download can be started
 func (d *Download) CanStart() bool {
-	return d.Status == StatusPending ||
-		(d.Status == StatusFailed && d.AttemptCount < d.MaxAttempts())
+	return (d.Status == StatusPending || d.CanRetry()) && d.IsRetryDue(time.Now())
 }
 
 // Start begins or retries a download
@@ -52,6 +97,11 @@ func (d *Download) Start() error {
 		return ErrAlreadyCompleted
 	}
 
+	// Business rule: Cannot start once rejected
+	if d.Status == StatusRejected {
+		return ErrRejected
+	}
+
 	// Business rule: Cannot start if already in progress
 	if d.Status == StatusInProgress {
 		return ErrAlreadyInProgress
@@ -69,6 +119,8 @@ func (d *Download) Start() error {
 	d.AttemptCount++
 	d.UpdatedAt = now
 	d.ErrorMessage = nil
+	d.ErrorCode = nil
+	d.NextAttemptAt = nil
 
 	return nil
 }
@@ -101,12 +153,101 @@ func (d *Download) Complete(storagePath, fileHash, fileExtension string) error {
 	d.CompletedAt = &now
 	d.UpdatedAt = now
 	d.ErrorMessage = nil // Clear any error
+	d.ErrorCode = nil
+
+	return nil
+}
+
+// Revise replaces the file of a completed download with a newer version of it.
+// The caller keeps the previous version, see downloadversion.
+func (d *Download) Revise(storagePath, fileHash, fileExtension string) error {
+	if d.Status != StatusCompleted {
+		return ErrNotCompleted
+	}
+
+	if storagePath == "" {
+		return ErrEmptyStoragePath
+	}
+	if fileHash == "" {
+		return ErrEmptyFileHash
+	}
+	if fileExtension == "" {
+		return ErrEmptyFileExtension
+	}
+
+	now := time.Now()
+	d.Version++
+	d.StoragePath = &storagePath
+	d.FileHash = &fileHash
+	d.FileExtension = &fileExtension
+	d.CompletedAt = &now
+	d.UpdatedAt = now
 
 	return nil
 }
 
-// Fail marks the download as failed - simple state transition only
-func (d *Download) Fail(errorMessage string) error {
+// RecordValidators stores the HTTP validators returned with the file. Empty
+// values clear the previous ones, as the server no longer sends them.
+func (d *Download) RecordValidators(etag, lastModified string) {
+	d.ETag = nilIfEmpty(etag)
+	d.LastModified = nilIfEmpty(lastModified)
+
+	now := time.Now()
+	d.CheckedAt = &now
+	d.UpdatedAt = now
+}
+
+// RecordMIMEType stores the type detected from the content of the file
+func (d *Download) RecordMIMEType(mimeType string) {
+	d.MIMEType = nilIfEmpty(mimeType)
+	d.UpdatedAt = time.Now()
+}
+
+// RecordSnapshot links the stored copy of the report page the file was
+// resolved from. An empty path unlinks it, as the file has no page anymore.
+func (d *Download) RecordSnapshot(storagePath string) {
+	d.SnapshotPath = nilIfEmpty(storagePath)
+	d.UpdatedAt = time.Now()
+}
+
+// RecordManifest links the manifest of the files expanded out of an archive.
+// An empty path clears the manifest of a previous version.
+func (d *Download) RecordManifest(storagePath string) {
+	d.ManifestPath = nilIfEmpty(storagePath)
+	d.UpdatedAt = time.Now()
+}
+
+// URL returns where the artifact is fetched from, given the download URL of
+// its report
+func (d *Download) URL(reportDownloadURL string) string {
+	if d.SourceURL != nil {
+		return *d.SourceURL
+	}
+	return reportDownloadURL
+}
+
+// IsReport checks if the download holds the report itself rather than a
+// secondary artifact
+func (d *Download) IsReport() bool {
+	return d.Kind == ArtifactKindReport
+}
+
+// RecordSourceArchive links the stored tarball of the audited repository
+func (d *Download) RecordSourceArchive(storagePath, hash, ref string) {
+	d.ArchivePath = &storagePath
+	d.ArchiveHash = &hash
+	d.ArchiveRef = &ref
+	d.UpdatedAt = time.Now()
+}
+
+// HasSourceArchive reports whether the repository tarball is stored
+func (d *Download) HasSourceArchive() bool {
+	return d.ArchivePath != nil
+}
+
+// Fail marks the download as failed, or as rejected when code is permanent
+// so that no attempt is spent on it anymore
+func (d *Download) Fail(code ErrorCode, errorMessage string) error {
 	if d.Status == StatusCompleted {
 		return ErrAlreadyCompleted
 	}
@@ -115,14 +256,91 @@ func (d *Download) Fail(errorMessage string) error {
 	}
 
 	d.Status = StatusFailed
+	if code.IsPermanent() {
+		d.Status = StatusRejected
+	}
+	d.ErrorCode = &code
+	d.ErrorMessage = &errorMessage
+	d.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Postpone hands an in progress download back until the given time, as asked
+// by a host rate limiting us. The attempt is given back, since the file was
+// never requested or the host refused to serve it for now.
+func (d *Download) Postpone(until time.Time, code ErrorCode, errorMessage string) error {
+	if d.Status != StatusInProgress {
+		return ErrNotInProgress
+	}
+
+	d.Status = StatusPending
+	if d.AttemptCount > 0 {
+		d.AttemptCount--
+	}
+	d.NextAttemptAt = &until
+	d.ErrorCode = &code
 	d.ErrorMessage = &errorMessage
 	d.UpdatedAt = time.Now()
 
 	return nil
 }
 
+// ScheduleRetry sets when a failed download may run again, following policy.
+// It reports the delay, and false when no attempt is left.
+func (d *Download) ScheduleRetry(policy RetryPolicy) (time.Duration, bool) {
+	if !d.CanRetry() {
+		d.NextAttemptAt = nil
+		return 0, false
+	}
+
+	delay := policy.Delay(d.AttemptCount)
+	next := time.Now().Add(delay)
+	d.NextAttemptAt = &next
+	d.UpdatedAt = time.Now()
+	return delay, true
+}
+
+// Requeue records that the request for a pending download, or the retry of a
+// failed one, was published again
+func (d *Download) Requeue() error {
+	now := time.Now()
+	switch {
+	case d.Status == StatusPending:
+	case d.CanRetry():
+		// Keep the retry due while restarting the grace period of the event
+		d.NextAttemptAt = &now
+	default:
+		return ErrNotPending
+	}
+
+	d.UpdatedAt = now
+	return nil
+}
+
+// Abandon releases a download whose worker died after Start. The attempt
+// still counts, so a download that keeps crashing ends up failed.
+func (d *Download) Abandon(errorMessage string) error {
+	if d.Status != StatusInProgress {
+		return ErrNotInProgress
+	}
+
+	d.Status = StatusPending
+	if d.HasExceededMaxAttempts() {
+		d.Status = StatusFailed
+	}
+	d.ErrorMessage = &errorMessage
+	d.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// MaxAttempts falls back to the default for downloads built without a limit
 func (d *Download) MaxAttempts() int {
-	return 3
+	if d.AttemptLimit <= 0 {
+		return DefaultMaxAttempts
+	}
+	return d.AttemptLimit
 }
 
 // ============================================================================
@@ -134,6 +352,23 @@ func (d *Download) IsCompleted() bool {
 	return d.Status == StatusCompleted
 }
 
+// IsRetryDue checks if the backoff of a failed or postponed download is over at now
+func (d *Download) IsRetryDue(now time.Time) bool {
+	return d.NextAttemptAt == nil || !d.NextAttemptAt.After(now)
+}
+
+// HasValidators checks if a conditional request can be sent for the file
+func (d *Download) HasValidators() bool {
+	return d.ETag != nil || d.LastModified != nil
+}
+
+// VersionedPath returns where the next version of the file goes, so that
+// revising a report never overwrites the blob of the current version
+func (d *Download) VersionedPath(basePath string) string {
+	ext := path.Ext(basePath)
+	return fmt.Sprintf("%s.v%d%s", strings.TrimSuffix(basePath, ext), d.Version+1, ext)
+}
+
 // IsInProgress checks if the download is currently in progress
 func (d *Download) IsInProgress() bool {
 	return d.Status == StatusInProgress
@@ -144,6 +379,11 @@ func (d *Download) IsFailed() bool {
 	return d.Status == StatusFailed
 }
 
+// IsRejected checks if the download failed permanently
+func (d *Download) IsRejected() bool {
+	return d.Status == StatusRejected
+}
+
 // CanRetry checks if the download can be retried
 func (d *Download) CanRetry() bool {
 	return d.Status == StatusFailed && d.AttemptCount < d.MaxAttempts()
@@ -176,3 +416,10 @@ func (d *Download) Duration() *time.Duration {
 	duration := d.CompletedAt.Sub(*d.StartedAt)
 	return &duration
 }
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -10,13 +10,28 @@ var (
 	ErrAlreadyInProgress      = errors.New("download already in progress")
 	ErrInvalidStateTransition = errors.New("invalid download state transition")
 	ErrNotInProgress          = errors.New("download is not in progress")
+	ErrNotPending             = errors.New("download is not pending")
+	ErrNotCompleted           = errors.New("download is not completed")
+
+	// ErrClaimedElsewhere means the download could not be claimed because its
+	// state changed since it was read, typically because another worker holds it
+	ErrClaimedElsewhere = errors.New("download claimed by another worker")
 
 	// Retry/attempt errors
 	ErrMaxAttemptsExceeded = errors.New("maximum download attempts exceeded")
 	ErrCannotRetry         = errors.New("cannot retry download")
+	ErrRetryNotDue         = errors.New("download retry is not due yet")
+	ErrPostponed           = errors.New("download postponed")
+
+	// ErrRejected means the download failed permanently, no retry can help
+	ErrRejected = errors.New("download rejected")
 
 	// Validation errors
 	ErrEmptyStoragePath   = errors.New("storage path cannot be empty")
 	ErrEmptyFileHash      = errors.New("file hash cannot be empty")
 	ErrEmptyFileExtension = errors.New("file extension cannot be empty")
+	ErrEmptySourceURL     = errors.New("artifact source URL cannot be empty")
+
+	// ErrInvalidArtifactKind is returned for a kind the downloads table rejects
+	ErrInvalidArtifactKind = errors.New("invalid artifact kind")
 )
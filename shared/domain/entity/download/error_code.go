@@ -0,0 +1,30 @@
+package download
+
+// ErrorCode classifies why a download failed, which decides whether another
+// attempt may succeed
+type ErrorCode string
+
+const (
+	// Transient failures, worth a retry
+	ErrorCodeNetwork     ErrorCode = "network"      // DNS, connection and timeout errors
+	ErrorCodeServerError ErrorCode = "server_error" // HTTP 5xx and 408
+	ErrorCodeInternal    ErrorCode = "internal"     // storage or database errors on our side
+	ErrorCodeRateLimited ErrorCode = "rate_limited" // HTTP 429, postponed without spending an attempt
+
+	// Permanent failures, the download is rejected
+	ErrorCodeClientError    ErrorCode = "client_error" // HTTP 4xx and malformed requests
+	ErrorCodeTooLarge       ErrorCode = "too_large"
+	ErrorCodeInvalidContent ErrorCode = "invalid_content"
+	ErrorCodeBlocked        ErrorCode = "blocked"    // URL resolving to a private or internal address
+	ErrorCodeDisallowed     ErrorCode = "disallowed" // URL kept off by robots.txt or the provider terms
+)
+
+// IsPermanent checks if retrying a download that failed with the code cannot help
+func (c ErrorCode) IsPermanent() bool {
+	switch c {
+	case ErrorCodeClientError, ErrorCodeTooLarge, ErrorCodeInvalidContent, ErrorCodeBlocked, ErrorCodeDisallowed:
+		return true
+	default:
+		return false
+	}
+}
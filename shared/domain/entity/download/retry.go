@@ -0,0 +1,55 @@
+package download
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// maxDelay bounds the delays of policies without a cap, which would overflow
+// time.Duration after enough attempts
+const maxDelay = time.Duration(math.MaxInt64)
+
+// RetryPolicy spaces the attempts of a failing download with exponential backoff
+type RetryPolicy struct {
+	BaseDelay  time.Duration // delay after the first failed attempt
+	Multiplier float64       // growth of the delay after each further failure
+	Jitter     float64       // fraction of the delay randomly added or removed, from 0 to 1
+	MaxDelay   time.Duration // cap of the delay before jitter, 0 for none
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		BaseDelay:  time.Minute,
+		Multiplier: 2,
+		Jitter:     0.2,
+		MaxDelay:   time.Hour,
+	}
+}
+
+// Delay returns how long to wait after the given failed attempt, counting from 1
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	limit := maxDelay
+	if p.MaxDelay > 0 {
+		limit = p.MaxDelay
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(math.Max(p.Multiplier, 1), float64(attempt-1))
+	if delay > float64(limit) {
+		delay = float64(limit)
+	}
+
+	// Spread the retries of downloads that failed together
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	if delay >= float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,150 @@
+package download
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		delay   time.Duration
+	}{
+		{
+			name:    "first attempt",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, MaxDelay: time.Hour},
+			attempt: 1,
+			delay:   time.Minute,
+		},
+		{
+			name:    "attempt before the first",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, MaxDelay: time.Hour},
+			attempt: 0,
+			delay:   time.Minute,
+		},
+		{
+			name:    "exponential growth",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, MaxDelay: time.Hour},
+			attempt: 4,
+			delay:   8 * time.Minute,
+		},
+		{
+			name:    "fractional multiplier",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 1.5, MaxDelay: time.Hour},
+			attempt: 3,
+			delay:   2*time.Minute + 15*time.Second,
+		},
+		{
+			name:    "multiplier below one keeps the base delay",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 0.5, MaxDelay: time.Hour},
+			attempt: 5,
+			delay:   time.Minute,
+		},
+		{
+			name:    "capped",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, MaxDelay: time.Hour},
+			attempt: 10,
+			delay:   time.Hour,
+		},
+		{
+			name:    "capped past float overflow",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, MaxDelay: time.Hour},
+			attempt: 5000,
+			delay:   time.Hour,
+		},
+		{
+			name:    "no cap",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2},
+			attempt: 20,
+			delay:   time.Minute << 19,
+		},
+		{
+			name:    "no cap past time.Duration overflow",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2},
+			attempt: 100,
+			delay:   maxDelay,
+		},
+		{
+			name:    "no cap past float overflow",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2},
+			attempt: 5000,
+			delay:   maxDelay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.delay, tt.policy.Delay(tt.attempt))
+		})
+	}
+}
+
+func TestRetryPolicy_DelayJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "around the delay",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, Jitter: 0.2, MaxDelay: time.Hour},
+			attempt: 2,
+			min:     96 * time.Second,
+			max:     144 * time.Second,
+		},
+		{
+			name:    "around the cap",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, Jitter: 0.2, MaxDelay: time.Hour},
+			attempt: 50,
+			min:     48 * time.Minute,
+			max:     72 * time.Minute,
+		},
+		{
+			name:    "full jitter",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, Jitter: 1, MaxDelay: time.Hour},
+			attempt: 1,
+			min:     0,
+			max:     2 * time.Minute,
+		},
+		{
+			name:    "no cap stays positive",
+			policy:  RetryPolicy{BaseDelay: time.Minute, Multiplier: 2, Jitter: 0.2},
+			attempt: 5000,
+			min:     maxDelay - maxDelay/5,
+			max:     maxDelay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spread := make(map[time.Duration]bool)
+			for range 200 {
+				delay := tt.policy.Delay(tt.attempt)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+				spread[delay] = true
+			}
+			assert.Greater(t, len(spread), 1, "delays are spread")
+		})
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+
+	var delays []time.Duration
+	for attempt := 1; attempt <= 8; attempt++ {
+		delays = append(delays, policy.Delay(attempt))
+	}
+	assert.Equal(t, []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute,
+		16 * time.Minute, 32 * time.Minute, time.Hour, time.Hour,
+	}, delays)
+}
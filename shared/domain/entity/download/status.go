@@ -7,4 +7,15 @@ const (
 	StatusInProgress Status = "in_progress"
 	StatusCompleted  Status = "completed"
 	StatusFailed     Status = "failed"
+	StatusRejected   Status = "rejected" // failed for good, never retried
 )
+
+// IsValid checks if the status is one the downloads table accepts
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted, StatusFailed, StatusRejected:
+		return true
+	default:
+		return false
+	}
+}
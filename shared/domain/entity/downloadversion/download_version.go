@@ -0,0 +1,39 @@
+package downloadversion
+
+import (
+	"shared/domain/entity/download"
+	"time"
+)
+
+// DownloadVersion is a previous file of a revised download. Its blob stays in
+// storage; the download itself always points to the latest version.
+type DownloadVersion struct {
+	ID            int64     `db:"id"`
+	DownloadID    int64     `db:"download_id"`
+	Version       int       `db:"version"`
+	StoragePath   string    `db:"storage_path"`
+	FileHash      string    `db:"file_hash"`
+	FileExtension string    `db:"file_extension"`
+	ETag          *string   `db:"etag"`
+	LastModified  *string   `db:"last_modified"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// NewDownloadVersion snapshots the current file of a completed download,
+// before Revise replaces it
+func NewDownloadVersion(d *download.Download) (*DownloadVersion, error) {
+	if !d.IsCompleted() || d.StoragePath == nil || d.FileHash == nil || d.FileExtension == nil {
+		return nil, ErrDownloadNotCompleted
+	}
+
+	return &DownloadVersion{
+		DownloadID:    d.ID,
+		Version:       d.Version,
+		StoragePath:   *d.StoragePath,
+		FileHash:      *d.FileHash,
+		FileExtension: *d.FileExtension,
+		ETag:          d.ETag,
+		LastModified:  d.LastModified,
+		CreatedAt:     time.Now(),
+	}, nil
+}
@@ -0,0 +1,7 @@
+package downloadversion
+
+import "errors"
+
+var (
+	ErrDownloadNotCompleted = errors.New("download version requires a completed download")
+)
@@ -0,0 +1,9 @@
+package outbox
+
+import "errors"
+
+var (
+	ErrAlreadyPublished = errors.New("outbox message already published")
+	ErrEmptyTarget      = errors.New("outbox message target cannot be empty")
+	ErrInvalidPayload   = errors.New("outbox message payload cannot be encoded")
+)
@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Message is an event stored in the same transaction as the state change that
+// produced it, and published to its queue afterwards by the relay
+type Message struct {
+	ID           int64      `db:"id"`
+	Target       string     `db:"target"`
+	EventType    string     `db:"event_type"`
+	Payload      []byte     `db:"payload"`
+	AttemptCount int        `db:"attempt_count"`
+	LastError    *string    `db:"last_error"`
+	CreatedAt    time.Time  `db:"created_at"`
+	PublishedAt  *time.Time `db:"published_at"`
+}
+
+// NewMessage encodes body as the JSON payload to publish on target
+func NewMessage(target, eventType string, body interface{}) (*Message, error) {
+	if target == "" {
+		return nil, ErrEmptyTarget
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	return &Message{
+		Target:    target,
+		EventType: eventType,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ============================================================================
+// BUSINESS METHODS (State transitions with rules)
+// ============================================================================
+
+// MarkPublished records a successful publish
+func (m *Message) MarkPublished() error {
+	if m.IsPublished() {
+		return ErrAlreadyPublished
+	}
+
+	now := time.Now()
+	m.PublishedAt = &now
+	m.AttemptCount++
+	m.LastError = nil
+	return nil
+}
+
+// RecordFailure records a failed publish; the message stays in the outbox
+func (m *Message) RecordFailure(errorMsg string) {
+	m.AttemptCount++
+	m.LastError = &errorMsg
+}
+
+// ============================================================================
+// QUERY METHODS (Business logic queries)
+// ============================================================================
+
+func (m *Message) IsPublished() bool {
+	return m.PublishedAt != nil
+}
+
+// Body returns the payload as it must be handed to the queue, without
+// encoding it a second time
+func (m *Message) Body() json.RawMessage {
+	return json.RawMessage(m.Payload)
+}
@@ -6,6 +6,14 @@ var (
 	ErrAlreadyCompleted       = errors.New("process already completed")
 	ErrAlreadyInProgress      = errors.New("process already in progress")
 	ErrNotInProgress          = errors.New("process not in progress")
+	ErrNotPending             = errors.New("process not pending")
 	ErrInvalidStateTransition = errors.New("invalid process state transition")
 	ErrMaxAttemptsExceeded    = errors.New("maximum process attempts exceeded")
+
+	// ErrClaimedElsewhere means the process could not be claimed because its
+	// state changed since it was read, typically because another worker holds it
+	ErrClaimedElsewhere = errors.New("process claimed by another worker")
+
+	// Validation errors
+	ErrEmptyProcessorVersion = errors.New("processor version cannot be empty")
 )
@@ -1,6 +1,13 @@
 package process
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMaxAttempts bounds the attempts of processes loaded from the
+// database, which does not store a limit per process
+const DefaultMaxAttempts = 3
 
 type Process struct {
 	ID               int64         `db:"id"`
@@ -26,6 +33,158 @@ func NewProcess(downloadID int64) *Process {
 		AttemptCount: 0,
 		CreatedAt:    now,
 		UpdatedAt:    now,
-		MaxAttempts:  3, // Default, can be configured
+		MaxAttempts:  DefaultMaxAttempts, // Default, can be configured
+	}
+}
+
+// ============================================================================
+// BUSINESS METHODS (State transitions with rules)
+// ============================================================================
+
+// CanStart checks if the process can be started
+func (p *Process) CanStart() bool {
+	return p.Status == StatusPending ||
+		(p.Status == StatusFailed && p.AttemptCount < p.attemptLimit())
+}
+
+// Start begins or retries a process
+func (p *Process) Start() error {
+	// Business rule: Cannot start if already completed
+	if p.Status == StatusCompleted {
+		return ErrAlreadyCompleted
+	}
+
+	// Business rule: Cannot start if already in progress
+	if p.Status == StatusInProgress {
+		return ErrAlreadyInProgress
+	}
+
+	// Business rule: Check max attempts
+	if p.AttemptCount >= p.attemptLimit() {
+		return ErrMaxAttemptsExceeded
+	}
+
+	// State transition
+	now := time.Now()
+	p.Status = StatusInProgress
+	p.StartedAt = &now
+	p.AttemptCount++
+	p.UpdatedAt = now
+	p.ErrorMessage = nil
+
+	return nil
+}
+
+// Complete marks the process as successfully completed by the given processor version
+func (p *Process) Complete(processorVersion string) error {
+	// Business rule: Can only complete if in progress
+	if p.Status != StatusInProgress {
+		return fmt.Errorf("%w: cannot complete process in status %s",
+			ErrInvalidStateTransition, p.Status)
+	}
+
+	if processorVersion == "" {
+		return ErrEmptyProcessorVersion
+	}
+
+	// State transition
+	now := time.Now()
+	p.Status = StatusCompleted
+	p.ProcessorVersion = &processorVersion
+	p.CompletedAt = &now
+	p.UpdatedAt = now
+	p.ErrorMessage = nil
+
+	return nil
+}
+
+// Fail marks the process as failed - simple state transition only
+func (p *Process) Fail(errorMessage string) error {
+	if p.Status == StatusCompleted {
+		return ErrAlreadyCompleted
+	}
+	if p.Status != StatusInProgress {
+		return ErrNotInProgress // Can only fail from in_progress
+	}
+
+	p.Status = StatusFailed
+	p.ErrorMessage = &errorMessage
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Abandon releases a process whose worker died after it was claimed. The
+// attempt still counts, so a process that keeps crashing ends up failed.
+func (p *Process) Abandon(errorMessage string) error {
+	if p.Status != StatusInProgress {
+		return ErrNotInProgress
+	}
+
+	p.Status = StatusPending
+	if p.HasExceededMaxAttempts() {
+		p.Status = StatusFailed
+	}
+	p.ErrorMessage = &errorMessage
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Requeue records that the request for a pending process was published again
+func (p *Process) Requeue() error {
+	if p.Status != StatusPending {
+		return ErrNotPending
+	}
+
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reprocess schedules a finished process again, typically because its file
+// was revised. Attempts start over as this is new work.
+func (p *Process) Reprocess() error {
+	if p.Status == StatusInProgress {
+		return ErrAlreadyInProgress
+	}
+
+	p.Status = StatusPending
+	p.AttemptCount = 0
+	p.ErrorMessage = nil
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ============================================================================
+// QUERY METHODS (Business logic queries)
+// ============================================================================
+
+// IsCompleted checks if the process is completed
+func (p *Process) IsCompleted() bool {
+	return p.Status == StatusCompleted
+}
+
+// IsInProgress checks if the process is currently in progress
+func (p *Process) IsInProgress() bool {
+	return p.Status == StatusInProgress
+}
+
+// IsFailed checks if the process has failed
+func (p *Process) IsFailed() bool {
+	return p.Status == StatusFailed
+}
+
+// HasExceededMaxAttempts checks if max attempts have been exceeded
+func (p *Process) HasExceededMaxAttempts() bool {
+	return p.AttemptCount >= p.attemptLimit()
+}
+
+// attemptLimit falls back to the default when MaxAttempts was not set,
+// which is the case for rows loaded from the database
+func (p *Process) attemptLimit() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
 	}
+	return p.MaxAttempts
 }
@@ -0,0 +1,7 @@
+package reportblob
+
+import "errors"
+
+var (
+	ErrDownloadNotCompleted = errors.New("report blob requires a completed download")
+)
@@ -0,0 +1,33 @@
+package reportblob
+
+import (
+	"shared/domain/entity/download"
+	"time"
+)
+
+// ReportBlob records that an artifact of a report is stored at StoragePath.
+// Several artifacts point to the same path when their files have the same hash, so a
+// blob may only be deleted once no link references it.
+type ReportBlob struct {
+	ID          int64     `db:"id"`
+	ReportID    int64     `db:"report_id"`
+	DownloadID  int64     `db:"download_id"`
+	FileHash    string    `db:"file_hash"`
+	StoragePath string    `db:"storage_path"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// NewReportBlob links the artifact of a completed download to its blob
+func NewReportBlob(d *download.Download) (*ReportBlob, error) {
+	if !d.IsCompleted() || d.StoragePath == nil || d.FileHash == nil {
+		return nil, ErrDownloadNotCompleted
+	}
+
+	return &ReportBlob{
+		ReportID:    d.ReportID,
+		DownloadID:  d.ID,
+		FileHash:    *d.FileHash,
+		StoragePath: *d.StoragePath,
+		CreatedAt:   time.Now(),
+	}, nil
+}
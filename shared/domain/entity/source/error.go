@@ -0,0 +1,8 @@
+package source
+
+import "errors"
+
+var (
+	ErrInactive      = errors.New("source is not active")
+	ErrNoScraperType = errors.New("source has no scraper type")
+)
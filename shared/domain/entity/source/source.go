@@ -0,0 +1,57 @@
+package source
+
+import "time"
+
+type Source struct {
+	ID               int64      `db:"id"`
+	ProviderID       int64      `db:"provider_id"`
+	Name             string     `db:"name"`
+	EngagementType   *string    `db:"engagement_type"`
+	IndexPageURL     string     `db:"index_page_url"`
+	ScraperType      *string    `db:"scraper_type"`
+	LastVisitedAt    *time.Time `db:"last_visited_at"`
+	LastReportsCount *int       `db:"last_reports_count"`
+	LastMainDivHash  *string    `db:"last_main_div_hash"`
+	CrawlScheduledAt *time.Time `db:"crawl_scheduled_at"` // last crawl requested by the orchestrator
+	IsActive         bool       `db:"is_active"`
+	CreatedAt        time.Time  `db:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at"`
+}
+
+// ============================================================================
+// BUSINESS METHODS (State transitions with rules)
+// ============================================================================
+
+// MarkVisited records a crawl of the index page
+func (s *Source) MarkVisited(reportsCount int, mainDivHash string) {
+	now := time.Now()
+	s.LastVisitedAt = &now
+	s.LastReportsCount = &reportsCount
+	if mainDivHash != "" {
+		s.LastMainDivHash = &mainDivHash
+	}
+	s.UpdatedAt = now
+}
+
+// ScheduleCrawl records that a crawl of the index page was requested at
+func (s *Source) ScheduleCrawl(at time.Time) {
+	s.CrawlScheduledAt = &at
+	s.UpdatedAt = at
+}
+
+// ============================================================================
+// QUERY METHODS (Business logic queries)
+// ============================================================================
+
+// Scraper returns the scraper implementation that handles this source
+func (s *Source) Scraper() string {
+	if s.ScraperType == nil {
+		return ""
+	}
+	return *s.ScraperType
+}
+
+// HasStructureChanged reports whether the index page layout differs from the last visit
+func (s *Source) HasStructureChanged(mainDivHash string) bool {
+	return s.LastMainDivHash != nil && mainDivHash != "" && *s.LastMainDivHash != mainDivHash
+}
@@ -3,13 +3,23 @@ package entity
 import (
 	"shared/domain/entity/auditprovider"
 	"shared/domain/entity/auditreport"
+	"shared/domain/entity/auditreportdetail"
 	"shared/domain/entity/download"
+	"shared/domain/entity/downloadversion"
+	"shared/domain/entity/outbox"
 	"shared/domain/entity/process"
+	"shared/domain/entity/reportblob"
+	"shared/domain/entity/source"
 )
 
 type (
-	Download      = download.Download
-	Process       = process.Process
-	AuditProvider = auditprovider.AuditProvider
-	AuditReport   = auditreport.AuditReport
+	Download          = download.Download
+	Process           = process.Process
+	AuditProvider     = auditprovider.AuditProvider
+	AuditReport       = auditreport.AuditReport
+	AuditReportDetail = auditreportdetail.AuditReportDetail
+	Source            = source.Source
+	OutboxMessage     = outbox.Message
+	ReportBlob        = reportblob.ReportBlob
+	DownloadVersion   = downloadversion.DownloadVersion
 )
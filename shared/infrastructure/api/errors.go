@@ -0,0 +1,13 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned for a cursor not issued by a previous page
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func ErrInvalidParameter(name, value string) error {
+	return fmt.Errorf("invalid %s: %q", name, value)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"shared/application/ports"
+)
+
+// responder writes the JSON answers of the APIs and counts them by route
+// and status
+type responder struct {
+	logger  ports.Logger
+	metrics ports.Metrics
+}
+
+func (a *responder) writeJSON(w http.ResponseWriter, r *http.Request, body any) {
+	a.metrics.IncrementCounter("api.requests", map[string]string{"route": r.Pattern, "status": "200"})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		a.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// writeLookupError answers 404 for missing entities, 500 otherwise
+func (a *responder) writeLookupError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ports.ErrNotFound) {
+		a.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	a.writeError(w, r, http.StatusInternalServerError, err)
+}
+
+func (a *responder) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	a.metrics.IncrementCounter("api.requests", map[string]string{"route": r.Pattern, "status": strconv.Itoa(status)})
+
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		a.logger.Error("Query failed",
+			"path", r.URL.Path,
+			"error", err.Error())
+		message = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	resp := ports.RuntimeResponse{
+		Success: false,
+		Error:   message,
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		a.logger.Error("Failed to encode error response", "error", err)
+	}
+}
+
+// parseTime accepts RFC 3339 timestamps and plain dates
+func parseTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"shared/application/dto"
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/auditreport"
+)
+
+// maxQueryLength bounds the search terms, longer queries are rejected
+const maxQueryLength = 256
+
+// SearchAPI finds reports by the words of their title, client, summary and
+// the text extracted from them, best matches first:
+//
+//	GET /search?q=&provider=&engagement_type=&ended_after=&ended_before=
+//	           &min_high=&min_medium=&min_low=&min_informational=&offset=&limit=
+//
+// q takes the web search syntax: words, "quoted phrases", or, -excluded.
+// The highlight of a result is HTML safe to render: the scraped text is
+// escaped and <mark> is the only tag, around the matching terms.
+type SearchAPI struct {
+	responder
+	repositories ports.Repositories
+}
+
+func NewSearchAPI(repositories ports.Repositories, obs ports.Observability) (*SearchAPI, error) {
+	logger, metrics, err := obs.ComponentsScoped("api.search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return &SearchAPI{
+		responder:    responder{logger: logger, metrics: metrics},
+		repositories: repositories,
+	}, nil
+}
+
+// Register mounts the routes of the API on mux
+func (a *SearchAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /search", a.search)
+}
+
+func (a *SearchAPI) search(w http.ResponseWriter, r *http.Request) {
+	search, err := reportSearch(r)
+	if err != nil {
+		a.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// One more hit than asked tells if there is a next page
+	pageSize := search.Limit
+	search.Limit++
+
+	ctx := r.Context()
+	hits, err := a.repositories.AuditReport().Search(ctx, search)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	page := dto.SearchPage{Results: []dto.SearchHitView{}}
+	if len(hits) > pageSize {
+		hits = hits[:pageSize]
+		next := search.Offset + pageSize
+		page.NextOffset = &next
+	}
+
+	reports := make([]*entity.AuditReport, 0, len(hits))
+	for _, hit := range hits {
+		reports = append(reports, hit.Report)
+	}
+	views, err := reportViews(ctx, a.repositories, reports)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i, hit := range hits {
+		page.Results = append(page.Results, dto.SearchHitView{
+			Report:    views[i],
+			Rank:      hit.Rank,
+			Highlight: hit.Headline,
+		})
+	}
+
+	a.writeJSON(w, r, page)
+}
+
+// reportSearch reads a search from the query string
+func reportSearch(r *http.Request) (ports.ReportSearch, error) {
+	query := r.URL.Query()
+	search := ports.ReportSearch{
+		Query:          strings.TrimSpace(query.Get("q")),
+		ProviderSlug:   query.Get("provider"),
+		EngagementType: auditreport.EngagementType(query.Get("engagement_type")),
+		Limit:          defaultPageSize,
+	}
+
+	if search.Query == "" || len(search.Query) > maxQueryLength {
+		return search, ErrInvalidParameter("q", search.Query)
+	}
+	if search.EngagementType != "" && !search.EngagementType.IsValid() {
+		return search, ErrInvalidParameter("engagement_type", query.Get("engagement_type"))
+	}
+
+	if after := query.Get("ended_after"); after != "" {
+		parsed, err := parseTime(after)
+		if err != nil {
+			return search, ErrInvalidParameter("ended_after", after)
+		}
+		search.EndedAfter = parsed
+	}
+	if before := query.Get("ended_before"); before != "" {
+		parsed, err := parseTime(before)
+		if err != nil {
+			return search, ErrInvalidParameter("ended_before", before)
+		}
+		search.EndedBefore = parsed
+	}
+
+	minimums := []struct {
+		name  string
+		value *int
+	}{
+		{"min_high", &search.MinFindings.High},
+		{"min_medium", &search.MinFindings.Medium},
+		{"min_low", &search.MinFindings.Low},
+		{"min_informational", &search.MinFindings.Informational},
+	}
+	for _, minimum := range minimums {
+		value := query.Get(minimum.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return search, ErrInvalidParameter(minimum.name, value)
+		}
+		*minimum.value = parsed
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		parsed, err := strconv.Atoi(offset)
+		if err != nil || parsed < 0 {
+			return search, ErrInvalidParameter("offset", offset)
+		}
+		search.Offset = parsed
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			return search, ErrInvalidParameter("limit", limit)
+		}
+		search.Limit = parsed
+	}
+	return search, nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/application/dto"
+	"shared/application/ports"
+	"shared/domain/entity/auditreport"
+	"shared/domain/entity/download"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withHits makes the reports of ids the hits of any search, best ranked first
+func withHits(s *store, ids ...int64) *store {
+	for i, id := range ids {
+		s.hits = append(s.hits, &ports.ReportSearchHit{
+			Report:   s.reports[id-1],
+			Rank:     float64(len(ids) - i),
+			Headline: "<mark>reentrancy</mark> in report",
+		})
+	}
+	return s
+}
+
+func hitIDs(page dto.SearchPage) []int64 {
+	ids := make([]int64, 0, len(page.Results))
+	for _, hit := range page.Results {
+		ids = append(ids, hit.Report.ID)
+	}
+	return ids
+}
+
+func TestSearchAPI_Search(t *testing.T) {
+	s := withHits(newTestStore(), 3, 1, 2)
+	mux := newTestMux(t, s)
+
+	var page dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?q=reentrancy", &page))
+	assert.Equal(t, []int64{3, 1, 2}, hitIDs(page))
+	assert.Nil(t, page.NextOffset, "last page")
+
+	best := page.Results[0]
+	assert.Equal(t, 3.0, best.Rank)
+	assert.Equal(t, "<mark>reentrancy</mark> in report", best.Highlight)
+	assert.Equal(t, "code4rena", best.Report.Provider)
+	require.Len(t, best.Report.Downloads, 1)
+	assert.Equal(t, string(download.StatusPending), best.Report.Downloads[0].Status)
+
+	assert.Equal(t, "cantina", page.Results[2].Report.Provider)
+	assert.Len(t, page.Results[1].Report.Downloads, 2, "report and findings of report 1")
+
+	require.Len(t, s.searches, 1)
+	assert.Equal(t, ports.ReportSearch{Query: "reentrancy", Limit: defaultPageSize + 1}, s.searches[0])
+}
+
+func TestSearchAPI_SearchFilters(t *testing.T) {
+	query := url.Values{
+		"q":                 {`  "flash loan" -oracle  `},
+		"provider":          {"cantina"},
+		"engagement_type":   {"solo"},
+		"ended_after":       {"2025-01-01"},
+		"ended_before":      {"2025-06-30T12:00:00Z"},
+		"min_high":          {"1"},
+		"min_medium":        {"2"},
+		"min_low":           {"0"},
+		"min_informational": {"4"},
+		"offset":            {"20"},
+		"limit":             {"10"},
+	}
+	s := newTestStore()
+	mux := newTestMux(t, s)
+
+	var page dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?"+query.Encode(), &page))
+	assert.Empty(t, page.Results)
+	assert.NotNil(t, page.Results, "results encode as an empty list")
+
+	require.Len(t, s.searches, 1)
+	assert.Equal(t, ports.ReportSearch{
+		Query:          `"flash loan" -oracle`,
+		ProviderSlug:   "cantina",
+		EngagementType: auditreport.EngagementTypeSolo,
+		EndedAfter:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndedBefore:    time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC),
+		MinFindings:    auditreport.FindingsSummary{High: 1, Medium: 2, Informational: 4},
+		Offset:         20,
+		Limit:          11,
+	}, s.searches[0])
+}
+
+func TestSearchAPI_SearchPages(t *testing.T) {
+	mux := newTestMux(t, withHits(newTestStore(), 6, 5, 4, 3, 2))
+
+	var first dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?q=reentrancy&limit=2", &first))
+	assert.Equal(t, []int64{6, 5}, hitIDs(first))
+	require.NotNil(t, first.NextOffset)
+	assert.Equal(t, 2, *first.NextOffset)
+
+	var second dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?q=reentrancy&limit=2&offset=2", &second))
+	assert.Equal(t, []int64{4, 3}, hitIDs(second))
+	require.NotNil(t, second.NextOffset)
+	assert.Equal(t, 4, *second.NextOffset)
+
+	var last dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?q=reentrancy&limit=2&offset=4", &last))
+	assert.Equal(t, []int64{2}, hitIDs(last))
+	assert.Nil(t, last.NextOffset)
+
+	var exact dto.SearchPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/search?q=reentrancy&limit=5", &exact))
+	assert.Len(t, exact.Results, 5)
+	assert.Nil(t, exact.NextOffset, "a full last page has no next one")
+}
+
+func TestSearchAPI_Errors(t *testing.T) {
+	long := strings.Repeat("a", maxQueryLength+1)
+	tests := []struct {
+		name   string
+		target string
+		err    string
+	}{
+		{name: "no query", target: "/search", err: ErrInvalidParameter("q", "").Error()},
+		{name: "blank query", target: "/search?q=%20%20", err: ErrInvalidParameter("q", "").Error()},
+		{name: "query too long", target: "/search?q=" + long, err: ErrInvalidParameter("q", long).Error()},
+		{name: "bad engagement type", target: "/search?q=a&engagement_type=audit", err: ErrInvalidParameter("engagement_type", "audit").Error()},
+		{name: "bad ended_after", target: "/search?q=a&ended_after=june", err: ErrInvalidParameter("ended_after", "june").Error()},
+		{name: "bad ended_before", target: "/search?q=a&ended_before=2025-13-01", err: ErrInvalidParameter("ended_before", "2025-13-01").Error()},
+		{name: "negative min_high", target: "/search?q=a&min_high=-1", err: ErrInvalidParameter("min_high", "-1").Error()},
+		{name: "min_low not a number", target: "/search?q=a&min_low=few", err: ErrInvalidParameter("min_low", "few").Error()},
+		{name: "negative offset", target: "/search?q=a&offset=-10", err: ErrInvalidParameter("offset", "-10").Error()},
+		{name: "limit zero", target: "/search?q=a&limit=0", err: ErrInvalidParameter("limit", "0").Error()},
+		{name: "limit too high", target: "/search?q=a&limit=201", err: ErrInvalidParameter("limit", "201").Error()},
+	}
+
+	s := newTestStore()
+	mux := newTestMux(t, s)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp ports.RuntimeResponse
+			assert.Equal(t, http.StatusBadRequest, serve(t, mux, tt.target, &resp))
+			assert.False(t, resp.Success)
+			assert.Equal(t, tt.err, resp.Error)
+		})
+	}
+	assert.Empty(t, s.searches, "invalid searches never reach the repository")
+}
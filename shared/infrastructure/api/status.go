@@ -0,0 +1,258 @@
+// Package api serves read-only views of the pipeline over the HTTP runtime.
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"shared/application/dto"
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/download"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// StatusAPI answers what happened to a report or a download:
+//
+//	GET /downloads/{id}
+//	GET /reports/{id}
+//	GET /reports?provider=&status=&since=&cursor=&limit=
+//
+// Listed reports carry the status of their downloads, without processes.
+type StatusAPI struct {
+	responder
+	repositories ports.Repositories
+}
+
+func NewStatusAPI(repositories ports.Repositories, obs ports.Observability) (*StatusAPI, error) {
+	logger, metrics, err := obs.ComponentsScoped("api.status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return &StatusAPI{
+		responder:    responder{logger: logger, metrics: metrics},
+		repositories: repositories,
+	}, nil
+}
+
+// Register mounts the routes of the API on mux
+func (a *StatusAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /downloads/{id}", a.getDownload)
+	mux.HandleFunc("GET /reports/{id}", a.getReport)
+	mux.HandleFunc("GET /reports", a.listReports)
+}
+
+func (a *StatusAPI) getDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		a.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	dl, err := a.repositories.Download().Get(ctx, id)
+	if err != nil {
+		a.writeLookupError(w, r, err)
+		return
+	}
+
+	proc, err := a.process(ctx, dl.ID)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	a.writeJSON(w, r, dto.NewDownloadView(dl, proc))
+}
+
+func (a *StatusAPI) getReport(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		a.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	report, err := a.repositories.AuditReport().Get(ctx, id)
+	if err != nil {
+		a.writeLookupError(w, r, err)
+		return
+	}
+
+	provider, err := a.repositories.AuditProvider().Get(ctx, report.ProviderID)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	downloads, err := a.repositories.Download().ListByReportID(ctx, report.ID)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	views := make([]dto.DownloadView, 0, len(downloads))
+	for _, dl := range downloads {
+		proc, err := a.process(ctx, dl.ID)
+		if err != nil {
+			a.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		views = append(views, dto.NewDownloadView(dl, proc))
+	}
+
+	a.writeJSON(w, r, dto.NewReportView(report, provider.Slug, views))
+}
+
+func (a *StatusAPI) listReports(w http.ResponseWriter, r *http.Request) {
+	filter, err := reportFilter(r)
+	if err != nil {
+		a.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// One more report than asked tells if there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+
+	ctx := r.Context()
+	reports, err := a.repositories.AuditReport().List(ctx, filter)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	page := dto.ReportPage{Reports: []dto.ReportView{}}
+	if len(reports) > pageSize {
+		reports = reports[:pageSize]
+		page.NextCursor = encodeCursor(reports[len(reports)-1].ID)
+	}
+
+	views, err := reportViews(ctx, a.repositories, reports)
+	if err != nil {
+		a.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	page.Reports = append(page.Reports, views...)
+
+	a.writeJSON(w, r, page)
+}
+
+// reportViews describes reports with their downloads, loaded in one query
+func reportViews(ctx context.Context, repositories ports.Repositories, reports []*entity.AuditReport) ([]dto.ReportView, error) {
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	providers, err := repositories.AuditProvider().List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	slugs := make(map[int64]string, len(providers))
+	for _, provider := range providers {
+		slugs[provider.ID] = provider.Slug
+	}
+
+	reportIDs := make([]int64, 0, len(reports))
+	for _, report := range reports {
+		reportIDs = append(reportIDs, report.ID)
+	}
+	downloads, err := repositories.Download().List(ctx, ports.DownloadFilter{ReportIDs: reportIDs})
+	if err != nil {
+		return nil, err
+	}
+	byReport := make(map[int64][]dto.DownloadView, len(reports))
+	for _, dl := range downloads {
+		byReport[dl.ReportID] = append(byReport[dl.ReportID], dto.NewDownloadView(dl, nil))
+	}
+
+	views := make([]dto.ReportView, 0, len(reports))
+	for _, report := range reports {
+		views = append(views, dto.NewReportView(report, slugs[report.ProviderID], byReport[report.ID]))
+	}
+	return views, nil
+}
+
+// process returns the process of a download, nil when it has none yet
+func (a *StatusAPI) process(ctx context.Context, downloadID int64) (*entity.Process, error) {
+	proc, err := a.repositories.Process().GetByDownloadID(ctx, downloadID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return proc, err
+}
+
+// reportFilter reads the filter of a report listing from the query string
+func reportFilter(r *http.Request) (ports.AuditReportFilter, error) {
+	query := r.URL.Query()
+	filter := ports.AuditReportFilter{
+		ProviderSlug: query.Get("provider"),
+		Status:       download.Status(query.Get("status")),
+		Limit:        defaultPageSize,
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, ErrInvalidParameter("status", query.Get("status"))
+	}
+
+	if since := query.Get("since"); since != "" {
+		parsed, err := parseTime(since)
+		if err != nil {
+			return filter, ErrInvalidParameter("since", since)
+		}
+		filter.Since = parsed
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.BeforeID = id
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			return filter, ErrInvalidParameter("limit", limit)
+		}
+		filter.Limit = parsed
+	}
+	return filter, nil
+}
+
+func pathID(r *http.Request) (int64, error) {
+	value := r.PathValue("id")
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidParameter("id", value)
+	}
+	return id, nil
+}
+
+// Cursors are opaque to clients: the ID of the last report of the page
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
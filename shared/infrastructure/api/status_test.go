@@ -0,0 +1,452 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"shared/application/dto"
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/download"
+	"shared/domain/entity/process"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// store holds the rows the fake repositories serve
+type store struct {
+	providers []*entity.AuditProvider
+	reports   []*entity.AuditReport
+	downloads []*entity.Download
+	processes []*entity.Process
+
+	reportFilters []ports.AuditReportFilter
+	searches      []ports.ReportSearch
+	hits          []*ports.ReportSearchHit
+}
+
+type fakeReports struct {
+	ports.AuditReportRepository
+	store *store
+}
+
+func (f *fakeReports) Get(ctx context.Context, id int64) (*entity.AuditReport, error) {
+	for _, report := range f.store.reports {
+		if report.ID == id {
+			return report, nil
+		}
+	}
+	return nil, fmt.Errorf("audit report %d: %w", id, ports.ErrNotFound)
+}
+
+// List selects reports the way the query does: joined to their provider and
+// to the download of the report itself, newest first
+func (f *fakeReports) List(ctx context.Context, filter ports.AuditReportFilter) ([]*entity.AuditReport, error) {
+	f.store.reportFilters = append(f.store.reportFilters, filter)
+
+	var reports []*entity.AuditReport
+	for _, report := range f.store.reports {
+		if filter.ProviderSlug != "" && f.store.provider(report.ProviderID).Slug != filter.ProviderSlug {
+			continue
+		}
+		if filter.Status != "" && !f.store.hasReportDownload(report.ID, filter.Status) {
+			continue
+		}
+		if !filter.Since.IsZero() && report.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		if filter.BeforeID > 0 && report.ID >= filter.BeforeID {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b *entity.AuditReport) int { return int(b.ID - a.ID) })
+	if filter.Limit > 0 && len(reports) > filter.Limit {
+		reports = reports[:filter.Limit]
+	}
+	return reports, nil
+}
+
+func (f *fakeReports) Search(ctx context.Context, search ports.ReportSearch) ([]*ports.ReportSearchHit, error) {
+	f.store.searches = append(f.store.searches, search)
+	hits := f.store.hits
+	if search.Offset < len(hits) {
+		hits = hits[search.Offset:]
+	} else {
+		hits = nil
+	}
+	if search.Limit > 0 && len(hits) > search.Limit {
+		hits = hits[:search.Limit]
+	}
+	return hits, nil
+}
+
+type fakeProviders struct {
+	ports.AuditProviderRepository
+	store *store
+}
+
+func (f *fakeProviders) Get(ctx context.Context, id int64) (*entity.AuditProvider, error) {
+	if provider := f.store.provider(id); provider != nil {
+		return provider, nil
+	}
+	return nil, ports.ErrNotFound
+}
+
+func (f *fakeProviders) List(ctx context.Context, onlyActive bool) ([]*entity.AuditProvider, error) {
+	return f.store.providers, nil
+}
+
+type fakeDownloads struct {
+	ports.DownloadRepository
+	store *store
+}
+
+func (f *fakeDownloads) Get(ctx context.Context, id int64) (*entity.Download, error) {
+	for _, dl := range f.store.downloads {
+		if dl.ID == id {
+			return dl, nil
+		}
+	}
+	return nil, fmt.Errorf("download %d: %w", id, ports.ErrNotFound)
+}
+
+func (f *fakeDownloads) ListByReportID(ctx context.Context, reportID int64) ([]*entity.Download, error) {
+	return f.List(ctx, ports.DownloadFilter{ReportIDs: []int64{reportID}})
+}
+
+func (f *fakeDownloads) List(ctx context.Context, filter ports.DownloadFilter) ([]*entity.Download, error) {
+	var downloads []*entity.Download
+	for _, dl := range f.store.downloads {
+		if filter.ReportIDs != nil && !slices.Contains(filter.ReportIDs, dl.ReportID) {
+			continue
+		}
+		downloads = append(downloads, dl)
+	}
+	return downloads, nil
+}
+
+type fakeProcesses struct {
+	ports.ProcessRepository
+	store *store
+}
+
+func (f *fakeProcesses) GetByDownloadID(ctx context.Context, downloadID int64) (*entity.Process, error) {
+	for _, proc := range f.store.processes {
+		if proc.DownloadID == downloadID {
+			return proc, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+type fakeRepositories struct {
+	ports.Repositories
+	store *store
+}
+
+func (f *fakeRepositories) AuditReport() ports.AuditReportRepository {
+	return &fakeReports{store: f.store}
+}
+func (f *fakeRepositories) AuditProvider() ports.AuditProviderRepository {
+	return &fakeProviders{store: f.store}
+}
+func (f *fakeRepositories) Download() ports.DownloadRepository {
+	return &fakeDownloads{store: f.store}
+}
+func (f *fakeRepositories) Process() ports.ProcessRepository {
+	return &fakeProcesses{store: f.store}
+}
+
+func (s *store) provider(id int64) *entity.AuditProvider {
+	for _, provider := range s.providers {
+		if provider.ID == id {
+			return provider
+		}
+	}
+	return nil
+}
+
+func (s *store) hasReportDownload(reportID int64, status download.Status) bool {
+	for _, dl := range s.downloads {
+		if dl.ReportID == reportID && dl.Kind == download.ArtifactKindReport && dl.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// newTestStore holds two providers with three reports each, report i
+// created i days after the first of June
+func newTestStore() *store {
+	s := &store{
+		providers: []*entity.AuditProvider{
+			{ID: 1, Slug: "code4rena"},
+			{ID: 2, Slug: "cantina"},
+		},
+	}
+	statuses := []download.Status{download.StatusCompleted, download.StatusFailed, download.StatusPending}
+	for i := int64(1); i <= 6; i++ {
+		s.reports = append(s.reports, &entity.AuditReport{
+			ID:         i,
+			ProviderID: 1 + (i-1)%2,
+			Title:      fmt.Sprintf("Report %d", i),
+			CreatedAt:  time.Date(2025, 6, 1+int(i), 0, 0, 0, 0, time.UTC),
+		})
+		s.downloads = append(s.downloads, &entity.Download{
+			ID:       10 * i,
+			ReportID: i,
+			Kind:     download.ArtifactKindReport,
+			Status:   statuses[(i-1)%3],
+		})
+	}
+	// The findings of report 1 failed while its report completed
+	s.downloads = append(s.downloads, &entity.Download{
+		ID:       11,
+		ReportID: 1,
+		Kind:     download.ArtifactKindFindings,
+		Status:   download.StatusFailed,
+	})
+	s.processes = append(s.processes, &entity.Process{ID: 100, DownloadID: 10, Status: process.StatusCompleted})
+	return s
+}
+
+func newTestMux(t *testing.T, s *store) *http.ServeMux {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	repositories := &fakeRepositories{store: s}
+	statusAPI, err := NewStatusAPI(repositories, obs)
+	require.NoError(t, err)
+	searchAPI, err := NewSearchAPI(repositories, obs)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	statusAPI.Register(mux)
+	searchAPI.Register(mux)
+	return mux
+}
+
+// serve answers target and decodes its JSON body into body
+func serve(t *testing.T, mux *http.ServeMux, target string, body any) int {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), body))
+	return recorder.Code
+}
+
+func reportIDs(page dto.ReportPage) []int64 {
+	ids := make([]int64, 0, len(page.Reports))
+	for _, report := range page.Reports {
+		ids = append(ids, report.ID)
+	}
+	return ids
+}
+
+func TestStatusAPI_GetDownload(t *testing.T) {
+	mux := newTestMux(t, newTestStore())
+
+	var view dto.DownloadView
+	require.Equal(t, http.StatusOK, serve(t, mux, "/downloads/10", &view))
+	assert.Equal(t, int64(10), view.ID)
+	assert.Equal(t, string(download.StatusCompleted), view.Status)
+	if assert.NotNil(t, view.Process) {
+		assert.Equal(t, int64(100), view.Process.ID)
+	}
+
+	var pending dto.DownloadView
+	require.Equal(t, http.StatusOK, serve(t, mux, "/downloads/20", &pending))
+	assert.Nil(t, pending.Process, "no process yet")
+}
+
+func TestStatusAPI_GetReport(t *testing.T) {
+	mux := newTestMux(t, newTestStore())
+
+	var view dto.ReportView
+	require.Equal(t, http.StatusOK, serve(t, mux, "/reports/1", &view))
+	assert.Equal(t, "code4rena", view.Provider)
+	require.Len(t, view.Downloads, 2)
+	assert.Equal(t, string(download.ArtifactKindReport), view.Downloads[0].Kind)
+	assert.NotNil(t, view.Downloads[0].Process)
+	assert.Equal(t, string(download.ArtifactKindFindings), view.Downloads[1].Kind)
+}
+
+func TestStatusAPI_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		status int
+		err    string
+	}{
+		{name: "unknown download", target: "/downloads/99", status: http.StatusNotFound},
+		{name: "unknown report", target: "/reports/99", status: http.StatusNotFound},
+		{name: "download id not a number", target: "/downloads/abc", status: http.StatusBadRequest, err: ErrInvalidParameter("id", "abc").Error()},
+		{name: "report id not positive", target: "/reports/0", status: http.StatusBadRequest, err: ErrInvalidParameter("id", "0").Error()},
+		{name: "bad since", target: "/reports?since=yesterday", status: http.StatusBadRequest, err: ErrInvalidParameter("since", "yesterday").Error()},
+		{name: "bad cursor encoding", target: "/reports?cursor=%21%21", status: http.StatusBadRequest, err: ErrInvalidCursor.Error()},
+		{name: "cursor not an id", target: "/reports?cursor=" + encodeCursor(-4), status: http.StatusBadRequest, err: ErrInvalidCursor.Error()},
+		{name: "bad status", target: "/reports?status=lost", status: http.StatusBadRequest, err: ErrInvalidParameter("status", "lost").Error()},
+		{name: "limit too high", target: "/reports?limit=201", status: http.StatusBadRequest, err: ErrInvalidParameter("limit", "201").Error()},
+		{name: "limit zero", target: "/reports?limit=0", status: http.StatusBadRequest, err: ErrInvalidParameter("limit", "0").Error()},
+	}
+
+	mux := newTestMux(t, newTestStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp ports.RuntimeResponse
+			assert.Equal(t, tt.status, serve(t, mux, tt.target, &resp))
+			assert.False(t, resp.Success)
+			if tt.err != "" {
+				assert.Equal(t, tt.err, resp.Error)
+			}
+		})
+	}
+}
+
+func TestStatusAPI_ListReports(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		filter  ports.AuditReportFilter
+		reports []int64
+	}{
+		{
+			name:    "newest first",
+			target:  "/reports",
+			filter:  ports.AuditReportFilter{Limit: defaultPageSize + 1},
+			reports: []int64{6, 5, 4, 3, 2, 1},
+		},
+		{
+			name:    "provider",
+			target:  "/reports?provider=cantina",
+			filter:  ports.AuditReportFilter{ProviderSlug: "cantina", Limit: defaultPageSize + 1},
+			reports: []int64{6, 4, 2},
+		},
+		{
+			name:    "status of the report download",
+			target:  "/reports?status=failed",
+			filter:  ports.AuditReportFilter{Status: download.StatusFailed, Limit: defaultPageSize + 1},
+			reports: []int64{5, 2},
+		},
+		{
+			name:    "provider and status",
+			target:  "/reports?provider=code4rena&status=completed",
+			filter:  ports.AuditReportFilter{ProviderSlug: "code4rena", Status: download.StatusCompleted, Limit: defaultPageSize + 1},
+			reports: []int64{1},
+		},
+		{
+			name:   "since a date",
+			target: "/reports?since=2025-06-05",
+			filter: ports.AuditReportFilter{
+				Since: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+				Limit: defaultPageSize + 1,
+			},
+			reports: []int64{6, 5, 4},
+		},
+		{
+			name:   "since a time",
+			target: "/reports?since=2025-06-06T12:00:00Z",
+			filter: ports.AuditReportFilter{
+				Since: time.Date(2025, 6, 6, 12, 0, 0, 0, time.UTC),
+				Limit: defaultPageSize + 1,
+			},
+			reports: []int64{6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore()
+			mux := newTestMux(t, s)
+
+			var page dto.ReportPage
+			require.Equal(t, http.StatusOK, serve(t, mux, tt.target, &page))
+			assert.Equal(t, tt.reports, reportIDs(page))
+			assert.Empty(t, page.NextCursor)
+			require.Len(t, s.reportFilters, 1)
+			assert.True(t, tt.filter.Since.Equal(s.reportFilters[0].Since))
+			tt.filter.Since = s.reportFilters[0].Since
+			assert.Equal(t, tt.filter, s.reportFilters[0])
+		})
+	}
+}
+
+func TestStatusAPI_ListReportsPages(t *testing.T) {
+	s := newTestStore()
+	mux := newTestMux(t, s)
+
+	var pages [][]int64
+	target := "/reports?limit=4"
+	for {
+		var page dto.ReportPage
+		require.Equal(t, http.StatusOK, serve(t, mux, target, &page))
+		pages = append(pages, reportIDs(page))
+		if page.NextCursor == "" {
+			break
+		}
+
+		// The cursor is the last report of the page
+		last := page.Reports[len(page.Reports)-1].ID
+		assert.Equal(t, encodeCursor(last), page.NextCursor)
+		target = "/reports?limit=4&cursor=" + page.NextCursor
+	}
+
+	assert.Equal(t, [][]int64{{6, 5, 4, 3}, {2, 1}}, pages)
+	require.Len(t, s.reportFilters, 2)
+	assert.Equal(t, int64(3), s.reportFilters[1].BeforeID)
+}
+
+func TestStatusAPI_ListReportsDownloads(t *testing.T) {
+	mux := newTestMux(t, newTestStore())
+
+	var page dto.ReportPage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/reports?provider=code4rena", &page))
+	require.Equal(t, []int64{5, 3, 1}, reportIDs(page))
+
+	for _, report := range page.Reports {
+		assert.Equal(t, "code4rena", report.Provider)
+		require.NotEmpty(t, report.Downloads)
+		for _, dl := range report.Downloads {
+			assert.Equal(t, report.ID, dl.ReportID)
+			assert.Nil(t, dl.Process, "listed reports carry no processes")
+		}
+	}
+	assert.Len(t, page.Reports[2].Downloads, 2)
+}
+
+func TestStatusAPI_ListReportsEmpty(t *testing.T) {
+	mux := newTestMux(t, newTestStore())
+
+	var page map[string]json.RawMessage
+	require.Equal(t, http.StatusOK, serve(t, mux, "/reports?provider=unknown", &page))
+	assert.JSONEq(t, `[]`, string(page["reports"]))
+	assert.NotContains(t, page, "next_cursor")
+}
+
+func TestDecodeCursor(t *testing.T) {
+	id, err := decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	for _, cursor := range []string{"", "%%", encodeCursor(0), "bm90LWFuLWlk"} {
+		_, err := decodeCursor(cursor)
+		assert.True(t, errors.Is(err, ErrInvalidCursor), cursor)
+	}
+}
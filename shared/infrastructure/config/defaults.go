@@ -13,11 +13,19 @@ func DefaultConfig() *Config {
 		ServiceName: "audit-reports-service",
 		LogLevel:    "info",
 		Version:     "1.0.0",
+		Roles:       []string{"download", "process", "extract"},
 
 		// Component configurations with defaults
 		Adapters:      DefaultAdapterConfig(),
 		HTTP:          DefaultHTTPConfig(),
 		Lambda:        DefaultLambdaConfig(),
+		Schedule:      DefaultScheduleConfig(),
+		Middleware:    DefaultMiddlewareConfig(),
+		Retry:         DefaultRetryConfig(),
+		RateLimit:     DefaultRateLimitConfig(),
+		CrawlPolicy:   DefaultCrawlPolicyConfig(),
+		Content:       DefaultContentConfig(),
+		Downloader:    DefaultDownloaderConfig(),
 		Storage:       DefaultStorageConfig(),
 		Database:      DefaultDatabaseConfig(),
 		Observability: DefaultObservabilityConfig(),
@@ -55,6 +63,79 @@ func DefaultLambdaConfig() LambdaConfig {
 	}
 }
 
+// DefaultScheduleConfig returns sensible defaults for the schedule runtime
+func DefaultScheduleConfig() ScheduleConfig {
+	return ScheduleConfig{
+		Interval:           5 * time.Minute,
+		CrawlInterval:      24 * time.Hour,
+		CrawlLease:         time.Hour,
+		RecheckInterval:    7 * 24 * time.Hour,
+		PendingGracePeriod: 10 * time.Minute,
+		StuckTimeout:       15 * time.Minute,
+		OutboxDelay:        time.Minute,
+		BatchSize:          100,
+	}
+}
+
+// DefaultMiddlewareConfig remembers handled events for an hour
+func DefaultMiddlewareConfig() MiddlewareConfig {
+	return MiddlewareConfig{
+		IdempotencyTTL: time.Hour,
+	}
+}
+
+// DefaultRetryConfig returns sensible defaults for retries of failed work
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		BaseDelay:   time.Minute,
+		Multiplier:  2,
+		Jitter:      0.2,
+		MaxDelay:    time.Hour,
+		MaxAttempts: 3,
+	}
+}
+
+// DefaultRateLimitConfig returns a polite default rate for every host
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Default:   RateLimit{RequestsPerSecond: 1, Burst: 3},
+		Providers: map[string]RateLimit{},
+		MaxWait:   30 * time.Second,
+	}
+}
+
+// DefaultCrawlPolicyConfig honours robots.txt, fetched again once a day
+func DefaultCrawlPolicyConfig() CrawlPolicyConfig {
+	return CrawlPolicyConfig{
+		Enabled:  true,
+		CacheTTL: 24 * time.Hour,
+		MaxWait:  30 * time.Second,
+	}
+}
+
+// DefaultContentConfig stores mismatching files under their detected extension
+func DefaultContentConfig() ContentConfig {
+	return ContentConfig{
+		MismatchPolicy: "correct",
+		ResolveRules:   map[string]string{},
+	}
+}
+
+// DefaultDownloaderConfig accepts files up to 100MB over http and https
+func DefaultDownloaderConfig() DownloaderConfig {
+	return DownloaderConfig{
+		MaxFileSize:           100 * 1024 * 1024,
+		ProviderMaxFileSizes:  map[string]int64{},
+		UserAgent:             "AuditReportDownloader/1.0",
+		Timeout:               30 * time.Second,
+		ConnectTimeout:        10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+		MaxRedirects:          5,
+		AllowedSchemes:        []string{"https", "http"},
+	}
+}
+
 // DefaultStorageConfig returns sensible defaults for storage configuration
 func DefaultStorageConfig() StorageConfig {
 	return StorageConfig{
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -25,6 +26,27 @@ func getInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getInt64 gets environment variable as int64 with default value
+func getInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intVal, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
+// getList gets environment variable as a comma separated list with default value
+func getList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getBool gets environment variable as bool with default value
 func getBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -58,6 +80,72 @@ func getDuration(key, defaultValue string) time.Duration {
 	return 30 * time.Second // Ultimate fallback
 }
 
+// parseKeyValues reads a list like "cantina=pdf_link,sherlock=pdf_link"
+func parseKeyValues(value string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q, expected key=value", entry)
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return values, nil
+}
+
+// parseRateLimits reads rates by key from a list like "cantina=0.5:1,sherlock=2:5",
+// each entry giving requests per second and burst
+func parseRateLimits(value string) (map[string]RateLimit, error) {
+	entries, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]RateLimit, len(entries))
+	for key, rate := range entries {
+		rps, burst, ok := strings.Cut(rate, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected key=rps:burst", key+"="+rate)
+		}
+
+		rpsVal, err := strconv.ParseFloat(rps, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit %q: %w", key+"="+rate, err)
+		}
+		burstVal, err := strconv.Atoi(burst)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit %q: %w", key+"="+rate, err)
+		}
+
+		limits[key] = RateLimit{RequestsPerSecond: rpsVal, Burst: burstVal}
+	}
+	return limits, nil
+}
+
+// parseSizes reads a list like "cantina=52428800,sherlock=209715200" of
+// sizes in bytes
+func parseSizes(value string) (map[string]int64, error) {
+	entries, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64, len(entries))
+	for key, size := range entries {
+		sizeVal, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size %q: %w", key+"="+size, err)
+		}
+		sizes[key] = sizeVal
+	}
+	return sizes, nil
+}
+
 // Environment detection methods
 func (c *Config) IsLocal() bool {
 	env := strings.ToLower(c.Environment)
@@ -2,12 +2,28 @@ package config
 
 // parse reads configuration from environment variables
 func parse() (*Config, error) {
+	providerRateLimits, err := parseRateLimits(getEnv("RATE_LIMIT_PROVIDERS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	resolveRules, err := parseKeyValues(getEnv("CONTENT_RESOLVE_RULES", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	providerMaxFileSizes, err := parseSizes(getEnv("DOWNLOADER_PROVIDER_MAX_FILE_SIZES", ""))
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		// Core
 		Environment: getEnv("ENVIRONMENT", "local"),
 		ServiceName: getEnv("SERVICE_NAME", "my-worker"),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
 		Version:     getEnv("SERVICE_VERSION", "1.0.0"),
+		Roles:       getList("WORKER_ROLES", "download,process,extract"),
 
 		// Adapter selection
 		Adapters: AdapterConfig{
@@ -47,6 +63,70 @@ func parse() (*Config, error) {
 			EnablePartialBatchFailure: getBool("LAMBDA_PARTIAL_BATCH_FAILURE", true),
 		},
 
+		// Schedule Configuration
+		Schedule: ScheduleConfig{
+			Interval:           getDuration("SCHEDULE_INTERVAL", "5m"),
+			CrawlInterval:      getDuration("SCHEDULE_CRAWL_INTERVAL", "24h"),
+			CrawlLease:         getDuration("SCHEDULE_CRAWL_LEASE", "1h"),
+			RecheckInterval:    getDuration("SCHEDULE_RECHECK_INTERVAL", "168h"),
+			PendingGracePeriod: getDuration("SCHEDULE_PENDING_GRACE_PERIOD", "10m"),
+			StuckTimeout:       getDuration("SCHEDULE_STUCK_TIMEOUT", "15m"),
+			OutboxDelay:        getDuration("SCHEDULE_OUTBOX_DELAY", "1m"),
+			BatchSize:          getInt("SCHEDULE_BATCH_SIZE", 100),
+		},
+
+		// Middleware Configuration
+		Middleware: MiddlewareConfig{
+			IdempotencyTTL: getDuration("MIDDLEWARE_IDEMPOTENCY_TTL", "1h"),
+		},
+
+		// Retry Configuration
+		Retry: RetryConfig{
+			BaseDelay:   getDuration("RETRY_BASE_DELAY", "1m"),
+			Multiplier:  getFloat64("RETRY_MULTIPLIER", 2),
+			Jitter:      getFloat64("RETRY_JITTER", 0.2),
+			MaxDelay:    getDuration("RETRY_MAX_DELAY", "1h"),
+			MaxAttempts: getInt("RETRY_MAX_ATTEMPTS", 3),
+		},
+
+		// Rate Limit Configuration
+		RateLimit: RateLimitConfig{
+			Default: RateLimit{
+				RequestsPerSecond: getFloat64("RATE_LIMIT_RPS", 1),
+				Burst:             getInt("RATE_LIMIT_BURST", 3),
+			},
+			Providers: providerRateLimits,
+			MaxWait:   getDuration("RATE_LIMIT_MAX_WAIT", "30s"),
+		},
+
+		// Crawl Policy Configuration
+		CrawlPolicy: CrawlPolicyConfig{
+			Enabled:  getBool("CRAWL_POLICY_ENABLED", true),
+			CacheTTL: getDuration("CRAWL_POLICY_CACHE_TTL", "24h"),
+			MaxWait:  getDuration("CRAWL_POLICY_MAX_WAIT", "30s"),
+		},
+
+		// Content Configuration
+		Content: ContentConfig{
+			MismatchPolicy: getEnv("CONTENT_MISMATCH_POLICY", "correct"),
+			ResolveRules:   resolveRules,
+		},
+
+		// Downloader Configuration, falling back on the HTTP settings
+		Downloader: DownloaderConfig{
+			MaxFileSize:           getInt64("DOWNLOADER_MAX_FILE_SIZE", 100*1024*1024),
+			ProviderMaxFileSizes:  providerMaxFileSizes,
+			UserAgent:             getEnv("DOWNLOADER_USER_AGENT", getEnv("HTTP_USER_AGENT", "AuditReportDownloader/1.0")),
+			Timeout:               getDuration("DOWNLOADER_TIMEOUT", getEnv("HTTP_TIMEOUT", "30s")),
+			ConnectTimeout:        getDuration("DOWNLOADER_CONNECT_TIMEOUT", "10s"),
+			TLSHandshakeTimeout:   getDuration("DOWNLOADER_TLS_HANDSHAKE_TIMEOUT", "10s"),
+			ResponseHeaderTimeout: getDuration("DOWNLOADER_RESPONSE_HEADER_TIMEOUT", "15s"),
+			MaxRedirects:          getInt("DOWNLOADER_MAX_REDIRECTS", 5),
+			AllowedSchemes:        getList("DOWNLOADER_ALLOWED_SCHEMES", "https,http"),
+			ProxyURL:              getEnv("DOWNLOADER_PROXY_URL", ""),
+			AllowPrivateNetworks:  getBool("DOWNLOADER_ALLOW_PRIVATE_NETWORKS", false),
+		},
+
 		// Storage Configuration
 		Storage: StorageConfig{
 			BucketOrPath: getEnv("STORAGE_BUCKET_OR_PATH", ""),
@@ -12,12 +12,22 @@ type Config struct {
 	LogLevel    string
 	Version     string
 
+	// Roles are the workers the local binary runs in one process
+	Roles []string
+
 	// Adapter selection
 	Adapters AdapterConfig
 
 	// Component configurations
 	HTTP          HTTPConfig
 	Lambda        LambdaConfig
+	Schedule      ScheduleConfig
+	Middleware    MiddlewareConfig
+	Retry         RetryConfig
+	RateLimit     RateLimitConfig
+	CrawlPolicy   CrawlPolicyConfig
+	Content       ContentConfig
+	Downloader    DownloaderConfig
 	Storage       StorageConfig
 	Database      DatabaseConfig
 	Observability ObservabilityConfig
@@ -26,7 +36,7 @@ type Config struct {
 
 // AdapterConfig specifies which implementations to use
 type AdapterConfig struct {
-	Runtime  string // "lambda", "http", "rabbitmq"
+	Runtime  string // "lambda", "http", "rabbitmq", "schedule"
 	Storage  string // "s3", "filesystem"
 	Database string // "postgres"
 	Logger   string // "cloudwatch", "stdout"
@@ -61,6 +71,77 @@ type LambdaConfig struct {
 	EnablePartialBatchFailure bool
 }
 
+// ScheduleConfig holds configuration for the periodic schedule runtime and
+// the delays the orchestrator decides that work is due again with
+type ScheduleConfig struct {
+	Interval           time.Duration // Time between two ticks
+	CrawlInterval      time.Duration // Time between two visits of a source index
+	CrawlLease         time.Duration // How long a scheduled crawl may take before it is scheduled again
+	RecheckInterval    time.Duration // Time between two revalidations of a downloaded file
+	PendingGracePeriod time.Duration // How long a pending row may wait for its event before it is published again
+	StuckTimeout       time.Duration // How long work may stay in progress before its worker is presumed dead
+	OutboxDelay        time.Duration // Age of an outbox message before the orchestrator relays it
+	BatchSize          int           // Rows handled per step and tick
+}
+
+// MiddlewareConfig holds the middleware every runtime wraps the handler with
+type MiddlewareConfig struct {
+	IdempotencyTTL time.Duration // How long a handled event is remembered, zero disables it
+}
+
+// RetryConfig holds the backoff applied between attempts of failed work
+type RetryConfig struct {
+	BaseDelay   time.Duration // Delay after the first failed attempt
+	Multiplier  float64       // Growth of the delay after each further failure
+	Jitter      float64       // Fraction of the delay randomly added or removed
+	MaxDelay    time.Duration // Cap of the delay before jitter
+	MaxAttempts int           // Attempts given to new downloads
+}
+
+// RateLimitConfig holds the request rates allowed to each remote host
+type RateLimitConfig struct {
+	Default   RateLimit            // Rate of hosts of providers without their own
+	Providers map[string]RateLimit // Rates by provider slug
+	MaxWait   time.Duration        // Longest wait for a request slot before the work is postponed
+}
+
+// RateLimit is a token bucket: RequestsPerSecond refill it, Burst is its size
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// CrawlPolicyConfig holds how robots.txt is honoured on every outbound fetch
+type CrawlPolicyConfig struct {
+	Enabled  bool
+	CacheTTL time.Duration // How long a robots.txt is kept before it is fetched again
+	MaxWait  time.Duration // Longest wait for the crawl delay of a host before the work is postponed
+}
+
+// ContentConfig holds the validation of downloaded content
+type ContentConfig struct {
+	MismatchPolicy string            // "reject", "warn" or "correct" a type contradicting the extension
+	ResolveRules   map[string]string // By provider slug, how to find the artifact behind a report page
+}
+
+// DownloaderConfig holds the limits and HTTP client settings of the downloader
+type DownloaderConfig struct {
+	MaxFileSize          int64            // Largest file accepted, in bytes
+	ProviderMaxFileSizes map[string]int64 // Overrides of MaxFileSize by provider slug
+
+	UserAgent             string
+	Timeout               time.Duration // Whole request, body included
+	ConnectTimeout        time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	MaxRedirects          int
+	AllowedSchemes        []string // Schemes of the URLs fetched, redirects included
+	ProxyURL              string   // Empty uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// AllowPrivateNetworks lets URLs reach private, loopback and link-local
+	// addresses; only meant for local development
+	AllowPrivateNetworks bool
+}
+
 type StorageConfig struct {
 	// Common fields for all storage types
 	BucketOrPath string
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 )
 
 // Validate validates the entire configuration
@@ -24,6 +26,41 @@ func (c *Config) Validate() error {
 		errors = append(errors, err.Error())
 	}
 
+	// Validate middleware
+	if err := c.Middleware.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate retry policy
+	if err := c.Retry.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate rate limits
+	if err := c.RateLimit.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate crawl policy
+	if err := c.CrawlPolicy.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate content validation
+	if err := c.Content.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate downloader limits and HTTP client
+	if err := c.Downloader.Validate(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate the delays of the orchestrator, against the downloads they wait for
+	if err := c.Schedule.ValidatePolicy(c.Downloader); err != nil {
+		errors = append(errors, err.Error())
+	}
+
 	// Validate storage
 	if err := c.Storage.Validate(c.Adapters); err != nil {
 		errors = append(errors, err.Error())
@@ -63,9 +100,9 @@ func (c *Config) Validate() error {
 
 // Validate validates adapter configuration
 func (a *AdapterConfig) Validate() error {
-	validHandlers := map[string]bool{"lambda": true, "http": true, "rabbitmq": true}
+	validHandlers := map[string]bool{"lambda": true, "http": true, "rabbitmq": true, "schedule": true}
 	if !validHandlers[a.Runtime] {
-		return fmt.Errorf("invalid handler adapter: %s (must be lambda, http, or rabbitmq)", a.Runtime)
+		return fmt.Errorf("invalid handler adapter: %s (must be lambda, http, rabbitmq, or schedule)", a.Runtime)
 	}
 
 	validStorage := map[string]bool{"s3": true, "filesystem": true}
@@ -100,6 +137,180 @@ func (h *HTTPConfig) Validate() error {
 	return nil
 }
 
+// Validate validates Schedule configuration
+func (s *ScheduleConfig) Validate() error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("SCHEDULE_INTERVAL must be positive")
+	}
+	return nil
+}
+
+// ValidatePolicy validates the delays of the orchestrator. Work in progress
+// longer than StuckTimeout is taken back from its worker, so StuckTimeout
+// must exceed the time a download may legitimately take.
+func (s *ScheduleConfig) ValidatePolicy(downloader DownloaderConfig) error {
+	delays := map[string]time.Duration{
+		"SCHEDULE_CRAWL_INTERVAL":       s.CrawlInterval,
+		"SCHEDULE_CRAWL_LEASE":          s.CrawlLease,
+		"SCHEDULE_RECHECK_INTERVAL":     s.RecheckInterval,
+		"SCHEDULE_PENDING_GRACE_PERIOD": s.PendingGracePeriod,
+		"SCHEDULE_STUCK_TIMEOUT":        s.StuckTimeout,
+		"SCHEDULE_OUTBOX_DELAY":         s.OutboxDelay,
+	}
+	for name, delay := range delays {
+		if delay <= 0 {
+			return fmt.Errorf("%s must be positive", name)
+		}
+	}
+
+	if s.StuckTimeout <= downloader.Timeout {
+		return fmt.Errorf("SCHEDULE_STUCK_TIMEOUT (%s) must exceed DOWNLOADER_TIMEOUT (%s)", s.StuckTimeout, downloader.Timeout)
+	}
+	if s.BatchSize < 1 {
+		return fmt.Errorf("SCHEDULE_BATCH_SIZE must be at least 1")
+	}
+	return nil
+}
+
+// Validate validates Middleware configuration
+func (m *MiddlewareConfig) Validate() error {
+	if m.IdempotencyTTL < 0 {
+		return fmt.Errorf("MIDDLEWARE_IDEMPOTENCY_TTL cannot be negative")
+	}
+	return nil
+}
+
+// Validate validates Retry configuration
+func (r *RetryConfig) Validate() error {
+	if r.BaseDelay <= 0 {
+		return fmt.Errorf("RETRY_BASE_DELAY must be positive")
+	}
+	if r.Multiplier < 1 {
+		return fmt.Errorf("RETRY_MULTIPLIER must be at least 1")
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("RETRY_JITTER must be between 0 and 1")
+	}
+	if r.MaxDelay < r.BaseDelay {
+		return fmt.Errorf("RETRY_MAX_DELAY cannot be lower than RETRY_BASE_DELAY")
+	}
+	if r.MaxAttempts < 1 {
+		return fmt.Errorf("RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+	return nil
+}
+
+// Validate validates RateLimit configuration
+func (r *RateLimitConfig) Validate() error {
+	if err := r.Default.Validate(); err != nil {
+		return fmt.Errorf("RATE_LIMIT_RPS and RATE_LIMIT_BURST: %w", err)
+	}
+	for slug, limit := range r.Providers {
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("RATE_LIMIT_PROVIDERS %s: %w", slug, err)
+		}
+	}
+	if r.MaxWait < 0 {
+		return fmt.Errorf("RATE_LIMIT_MAX_WAIT cannot be negative")
+	}
+	return nil
+}
+
+// Validate validates a single rate
+func (r RateLimit) Validate() error {
+	if r.RequestsPerSecond <= 0 {
+		return fmt.Errorf("requests per second must be positive")
+	}
+	if r.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1")
+	}
+	return nil
+}
+
+// Validate validates CrawlPolicy configuration
+func (c *CrawlPolicyConfig) Validate() error {
+	if c.CacheTTL <= 0 {
+		return fmt.Errorf("CRAWL_POLICY_CACHE_TTL must be positive")
+	}
+	if c.MaxWait < 0 {
+		return fmt.Errorf("CRAWL_POLICY_MAX_WAIT cannot be negative")
+	}
+	return nil
+}
+
+// Validate validates Content configuration
+func (c *ContentConfig) Validate() error {
+	validPolicies := map[string]bool{"reject": true, "warn": true, "correct": true}
+	if !validPolicies[c.MismatchPolicy] {
+		return fmt.Errorf("invalid CONTENT_MISMATCH_POLICY: %s (must be reject, warn, or correct)", c.MismatchPolicy)
+	}
+
+	validRules := map[string]bool{"pdf_link": true, "github_markdown": true}
+	for slug, rule := range c.ResolveRules {
+		if !validRules[rule] {
+			return fmt.Errorf("invalid CONTENT_RESOLVE_RULES rule for %s: %s (must be pdf_link or github_markdown)", slug, rule)
+		}
+	}
+	return nil
+}
+
+// Validate validates Downloader configuration
+func (d *DownloaderConfig) Validate() error {
+	if d.MaxFileSize <= 0 {
+		return fmt.Errorf("DOWNLOADER_MAX_FILE_SIZE must be positive")
+	}
+	for slug, size := range d.ProviderMaxFileSizes {
+		if size <= 0 {
+			return fmt.Errorf("DOWNLOADER_PROVIDER_MAX_FILE_SIZES %s: size must be positive", slug)
+		}
+	}
+	if d.UserAgent == "" {
+		return fmt.Errorf("DOWNLOADER_USER_AGENT is required")
+	}
+
+	timeouts := map[string]time.Duration{
+		"DOWNLOADER_TIMEOUT":                 d.Timeout,
+		"DOWNLOADER_CONNECT_TIMEOUT":         d.ConnectTimeout,
+		"DOWNLOADER_TLS_HANDSHAKE_TIMEOUT":   d.TLSHandshakeTimeout,
+		"DOWNLOADER_RESPONSE_HEADER_TIMEOUT": d.ResponseHeaderTimeout,
+	}
+	for name, timeout := range timeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("%s must be positive", name)
+		}
+	}
+
+	if d.MaxRedirects < 0 {
+		return fmt.Errorf("DOWNLOADER_MAX_REDIRECTS cannot be negative")
+	}
+
+	if len(d.AllowedSchemes) == 0 {
+		return fmt.Errorf("DOWNLOADER_ALLOWED_SCHEMES cannot be empty")
+	}
+	validSchemes := map[string]bool{"http": true, "https": true}
+	for _, scheme := range d.AllowedSchemes {
+		if !validSchemes[scheme] {
+			return fmt.Errorf("invalid DOWNLOADER_ALLOWED_SCHEMES scheme: %s (must be http or https)", scheme)
+		}
+	}
+
+	if d.ProxyURL != "" {
+		proxy, err := url.Parse(d.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return fmt.Errorf("invalid DOWNLOADER_PROXY_URL: %s", d.ProxyURL)
+		}
+	}
+	return nil
+}
+
+// MaxFileSizeFor returns the largest file accepted from the provider
+func (d *DownloaderConfig) MaxFileSizeFor(providerSlug string) int64 {
+	if size, ok := d.ProviderMaxFileSizes[providerSlug]; ok {
+		return size
+	}
+	return d.MaxFileSize
+}
+
 // Validate validates Lambda configuration
 func (l *LambdaConfig) Validate() error {
 	if l.Timeout <= 0 {
@@ -230,6 +441,9 @@ func (c *Config) validateRuntime() error {
 	case "lambda":
 		return c.Lambda.Validate()
 
+	case "schedule":
+		return c.Schedule.Validate()
+
 	case "rabbitmq":
 		// RabbitMQ runtime needs URL and appropriate queue name
 		if c.Queue.RabbitMQ.URL == "" {
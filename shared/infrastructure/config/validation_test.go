@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleConfig_ValidatePolicy(t *testing.T) {
+	downloader := DefaultDownloaderConfig()
+	downloader.Timeout = 5 * time.Minute
+
+	tests := []struct {
+		name   string
+		modify func(s *ScheduleConfig)
+		err    string
+	}{
+		{name: "defaults", modify: func(s *ScheduleConfig) {}},
+		{
+			name:   "stuck timeout equal to the download timeout",
+			modify: func(s *ScheduleConfig) { s.StuckTimeout = 5 * time.Minute },
+			err:    "SCHEDULE_STUCK_TIMEOUT (5m0s) must exceed DOWNLOADER_TIMEOUT (5m0s)",
+		},
+		{
+			name:   "stuck timeout below the download timeout",
+			modify: func(s *ScheduleConfig) { s.StuckTimeout = time.Minute },
+			err:    "SCHEDULE_STUCK_TIMEOUT (1m0s) must exceed DOWNLOADER_TIMEOUT (5m0s)",
+		},
+		{
+			name:   "no crawl lease",
+			modify: func(s *ScheduleConfig) { s.CrawlLease = 0 },
+			err:    "SCHEDULE_CRAWL_LEASE must be positive",
+		},
+		{
+			name:   "no batch",
+			modify: func(s *ScheduleConfig) { s.BatchSize = 0 },
+			err:    "SCHEDULE_BATCH_SIZE must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := DefaultScheduleConfig()
+			tt.modify(&schedule)
+
+			err := schedule.ValidatePolicy(downloader)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
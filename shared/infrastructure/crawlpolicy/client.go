@@ -0,0 +1,288 @@
+// Package crawlpolicy keeps outbound fetches within the crawl policies of the
+// sites we visit: their robots.txt, and the terms of their provider.
+package crawlpolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"shared/application/ports"
+	"shared/domain/entity/auditprovider"
+	"shared/infrastructure/config"
+)
+
+const (
+	// maxRobotsSize is what is read of a robots.txt, RFC 9309 asks for 500KiB
+	maxRobotsSize = 512 * 1024
+
+	// staleTTL is how long a robots.txt past its TTL is still used while the
+	// host fails to serve it again
+	staleTTL = 10 * time.Minute
+
+	// providerTTL is how long the crawl policy of a provider is cached
+	providerTTL = 5 * time.Minute
+)
+
+// Client is a ports.HTTPClient sending a request only when the robots.txt of
+// its host, or the terms of the provider ctx is tagged with, allow it, and
+// not before the crawl delay asked by the host is over.
+type Client struct {
+	next      ports.HTTPClient
+	providers ports.AuditProviderRepository
+	config    config.CrawlPolicyConfig
+	logger    ports.Logger
+	metrics   ports.Metrics
+
+	mu       sync.Mutex
+	robots   map[string]*cachedRobots // by origin and product token
+	policies map[string]*cachedPolicy // by provider slug
+	nextSlot map[string]time.Time     // by host, earliest time of the next request
+}
+
+type cachedRobots struct {
+	robots  *Robots
+	expires time.Time
+}
+
+// cachedPolicy is the crawl policy of a provider; hasDelay tells if its
+// delay replaces the one of robots.txt
+type cachedPolicy struct {
+	policy   auditprovider.CrawlPolicy
+	delay    time.Duration
+	hasDelay bool
+	expires  time.Time
+}
+
+// defaultPolicy applies to requests of no known provider
+var defaultPolicy = &cachedPolicy{policy: auditprovider.CrawlPolicyRobots}
+
+func NewClient(next ports.HTTPClient, providers ports.AuditProviderRepository, cfg config.CrawlPolicyConfig, obs ports.Observability) (*Client, error) {
+	logger, metrics, err := obs.ComponentsScoped("crawlpolicy.client")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return &Client{
+		next:      next,
+		providers: providers,
+		config:    cfg,
+		logger:    logger,
+		metrics:   metrics,
+		robots:    make(map[string]*cachedRobots),
+		policies:  make(map[string]*cachedPolicy),
+		nextSlot:  make(map[string]time.Time),
+	}, nil
+}
+
+// Do sends req once the crawl policy allows it. A disallowed URL fails with
+// ports.ErrDisallowed, a crawl delay longer than MaxWait with a
+// *ports.CrawlDelayError, and a robots.txt that cannot be read with
+// ErrRobotsUnavailable.
+//
+// Every redirect is checked the same way, by the next client running
+// ports.CheckRedirect; a response redirected without that check is refused
+// with ErrUncheckedRedirect.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if !c.config.Enabled {
+		return c.next.Do(req)
+	}
+
+	if err := c.check(req); err != nil {
+		return nil, err
+	}
+
+	// Redirects of one request are followed one after the other
+	checked := req.URL.String()
+	ctx := ports.WithRedirectCheck(req.Context(), func(redirect *http.Request) error {
+		if err := c.check(redirect); err != nil {
+			return err
+		}
+		checked = redirect.URL.String()
+		return nil
+	})
+
+	resp, err := c.next.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Request != nil && resp.Request.URL.String() != checked {
+		resp.Body.Close()
+		c.metrics.IncrementCounter("crawl_policy.disallowed", map[string]string{"reason": "unchecked_redirect"})
+		return nil, ErrUncheckedRedirect(resp.Request.URL)
+	}
+	return resp, nil
+}
+
+// check waits until the crawl policy lets req be sent, or returns why it
+// cannot be
+func (c *Client) check(req *http.Request) error {
+	ctx := req.Context()
+	slug := ports.ProviderSlug(ctx)
+	policy := c.policy(ctx, slug)
+
+	delay := policy.delay
+	switch policy.policy {
+	case auditprovider.CrawlPolicyDeny:
+		c.metrics.IncrementCounter("crawl_policy.disallowed", map[string]string{"reason": "provider"})
+		return ErrDisallowedByProvider(slug)
+	case auditprovider.CrawlPolicyAllow:
+		// An agreement with the provider supersedes its robots.txt
+	default:
+		robots, err := c.robotsFor(ctx, req)
+		if err != nil {
+			return err
+		}
+		if !robots.Allowed(req.URL.RequestURI()) {
+			c.metrics.IncrementCounter("crawl_policy.disallowed", map[string]string{"reason": "robots"})
+			return ErrDisallowedByRobots(req.URL)
+		}
+		if !policy.hasDelay {
+			delay = robots.CrawlDelay()
+		}
+	}
+
+	return c.wait(ctx, req.URL.Host, delay)
+}
+
+// policy returns the crawl policy of the provider. Lookup failures fall back
+// on robots.txt rather than failing the request.
+func (c *Client) policy(ctx context.Context, slug string) *cachedPolicy {
+	if slug == "" || c.providers == nil {
+		return defaultPolicy
+	}
+
+	c.mu.Lock()
+	cached, ok := c.policies[slug]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached
+	}
+
+	provider, err := c.providers.GetBySlug(ctx, slug)
+	if err != nil {
+		c.logger.Error("Failed to get provider crawl policy",
+			"provider", slug,
+			"error", err.Error())
+		return defaultPolicy
+	}
+
+	cached = &cachedPolicy{
+		policy:  provider.CrawlPolicy,
+		expires: time.Now().Add(providerTTL),
+	}
+	cached.delay, cached.hasDelay = provider.CrawlDelay()
+
+	c.mu.Lock()
+	c.policies[slug] = cached
+	c.mu.Unlock()
+	return cached
+}
+
+// robotsFor returns the rules of the host of req for its user agent, from
+// the cache while fresh. A host failing to serve its robots.txt again keeps
+// the previous one for a while.
+func (c *Client) robotsFor(ctx context.Context, req *http.Request) (*Robots, error) {
+	userAgent := req.UserAgent()
+	origin := req.URL.Scheme + "://" + req.URL.Host
+	key := origin + " " + productToken(userAgent)
+
+	c.mu.Lock()
+	cached, ok := c.robots[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.robots, nil
+	}
+
+	robots, err := c.fetchRobots(ctx, origin, userAgent)
+	ttl := c.config.CacheTTL
+	if err != nil {
+		c.metrics.IncrementCounter("crawl_policy.robots_fetched", map[string]string{"result": "error"})
+		if !ok {
+			return nil, ErrFetchRobots(req.URL.Host, err)
+		}
+		c.logger.Error("Failed to refresh robots.txt, keeping the previous one",
+			"host", req.URL.Host,
+			"error", err.Error())
+		robots, ttl = cached.robots, staleTTL
+	} else {
+		c.metrics.IncrementCounter("crawl_policy.robots_fetched", map[string]string{"result": "ok"})
+	}
+
+	c.mu.Lock()
+	c.robots[key] = &cachedRobots{robots: robots, expires: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return robots, nil
+}
+
+// fetchRobots reads the robots.txt of origin. Client errors mean there is
+// none, which allows everything; server errors and 429 leave the host
+// unreachable, which allows nothing.
+func (c *Client) fetchRobots(ctx context.Context, origin, userAgent string) (*Robots, error) {
+	// robots.txt is always allowed, wherever it redirects
+	ctx = ports.WithRedirectCheck(ctx, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+		if err != nil {
+			return nil, err
+		}
+		return ParseRobots(body, userAgent), nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("unexpected HTTP status code: %d", resp.StatusCode)
+	default:
+		// 4xx, and redirects the next client left unfollowed
+		return allowAll, nil
+	}
+}
+
+// wait holds the request until the crawl delay of host since the previous
+// one is over, and books the slot after it
+func (c *Client) wait(ctx context.Context, host string, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	slot := now
+	if next, ok := c.nextSlot[host]; ok && next.After(now) {
+		slot = next
+	}
+	remaining := slot.Sub(now)
+	if remaining > c.config.MaxWait {
+		c.mu.Unlock()
+		return &ports.CrawlDelayError{RetryAfter: remaining}
+	}
+	c.nextSlot[host] = slot.Add(delay)
+	c.mu.Unlock()
+
+	if remaining == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,323 @@
+package crawlpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/auditprovider"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserAgent = "AuditReportDownloader/1.0"
+
+// fakeProviders serves the crawl policy of providers by slug
+type fakeProviders struct {
+	ports.AuditProviderRepository
+	providers map[string]*entity.AuditProvider
+}
+
+func (f *fakeProviders) GetBySlug(ctx context.Context, slug string) (*entity.AuditProvider, error) {
+	provider, ok := f.providers[slug]
+	if !ok {
+		return nil, errors.New("provider not found")
+	}
+	return provider, nil
+}
+
+// site is an httptest server answering robots.txt with a status and body,
+// and counting the other requests it serves
+type site struct {
+	*httptest.Server
+	robotsStatus int
+	robotsBody   string
+	served       atomic.Int32
+}
+
+func newSite(t *testing.T, robotsStatus int, robotsBody string, routes map[string]string) *site {
+	t.Helper()
+	s := &site{robotsStatus: robotsStatus, robotsBody: robotsBody}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(s.robotsStatus)
+			fmt.Fprint(w, s.robotsBody)
+			return
+		}
+		s.served.Add(1)
+		if location, ok := routes[r.URL.Path]; ok {
+			http.Redirect(w, r, location, http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "report")
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T, next ports.HTTPClient, providers ports.AuditProviderRepository, maxWait time.Duration) *Client {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	client, err := NewClient(next, providers, config.CrawlPolicyConfig{
+		Enabled:  true,
+		CacheTTL: time.Minute,
+		MaxWait:  maxWait,
+	}, obs)
+	require.NoError(t, err)
+	return client
+}
+
+// checkingClient follows redirects through ports.CheckRedirect, as the
+// clients wrapped by the crawl policy do
+func checkingClient() *http.Client {
+	return &http.Client{CheckRedirect: ports.CheckRedirect}
+}
+
+func get(t *testing.T, client *Client, ctx context.Context, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", testUserAgent)
+	resp, err := client.Do(req)
+	if resp != nil {
+		t.Cleanup(func() { resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func TestClient_FetchRobots(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{name: "served", status: http.StatusOK, body: "User-agent: *\nAllow: /\n"},
+		{name: "not found allows all", status: http.StatusNotFound, body: "User-agent: *\nDisallow: /\n"},
+		{name: "forbidden allows all", status: http.StatusForbidden},
+		{name: "server error", status: http.StatusInternalServerError, err: ErrRobotsUnavailable},
+		{name: "unavailable", status: http.StatusServiceUnavailable, err: ErrRobotsUnavailable},
+		{name: "too many requests", status: http.StatusTooManyRequests, err: ErrRobotsUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := newSite(t, tt.status, tt.body, nil)
+			client := newTestClient(t, checkingClient(), nil, time.Second)
+
+			resp, err := get(t, client, context.Background(), site.URL+"/reports/1")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Zero(t, site.served.Load(), "nothing is fetched from an unreachable host")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.EqualValues(t, 1, site.served.Load())
+		})
+	}
+}
+
+func TestClient_KeepsStaleRobots(t *testing.T) {
+	site := newSite(t, http.StatusOK, "User-agent: *\nDisallow: /private\n", nil)
+	client := newTestClient(t, checkingClient(), nil, time.Second)
+
+	_, err := get(t, client, context.Background(), site.URL+"/reports/1")
+	require.NoError(t, err)
+
+	// Expire the cached robots.txt, and fail to serve it again
+	for _, cached := range client.robots {
+		cached.expires = time.Now().Add(-time.Second)
+	}
+	site.robotsStatus = http.StatusInternalServerError
+
+	_, err = get(t, client, context.Background(), site.URL+"/reports/2")
+	assert.NoError(t, err)
+	_, err = get(t, client, context.Background(), site.URL+"/private/1")
+	assert.ErrorIs(t, err, ports.ErrDisallowed)
+}
+
+func TestClient_Disallowed(t *testing.T) {
+	site := newSite(t, http.StatusOK, "User-agent: *\nDisallow: /private\n", nil)
+	providers := &fakeProviders{providers: map[string]*entity.AuditProvider{
+		"denied":  {Slug: "denied", CrawlPolicy: auditprovider.CrawlPolicyDeny},
+		"allowed": {Slug: "allowed", CrawlPolicy: auditprovider.CrawlPolicyAllow},
+	}}
+	client := newTestClient(t, checkingClient(), providers, time.Second)
+
+	tests := []struct {
+		name     string
+		provider string
+		path     string
+		err      error
+	}{
+		{name: "allowed by robots.txt", path: "/reports/1"},
+		{name: "disallowed by robots.txt", path: "/private/1", err: ports.ErrDisallowed},
+		{name: "denied by the provider", provider: "denied", path: "/reports/1", err: ports.ErrDisallowed},
+		{name: "agreement with the provider", provider: "allowed", path: "/private/1"},
+		{name: "unknown provider follows robots.txt", provider: "unknown", path: "/private/1", err: ports.ErrDisallowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ports.WithProviderSlug(context.Background(), tt.provider)
+			_, err := get(t, client, ctx, site.URL+tt.path)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_ChecksRedirects(t *testing.T) {
+	blocked := newSite(t, http.StatusOK, "User-agent: *\nDisallow: /\n", nil)
+	open := newSite(t, http.StatusOK, "", nil)
+	site := newSite(t, http.StatusOK, "User-agent: *\nDisallow: /private\n", map[string]string{
+		"/to-private": "/private/1",
+		"/to-blocked": blocked.URL + "/reports/1",
+		"/to-open":    open.URL + "/reports/1",
+		"/hop":        "/to-private",
+	})
+	client := newTestClient(t, checkingClient(), nil, time.Second)
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{name: "to an allowed host", path: "/to-open"},
+		{name: "to a disallowed path", path: "/to-private", err: ports.ErrDisallowed},
+		{name: "to a disallowed host", path: "/to-blocked", err: ports.ErrDisallowed},
+		{name: "second hop disallowed", path: "/hop", err: ports.ErrDisallowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := get(t, client, context.Background(), site.URL+tt.path)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+	assert.Zero(t, blocked.served.Load())
+}
+
+// doFunc is a ports.HTTPClient running a function
+type doFunc func(req *http.Request) (*http.Response, error)
+
+func (f doFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestClient_ProviderDeniesRedirect(t *testing.T) {
+	open := newSite(t, http.StatusOK, "", nil)
+	site := newSite(t, http.StatusOK, "", map[string]string{
+		"/to-open": open.URL + "/reports/1",
+	})
+	providers := &fakeProviders{providers: map[string]*entity.AuditProvider{
+		"provider": {Slug: "provider", CrawlPolicy: auditprovider.CrawlPolicyRobots},
+	}}
+	client := newTestClient(t, nil, providers, time.Second)
+
+	// The terms of the provider change once the first request is sent
+	client.next = doFunc(func(req *http.Request) (*http.Response, error) {
+		client.mu.Lock()
+		client.policies["provider"] = &cachedPolicy{
+			policy:  auditprovider.CrawlPolicyDeny,
+			expires: time.Now().Add(time.Minute),
+		}
+		client.mu.Unlock()
+		return checkingClient().Do(req)
+	})
+
+	ctx := ports.WithProviderSlug(context.Background(), "provider")
+	_, err := get(t, client, ctx, site.URL+"/to-open")
+	assert.ErrorIs(t, err, ports.ErrDisallowed)
+	assert.EqualValues(t, 1, site.served.Load())
+	assert.Zero(t, open.served.Load())
+}
+
+func TestClient_UncheckedRedirect(t *testing.T) {
+	open := newSite(t, http.StatusOK, "", nil)
+	site := newSite(t, http.StatusOK, "", map[string]string{
+		"/to-open": open.URL + "/reports/1",
+	})
+	client := newTestClient(t, &http.Client{}, nil, time.Second)
+
+	_, err := get(t, client, context.Background(), site.URL+"/to-open")
+	assert.ErrorIs(t, err, ports.ErrDisallowed)
+}
+
+func TestClient_Wait(t *testing.T) {
+	t.Run("no delay", func(t *testing.T) {
+		client := newTestClient(t, nil, nil, 0)
+		require.NoError(t, client.wait(context.Background(), "host", 0))
+		require.NoError(t, client.wait(context.Background(), "host", 0))
+	})
+
+	t.Run("spaces requests by the delay", func(t *testing.T) {
+		const delay = 50 * time.Millisecond
+		client := newTestClient(t, nil, nil, time.Second)
+
+		start := time.Now()
+		for range 3 {
+			require.NoError(t, client.wait(context.Background(), "host", delay))
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 2*delay)
+
+		// Other hosts keep their own slots
+		start = time.Now()
+		require.NoError(t, client.wait(context.Background(), "other", delay))
+		assert.Less(t, time.Since(start), delay)
+	})
+
+	t.Run("delay past max wait", func(t *testing.T) {
+		client := newTestClient(t, nil, nil, 10*time.Millisecond)
+		require.NoError(t, client.wait(context.Background(), "host", time.Minute))
+
+		err := client.wait(context.Background(), "host", time.Minute)
+		var delayErr *ports.CrawlDelayError
+		require.ErrorAs(t, err, &delayErr)
+		assert.Greater(t, delayErr.RetryAfter, 59*time.Second)
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		client := newTestClient(t, nil, nil, time.Minute)
+		require.NoError(t, client.wait(context.Background(), "host", time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, client.wait(ctx, "host", time.Minute), context.DeadlineExceeded)
+	})
+}
+
+func TestClient_CrawlDelayOfRobots(t *testing.T) {
+	site := newSite(t, http.StatusOK, "User-agent: *\nCrawl-delay: 60\n", nil)
+	client := newTestClient(t, checkingClient(), nil, time.Second)
+
+	_, err := get(t, client, context.Background(), site.URL+"/reports/1")
+	require.NoError(t, err)
+
+	_, err = get(t, client, context.Background(), site.URL+"/reports/2")
+	var delayErr *ports.CrawlDelayError
+	require.ErrorAs(t, err, &delayErr)
+	assert.EqualValues(t, 1, site.served.Load())
+}
@@ -0,0 +1,31 @@
+package crawlpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"shared/application/ports"
+)
+
+// ErrRobotsUnavailable is returned while the robots.txt of a host cannot be
+// read; nothing is fetched from the host until it can
+var ErrRobotsUnavailable = errors.New("robots.txt unavailable")
+
+func ErrDisallowedByRobots(u *url.URL) error {
+	return fmt.Errorf("%w: robots.txt of %s disallows %s", ports.ErrDisallowed, u.Host, u.RequestURI())
+}
+
+func ErrDisallowedByProvider(slug string) error {
+	return fmt.Errorf("%w: terms of %s forbid automated fetching", ports.ErrDisallowed, slug)
+}
+
+func ErrFetchRobots(host string, err error) error {
+	return fmt.Errorf("%w: %s: %w", ErrRobotsUnavailable, host, err)
+}
+
+// ErrUncheckedRedirect is returned when the next client followed a redirect
+// to u without running ports.CheckRedirect
+func ErrUncheckedRedirect(u *url.URL) error {
+	return fmt.Errorf("%w: redirect to %s was not checked", ports.ErrDisallowed, u.Redacted())
+}
@@ -0,0 +1,160 @@
+package crawlpolicy
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Robots holds the rules of a robots.txt that apply to one user agent, as
+// specified by RFC 9309
+type Robots struct {
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// group is a run of user-agent lines followed by their rules
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+// allowAll is the policy of hosts without a robots.txt
+var allowAll = &Robots{}
+
+// ParseRobots keeps the rules of body that apply to userAgent: those of the
+// groups naming its product token, or else those of the * groups
+func ParseRobots(body []byte, userAgent string) *Robots {
+	token := productToken(userAgent)
+
+	var groups []*group
+	var current *group
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share the rules that follow them
+			if current == nil || len(current.rules) > 0 || current.crawlDelay > 0 {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			// An empty disallow allows everything, it adds no rule
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	robots := merge(groups, token)
+	if robots == nil {
+		robots = merge(groups, "*")
+	}
+	if robots == nil {
+		return allowAll
+	}
+	return robots
+}
+
+// merge combines the groups naming agent, nil when there are none
+func merge(groups []*group, agent string) *Robots {
+	var robots *Robots
+	for _, g := range groups {
+		for _, name := range g.agents {
+			if name != agent {
+				continue
+			}
+			if robots == nil {
+				robots = &Robots{}
+			}
+			robots.rules = append(robots.rules, g.rules...)
+			robots.crawlDelay = max(robots.crawlDelay, g.crawlDelay)
+			break
+		}
+	}
+	return robots
+}
+
+// Allowed checks if path, query included, may be fetched. The longest
+// matching rule wins, allow winning ties; no rule means allowed.
+func (r *Robots) Allowed(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !matches(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed = rule.allow
+			longest = len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay returns the delay asked between two requests, zero when none
+func (r *Robots) CrawlDelay() time.Duration {
+	return r.crawlDelay
+}
+
+// matches checks path against a pattern where * matches any sequence of
+// characters and a trailing $ anchors the end of the path
+func matches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// productToken is the name robots.txt knows a crawler by: its User-Agent up
+// to the version, e.g. auditreportdownloader for AuditReportDownloader/1.0
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(userAgent, "/")
+	token, _, _ = strings.Cut(token, " ")
+	return strings.ToLower(strings.TrimSpace(token))
+}
@@ -0,0 +1,197 @@
+package crawlpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRobots(t *testing.T) {
+	const body = `# robots.txt of audits.example.com
+User-agent: *
+Disallow: /private
+Crawl-delay: 2
+
+User-agent: AuditReportDownloader
+User-agent: otherbot
+Disallow: /reports/drafts/ # unpublished
+Allow: /reports/drafts/public
+Disallow:
+Crawl-delay: 0.5
+
+User-agent: auditreportdownloader
+Disallow: /tmp
+Crawl-delay: 1
+
+Sitemap: https://audits.example.com/sitemap.xml
+`
+
+	tests := []struct {
+		name      string
+		userAgent string
+		allowed   map[string]bool
+		delay     time.Duration
+	}{
+		{
+			name:      "groups of the product token, merged",
+			userAgent: "AuditReportDownloader/1.0 (+https://example.com/bot)",
+			allowed: map[string]bool{
+				"/private":                  true,
+				"/reports/drafts/2025":      false,
+				"/reports/drafts/public/1":  true,
+				"/tmp/file":                 false,
+				"/reports/2025-06-vault.md": true,
+			},
+			delay: time.Second,
+		},
+		{
+			name:      "agent listed with another in one group",
+			userAgent: "OtherBot",
+			allowed: map[string]bool{
+				"/private":             true,
+				"/reports/drafts/2025": false,
+			},
+			delay: 500 * time.Millisecond,
+		},
+		{
+			name:      "star group for unknown agents",
+			userAgent: "Mozilla/5.0",
+			allowed: map[string]bool{
+				"/private/x":           false,
+				"/reports/drafts/2025": true,
+			},
+			delay: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robots := ParseRobots([]byte(body), tt.userAgent)
+			for path, allowed := range tt.allowed {
+				assert.Equal(t, allowed, robots.Allowed(path), path)
+			}
+			assert.Equal(t, tt.delay, robots.CrawlDelay())
+		})
+	}
+}
+
+func TestParseRobots_AllowsAll(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty", body: ""},
+		{name: "no group for the agent", body: "User-agent: otherbot\nDisallow: /\n"},
+		{name: "empty disallow", body: "User-agent: *\nDisallow:\n"},
+		{name: "rules before any user-agent", body: "Disallow: /\nCrawl-delay: 5\n"},
+		{name: "not a robots.txt", body: "<html><body>Not found</body></html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robots := ParseRobots([]byte(tt.body), "AuditReportDownloader/1.0")
+			assert.True(t, robots.Allowed("/reports/1"))
+			assert.Zero(t, robots.CrawlDelay())
+		})
+	}
+}
+
+func TestRobots_Allowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []rule
+		path    string
+		allowed bool
+	}{
+		{name: "no rule", path: "/a", allowed: true},
+		{name: "disallowed prefix", rules: []rule{{pattern: "/a"}}, path: "/a/b", allowed: false},
+		{name: "longest match wins", rules: []rule{{pattern: "/a"}, {allow: true, pattern: "/a/b"}}, path: "/a/b/c", allowed: true},
+		{name: "longest disallow wins", rules: []rule{{allow: true, pattern: "/a"}, {pattern: "/a/b"}}, path: "/a/b/c", allowed: false},
+		{name: "allow wins ties", rules: []rule{{pattern: "/a*"}, {allow: true, pattern: "/a/"}}, path: "/a/b", allowed: true},
+		{name: "robots.txt itself", rules: []rule{{pattern: "/"}}, path: "/robots.txt", allowed: true},
+		{name: "query included", rules: []rule{{pattern: "/search?q="}}, path: "/search?q=vault", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robots := &Robots{rules: tt.rules}
+			assert.Equal(t, tt.allowed, robots.Allowed(tt.path))
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	groups := []*group{
+		{agents: []string{"*"}, rules: []rule{{pattern: "/star"}}, crawlDelay: 3 * time.Second},
+		{agents: []string{"bot", "otherbot"}, rules: []rule{{pattern: "/a"}}, crawlDelay: time.Second},
+		{agents: []string{"bot"}, rules: []rule{{allow: true, pattern: "/b"}}, crawlDelay: 2 * time.Second},
+	}
+
+	robots := merge(groups, "bot")
+	if assert.NotNil(t, robots) {
+		assert.Equal(t, []rule{{pattern: "/a"}, {allow: true, pattern: "/b"}}, robots.rules)
+		assert.Equal(t, 2*time.Second, robots.crawlDelay, "the longest delay applies")
+	}
+
+	robots = merge(groups, "otherbot")
+	if assert.NotNil(t, robots) {
+		assert.Equal(t, []rule{{pattern: "/a"}}, robots.rules)
+	}
+
+	assert.Nil(t, merge(groups, "unknownbot"))
+	assert.Nil(t, merge(nil, "*"))
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/", "/anything", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish.html", false},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/folder/index.php?x=1", true},
+		{"/*.php", "/index.html", false},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/a.php/b.php", true},
+		{"/fish*", "/fish", true},
+		{"/fish*.pdf", "/fish/report.pdf", true},
+		{"/a*b*c", "/a-x-b-y-c", true},
+		{"/a*b*c", "/a-x-c-y-b", false},
+		{"/exact$", "/exact", true},
+		{"/exact$", "/exact/", false},
+		{"*", "/", true},
+		{"*report$", "/reports/report", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.match, matches(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestProductToken(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		token     string
+	}{
+		{"AuditReportDownloader/1.0", "auditreportdownloader"},
+		{"AuditReportDownloader/1.0 (+https://example.com/bot)", "auditreportdownloader"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1)", "mozilla"},
+		{"SimpleBot", "simplebot"},
+		{"Spaced Bot", "spaced"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			assert.Equal(t, tt.token, productToken(tt.userAgent))
+		})
+	}
+}
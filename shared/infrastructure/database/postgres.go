@@ -154,7 +154,7 @@ func (d *DB) Select(ctx context.Context, dest interface{}, query string, args ..
 func (d *DB) Transaction(ctx context.Context, fn func(tx ports.Transaction) error) error {
 	startTime := time.Now()
 
-	tx, err := d.conn.BeginTx(ctx, nil)
+	tx, err := d.conn.BeginTxx(ctx, nil)
 	if err != nil {
 		d.logger.Error("Failed to begin transaction", "error", err)
 		return err
@@ -212,7 +212,7 @@ func (d *DB) recordMetrics(operation string, duration time.Duration, err error)
 }
 
 type pgTx struct {
-	tx      *sql.Tx
+	tx      *sqlx.Tx
 	logger  ports.Logger
 	metrics ports.Metrics
 }
@@ -229,6 +229,14 @@ func (t *pgTx) QueryRow(ctx context.Context, query string, args ...interface{})
 	return t.tx.QueryRowContext(ctx, query, args...)
 }
 
+func (t *pgTx) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return t.tx.SelectContext(ctx, dest, query, args...)
+}
+
+func (t *pgTx) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return t.tx.GetContext(ctx, dest, query, args...)
+}
+
 func (t *pgTx) Commit() error {
 	return t.tx.Commit()
 }
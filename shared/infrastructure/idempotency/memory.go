@@ -0,0 +1,66 @@
+// Package idempotency stores the requests already handled by a worker.
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneEvery is how many keys are remembered between two sweeps of the
+// expired ones
+const pruneEvery = 1024
+
+// MemoryStore is a ports.IdempotencyStore local to the process. It catches
+// the redeliveries reaching the same process, which is what a consumer or a
+// warm Lambda container sees; the handlers stay idempotent for the others.
+type MemoryStore struct {
+	mu         sync.Mutex
+	expiries   map[string]time.Time
+	sincePrune int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		expiries: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryStore) Handled(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires, ok := s.expiries[key]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expires) {
+		delete(s.expiries, key)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) Remember(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.expiries[key] = now.Add(ttl)
+
+	s.sincePrune++
+	if s.sincePrune >= pruneEvery {
+		s.prune(now)
+	}
+	return nil
+}
+
+// prune forgets the expired keys
+func (s *MemoryStore) prune(now time.Time) {
+	for key, expires := range s.expiries {
+		if now.After(expires) {
+			delete(s.expiries, key)
+		}
+	}
+	s.sincePrune = 0
+}
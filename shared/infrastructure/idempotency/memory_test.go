@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Handled(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	handled, err := store.Handled(ctx, "event:e1")
+	require.NoError(t, err)
+	assert.False(t, handled)
+
+	require.NoError(t, store.Remember(ctx, "event:e1", time.Hour))
+	handled, err = store.Handled(ctx, "event:e1")
+	require.NoError(t, err)
+	assert.True(t, handled)
+
+	handled, err = store.Handled(ctx, "event:e2")
+	require.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	require.NoError(t, store.Remember(ctx, "event:e1", 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	handled, err := store.Handled(ctx, "event:e1")
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.NotContains(t, store.expiries, "event:e1", "an expired key is forgotten once read")
+
+	// Remembered again, it counts from then
+	require.NoError(t, store.Remember(ctx, "event:e1", time.Hour))
+	handled, err = store.Handled(ctx, "event:e1")
+	require.NoError(t, err)
+	assert.True(t, handled)
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	require.NoError(t, store.Remember(ctx, "expired", -time.Second))
+	require.NoError(t, store.Remember(ctx, "kept", time.Hour))
+	for i := 2; i < pruneEvery-1; i++ {
+		require.NoError(t, store.Remember(ctx, fmt.Sprintf("event:%d", i), -time.Second))
+	}
+	assert.Len(t, store.expiries, pruneEvery-1, "nothing is pruned before pruneEvery keys")
+
+	require.NoError(t, store.Remember(ctx, "last", time.Hour))
+	assert.Len(t, store.expiries, 2)
+	assert.Contains(t, store.expiries, "kept")
+	assert.Contains(t, store.expiries, "last")
+	assert.Zero(t, store.sincePrune)
+}
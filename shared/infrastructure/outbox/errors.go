@@ -0,0 +1,15 @@
+package outbox
+
+import "fmt"
+
+func ErrPublishFailed(err error) error {
+	return fmt.Errorf("failed to publish outbox message: %w", err)
+}
+
+func ErrMarkPublishedFailed(err error) error {
+	return fmt.Errorf("failed to mark outbox message published: %w", err)
+}
+
+func ErrListUnpublishedFailed(err error) error {
+	return fmt.Errorf("failed to list unpublished outbox messages: %w", err)
+}
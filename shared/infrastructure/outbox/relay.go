@@ -0,0 +1,88 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shared/application/ports"
+	"shared/domain/entity"
+	"time"
+)
+
+// Relay publishes outbox messages through the queue. Delivery is at least
+// once: a message published but not yet marked is sent again by Drain.
+type Relay struct {
+	repository ports.OutboxRepository
+	queue      ports.Queue
+	logger     ports.Logger
+	metrics    ports.Metrics
+}
+
+func NewRelay(repository ports.OutboxRepository, queue ports.Queue, obs ports.Observability) (*Relay, error) {
+	logger, metrics, err := obs.ComponentsScoped("outbox.relay")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return &Relay{
+		repository: repository,
+		queue:      queue,
+		logger:     logger,
+		metrics:    metrics,
+	}, nil
+}
+
+// Send publishes a committed message and marks it published. On failure the
+// attempt is recorded and the message is left for Drain.
+func (r *Relay) Send(ctx context.Context, message *entity.OutboxMessage) error {
+	err := r.queue.Publish(ctx, &ports.QueueMessage{
+		Target: message.Target,
+		Body:   message.Body(),
+	})
+	if err != nil {
+		r.metrics.IncrementCounter("outbox.publish_errors", map[string]string{"event_type": message.EventType})
+		message.RecordFailure(err.Error())
+		if updateErr := r.repository.Update(ctx, message); updateErr != nil {
+			r.logger.Error("Failed to record outbox publish failure",
+				"message_id", message.ID,
+				"error", updateErr.Error())
+		}
+		return ErrPublishFailed(err)
+	}
+
+	if err := message.MarkPublished(); err != nil {
+		return err
+	}
+	if err := r.repository.Update(ctx, message); err != nil {
+		return ErrMarkPublishedFailed(err)
+	}
+
+	r.metrics.IncrementCounter("outbox.published", map[string]string{"event_type": message.EventType})
+	return nil
+}
+
+// Drain sends up to limit unpublished messages created before createdBefore,
+// oldest first, and returns how many were published
+func (r *Relay) Drain(ctx context.Context, createdBefore time.Time, limit int) (int, error) {
+	messages, err := r.repository.GetUnpublished(ctx, createdBefore, limit)
+	if err != nil {
+		return 0, ErrListUnpublishedFailed(err)
+	}
+
+	sent := 0
+	var errs []error
+	for _, message := range messages {
+		if err := r.Send(ctx, message); err != nil {
+			r.logger.Error("Failed to relay outbox message",
+				"message_id", message.ID,
+				"event_type", message.EventType,
+				"attempt_count", message.AttemptCount,
+				"error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutbox keeps a copy of the messages as last stored
+type fakeOutbox struct {
+	ports.OutboxRepository
+	stored    map[int64]*entity.OutboxMessage
+	updateErr error
+}
+
+func newFakeOutbox(messages ...*entity.OutboxMessage) *fakeOutbox {
+	f := &fakeOutbox{stored: make(map[int64]*entity.OutboxMessage)}
+	for _, message := range messages {
+		stored := *message
+		f.stored[message.ID] = &stored
+	}
+	return f
+}
+
+func (f *fakeOutbox) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	stored := *message
+	f.stored[message.ID] = &stored
+	return nil
+}
+
+func (f *fakeOutbox) GetUnpublished(ctx context.Context, createdBefore time.Time, limit int) ([]*entity.OutboxMessage, error) {
+	var messages []*entity.OutboxMessage
+	for _, stored := range f.stored {
+		if stored.IsPublished() || !stored.CreatedAt.Before(createdBefore) {
+			continue
+		}
+		message := *stored
+		messages = append(messages, &message)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+// fakeQueue publishes to every target but the failing ones, and checks that
+// the message is still unpublished in the outbox when it is published
+type fakeQueue struct {
+	t         *testing.T
+	outbox    *fakeOutbox
+	failing   map[string]bool
+	published []*ports.QueueMessage
+}
+
+func (f *fakeQueue) Publish(ctx context.Context, message *ports.QueueMessage) error {
+	for _, stored := range f.outbox.stored {
+		if string(stored.Body()) == string(message.Body.(json.RawMessage)) {
+			assert.False(f.t, stored.IsPublished(), "marked published before its publish")
+		}
+	}
+	if f.failing[message.Target] {
+		return errors.New("broker unavailable")
+	}
+	f.published = append(f.published, message)
+	return nil
+}
+
+func (f *fakeQueue) PublishBatch(ctx context.Context, messages []*ports.QueueMessage) error {
+	for _, message := range messages {
+		if err := f.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestRelay(t *testing.T, messages ...*entity.OutboxMessage) (*Relay, *fakeOutbox, *fakeQueue) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	outbox := newFakeOutbox(messages...)
+	queue := &fakeQueue{t: t, outbox: outbox, failing: make(map[string]bool)}
+	relay, err := NewRelay(outbox, queue, obs)
+	require.NoError(t, err)
+	return relay, outbox, queue
+}
+
+func newMessage(id int64, target string, createdAt time.Time) *entity.OutboxMessage {
+	return &entity.OutboxMessage{
+		ID:        id,
+		Target:    target,
+		EventType: "download.requested",
+		Payload:   []byte(fmt.Sprintf(`{"download_id":%d}`, id)),
+		CreatedAt: createdAt,
+	}
+}
+
+func TestRelay_Send(t *testing.T) {
+	message := newMessage(1, "downloader", time.Now())
+	relay, outbox, queue := newTestRelay(t, message)
+
+	require.NoError(t, relay.Send(context.Background(), message))
+
+	require.Len(t, queue.published, 1)
+	assert.Equal(t, "downloader", queue.published[0].Target)
+	assert.JSONEq(t, `{"download_id":1}`, string(queue.published[0].Body.(json.RawMessage)))
+
+	stored := outbox.stored[1]
+	assert.True(t, stored.IsPublished())
+	assert.Equal(t, 1, stored.AttemptCount)
+	assert.Nil(t, stored.LastError)
+}
+
+func TestRelay_SendFailedPublish(t *testing.T) {
+	message := newMessage(1, "downloader", time.Now())
+	relay, outbox, queue := newTestRelay(t, message)
+	queue.failing["downloader"] = true
+
+	err := relay.Send(context.Background(), message)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broker unavailable")
+
+	stored := outbox.stored[1]
+	assert.False(t, stored.IsPublished(), "a failed publish leaves the message in the outbox")
+	assert.Equal(t, 1, stored.AttemptCount)
+	if assert.NotNil(t, stored.LastError) {
+		assert.Equal(t, "broker unavailable", *stored.LastError)
+	}
+
+	// Left for Drain, which publishes it once the broker is back
+	queue.failing["downloader"] = false
+	sent, err := relay.Drain(context.Background(), time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.True(t, outbox.stored[1].IsPublished())
+	assert.Equal(t, 2, outbox.stored[1].AttemptCount)
+}
+
+func TestRelay_SendFailedMark(t *testing.T) {
+	message := newMessage(1, "downloader", time.Now())
+	relay, outbox, queue := newTestRelay(t, message)
+	outbox.updateErr = errors.New("database unavailable")
+
+	err := relay.Send(context.Background(), message)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database unavailable")
+	assert.Len(t, queue.published, 1, "published, and sent again by Drain: delivery is at least once")
+	assert.False(t, outbox.stored[1].IsPublished())
+}
+
+func TestRelay_Drain(t *testing.T) {
+	now := time.Now()
+	published := newMessage(1, "processor", now.Add(-time.Hour))
+	require.NoError(t, published.MarkPublished())
+	relay, outbox, queue := newTestRelay(t,
+		published,
+		newMessage(2, "downloader", now.Add(-3*time.Minute)),
+		newMessage(3, "extractor", now.Add(-2*time.Minute)),
+		newMessage(4, "downloader", now.Add(-time.Minute)),
+		newMessage(5, "downloader", now), // still within its transaction's relay
+	)
+	queue.failing["extractor"] = true
+
+	sent, err := relay.Drain(context.Background(), now.Add(-30*time.Second), 10)
+	require.Error(t, err)
+	assert.Equal(t, 2, sent)
+
+	var targets []string
+	for _, message := range queue.published {
+		targets = append(targets, message.Target)
+	}
+	assert.Equal(t, []string{"downloader", "downloader"}, targets, "oldest first, failures skipped")
+
+	assert.True(t, outbox.stored[2].IsPublished())
+	assert.False(t, outbox.stored[3].IsPublished())
+	assert.True(t, outbox.stored[4].IsPublished())
+	assert.False(t, outbox.stored[5].IsPublished())
+}
+
+func TestRelay_DrainLimit(t *testing.T) {
+	now := time.Now()
+	relay, outbox, _ := newTestRelay(t,
+		newMessage(1, "downloader", now.Add(-3*time.Minute)),
+		newMessage(2, "downloader", now.Add(-2*time.Minute)),
+		newMessage(3, "downloader", now.Add(-time.Minute)),
+	)
+
+	sent, err := relay.Drain(context.Background(), now, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.True(t, outbox.stored[1].IsPublished())
+	assert.True(t, outbox.stored[2].IsPublished())
+	assert.False(t, outbox.stored[3].IsPublished())
+}
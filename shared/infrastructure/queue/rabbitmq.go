@@ -83,6 +83,16 @@ func (q *RabbitMQQueue) Publish(ctx context.Context, message *ports.QueueMessage
 		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 
+	// Delayed messages wait in a delay queue that expires them into the target
+	routingKey := message.Target
+	if message.Delay > 0 {
+		routingKey, err = q.declareDelayQueue(message.Target, message.Delay)
+		if err != nil {
+			q.logger.Error("failed to declare delay queue", "error", err, "queue", message.Target)
+			return fmt.Errorf("failed to declare delay queue: %w", err)
+		}
+	}
+
 	// Create AMQP message
 	amqpMsg := amqp091.Publishing{
 		DeliveryMode: amqp091.Persistent,
@@ -94,10 +104,10 @@ func (q *RabbitMQQueue) Publish(ctx context.Context, message *ports.QueueMessage
 	// Publish message
 	err = q.channel.PublishWithContext(
 		ctx,
-		"",             // exchange (empty for direct queue)
-		message.Target, // routing key (queue name)
-		false,          // mandatory
-		false,          // immediate
+		"",         // exchange (empty for direct queue)
+		routingKey, // routing key (queue name)
+		false,      // mandatory
+		false,      // immediate
 		amqpMsg,
 	)
 
@@ -115,6 +125,32 @@ func (q *RabbitMQQueue) Publish(ctx context.Context, message *ports.QueueMessage
 	return nil
 }
 
+// declareDelayQueue declares the queue holding messages for target during
+// delay, rounded to the second. There is one queue per delay since messages
+// only expire from the head of a queue, and each deletes itself once unused.
+func (q *RabbitMQQueue) declareDelayQueue(target string, delay time.Duration) (string, error) {
+	ttl := max(delay.Round(time.Second), time.Second)
+	name := fmt.Sprintf("%s.delay.%ds", target, int64(ttl/time.Second))
+
+	_, err := q.channel.QueueDeclare(
+		name,  // queue name
+		true,  // durable
+		false, // auto-delete
+		false, // exclusive
+		false, // no-wait
+		amqp091.Table{
+			"x-message-ttl":             ttl.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": target,
+			"x-expires":                 (ttl + time.Minute).Milliseconds(),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
 func (q *RabbitMQQueue) PublishBatch(ctx context.Context, messages []*ports.QueueMessage) error {
 	for _, msg := range messages {
 		if err := q.Publish(ctx, msg); err != nil {
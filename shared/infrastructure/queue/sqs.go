@@ -100,8 +100,9 @@ func (q *SQSQueue) Publish(ctx context.Context, message *ports.QueueMessage) err
 
 	// Build SQS message
 	sqsMsg := &sqs.SendMessageInput{
-		QueueUrl:    aws.String(queueURL),
-		MessageBody: aws.String(string(body)),
+		QueueUrl:     aws.String(queueURL),
+		MessageBody:  aws.String(string(body)),
+		DelaySeconds: delaySeconds(message.Delay),
 	}
 
 	// Send message
@@ -157,8 +158,9 @@ func (q *SQSQueue) publishBatchToQueue(ctx context.Context, target string, messa
 			}
 
 			entries[j] = types.SendMessageBatchRequestEntry{
-				Id:          aws.String(fmt.Sprintf("%d", j)),
-				MessageBody: aws.String(string(body)),
+				Id:           aws.String(fmt.Sprintf("%d", j)),
+				MessageBody:  aws.String(string(body)),
+				DelaySeconds: delaySeconds(msg.Delay),
 			}
 		}
 
@@ -179,3 +181,17 @@ func (q *SQSQueue) publishBatchToQueue(ctx context.Context, target string, messa
 
 	return nil
 }
+
+// SQS delays a message by 15 minutes at most; longer delays are cut short, so
+// consumers must tolerate an early delivery
+const maxDelay = 15 * time.Minute
+
+func delaySeconds(delay time.Duration) int32 {
+	if delay <= 0 {
+		return 0
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return int32(delay.Round(time.Second) / time.Second)
+}
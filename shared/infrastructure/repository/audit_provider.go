@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"shared/domain/entity"
+	"shared/domain/entity/auditprovider"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -13,14 +14,20 @@ type auditProviderRepository struct {
 }
 
 func (r *auditProviderRepository) Create(ctx context.Context, provider *entity.AuditProvider) error {
+	if provider.CrawlPolicy == "" {
+		provider.CrawlPolicy = auditprovider.CrawlPolicyRobots
+	}
+
 	query := r.qb.Insert("audit_providers").
 		Columns(
 			"name", "slug", "website_url", "description",
 			"provider_type", "is_active", "created_at", "updated_at",
+			"crawl_policy", "crawl_delay_seconds",
 		).
 		Values(
 			provider.Name, provider.Slug, provider.WebsiteURL, provider.Description,
 			provider.ProviderType, provider.IsActive, provider.CreatedAt, provider.UpdatedAt,
+			provider.CrawlPolicy, provider.CrawlDelaySeconds,
 		).
 		Suffix("RETURNING id")
 
@@ -39,7 +46,12 @@ func (r *auditProviderRepository) Update(ctx context.Context, provider *entity.A
 		Set("slug", provider.Slug).
 		Set("provider_type", provider.ProviderType).
 		Set("is_active", provider.IsActive).
-		Set("updated_at", provider.UpdatedAt)
+		Set("updated_at", provider.UpdatedAt).
+		Set("crawl_delay_seconds", provider.CrawlDelaySeconds)
+
+	if provider.CrawlPolicy != "" {
+		query = query.Set("crawl_policy", provider.CrawlPolicy)
+	}
 
 	if provider.WebsiteURL != nil {
 		query = query.Set("website_url", *provider.WebsiteURL)
@@ -67,6 +79,7 @@ func (r *auditProviderRepository) GetBySlug(ctx context.Context, slug string) (*
 	err := row.Scan(
 		&p.ID, &p.Name, &p.Slug, &p.WebsiteURL, &p.Description,
 		&p.ProviderType, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+		&p.CrawlPolicy, &p.CrawlDelaySeconds,
 	)
 
 	if err != nil {
@@ -97,6 +110,7 @@ func (r *auditProviderRepository) List(ctx context.Context, onlyActive bool) ([]
 		err := rows.Scan(
 			&p.ID, &p.Name, &p.Slug, &p.WebsiteURL, &p.Description,
 			&p.ProviderType, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+			&p.CrawlPolicy, &p.CrawlDelaySeconds,
 		)
 		if err != nil {
 			return nil, err
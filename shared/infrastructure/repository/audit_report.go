@@ -2,12 +2,26 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"html"
+	"shared/application/ports"
 	"shared/domain/entity"
+	"shared/domain/entity/download"
+	"strings"
 
 	"github.com/Masterminds/squirrel"
 )
 
+// auditReportColumns leaves out the search vector
+var auditReportColumns = []string{
+	"id", "source_id", "provider_id", "title", "engagement_type",
+	"client_company", "audit_start_date", "audit_end_date",
+	"details_page_url", "source_download_url", "repository_url",
+	"repository_ref", "summary", "findings_summary", "created_at", "updated_at",
+}
+
 type auditReportRepository struct {
 	*baseRepository[entity.AuditReport]
 }
@@ -18,19 +32,22 @@ func (r *auditReportRepository) Create(ctx context.Context, report *entity.Audit
 			"source_id", "provider_id", "title", "engagement_type",
 			"client_company", "audit_start_date", "audit_end_date",
 			"details_page_url", "source_download_url", "repository_url",
-			"summary", "findings_summary", "created_at", "updated_at",
+			"repository_ref", "summary", "findings_summary", "created_at", "updated_at",
 		).
 		Values(
 			report.SourceID, report.ProviderID, report.Title, report.EngagementType,
 			report.ClientCompany, report.AuditStartDate, report.AuditEndDate,
 			report.DetailsPageURL, report.SourceDownloadURL, report.RepositoryURL,
-			report.Summary, report.FindingsSummary, report.CreatedAt, report.UpdatedAt,
+			report.RepositoryRef, report.Summary, report.FindingsSummary, report.CreatedAt, report.UpdatedAt,
 		).
-		Suffix("RETURNING id")
+		Suffix("ON CONFLICT (source_id, details_page_url) DO NOTHING RETURNING id")
 
-	sql, args, _ := query.ToSql()
-	row := r.db.QueryRow(ctx, sql, args...)
-	err := row.Scan(&report.ID)
+	// A report stored concurrently by another extractor returns no row
+	sqlQuery, args, _ := query.ToSql()
+	err := r.db.Get(ctx, &report.ID, sqlQuery, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ports.ErrAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create report: %w", err)
 	}
@@ -56,6 +73,9 @@ func (r *auditReportRepository) Update(ctx context.Context, report *entity.Audit
 	if report.RepositoryURL != nil {
 		query = query.Set("repository_url", *report.RepositoryURL)
 	}
+	if report.RepositoryRef != nil {
+		query = query.Set("repository_ref", *report.RepositoryRef)
+	}
 	if report.Summary != nil {
 		query = query.Set("summary", *report.Summary)
 	}
@@ -89,3 +109,142 @@ func (r *auditReportRepository) ExistsByURL(ctx context.Context, sourceID int64,
 
 	return count > 0, nil
 }
+
+func (r *auditReportRepository) List(ctx context.Context, filter ports.AuditReportFilter) ([]*entity.AuditReport, error) {
+	query := r.qb.Select(qualified("audit_reports", auditReportColumns)...).From("audit_reports")
+	if filter.ProviderSlug != "" {
+		query = query.
+			Join("audit_providers ON audit_providers.id = audit_reports.provider_id").
+			Where(squirrel.Eq{"audit_providers.slug": filter.ProviderSlug})
+	}
+	if filter.Status != "" {
+		query = query.
+			Join("downloads ON downloads.report_id = audit_reports.id AND downloads.kind = ?", download.ArtifactKindReport).
+			Where(squirrel.Eq{"downloads.status": filter.Status})
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"audit_reports.created_at": filter.Since})
+	}
+	if filter.BeforeID > 0 {
+		query = query.Where(squirrel.Lt{"audit_reports.id": filter.BeforeID})
+	}
+	query = query.OrderBy("audit_reports.id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, _ := query.ToSql()
+	var reports []*entity.AuditReport
+	if err := r.db.Select(ctx, &reports, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	return reports, nil
+}
+
+// searchHit is a row of Search: the report, its rank and excerpts
+type searchHit struct {
+	entity.AuditReport
+	Rank     float64 `db:"rank"`
+	Headline string  `db:"headline"`
+}
+
+// The matching terms are delimited by private use characters rather than
+// tags: ts_headline copies the scraped text as is, so the excerpts are escaped
+// before the delimiters become <mark> tags.
+const (
+	headlineStart = "\uE000"
+	headlineStop  = "\uE001"
+)
+
+// headlineOptions keep a few short excerpts around the matching terms
+const headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop +
+	", MaxFragments=3, MaxWords=25, MinWords=10, FragmentDelimiter=\" … \""
+
+var headlineMarks = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// markHeadline turns the excerpts of ts_headline into HTML
+func markHeadline(headline string) string {
+	return headlineMarks.Replace(html.EscapeString(headline))
+}
+
+func (r *auditReportRepository) Search(ctx context.Context, search ports.ReportSearch) ([]*ports.ReportSearchHit, error) {
+	// Rank every match first, then highlight the page only: ts_headline
+	// reads the whole text where the ranking only reads the vectors
+	matches := squirrel.Select(
+		"audit_reports.id",
+		"ts_rank_cd(audit_reports.search_vector || COALESCE(audit_report_details.search_vector, ''), search_query) AS rank",
+		"search_query",
+	).
+		From("audit_reports").
+		LeftJoin("audit_report_details ON audit_report_details.report_id = audit_reports.id").
+		JoinClause("CROSS JOIN websearch_to_tsquery('english', ?) AS search_query", search.Query).
+		Where("(audit_reports.search_vector @@ search_query OR audit_report_details.search_vector @@ search_query)")
+
+	if search.ProviderSlug != "" {
+		matches = matches.
+			Join("audit_providers ON audit_providers.id = audit_reports.provider_id").
+			Where(squirrel.Eq{"audit_providers.slug": search.ProviderSlug})
+	}
+	if search.EngagementType != "" {
+		matches = matches.Where(squirrel.Eq{"audit_reports.engagement_type": search.EngagementType})
+	}
+	if !search.EndedAfter.IsZero() {
+		matches = matches.Where(squirrel.GtOrEq{"audit_reports.audit_end_date": search.EndedAfter})
+	}
+	if !search.EndedBefore.IsZero() {
+		matches = matches.Where(squirrel.LtOrEq{"audit_reports.audit_end_date": search.EndedBefore})
+	}
+	minFindings := []struct {
+		severity string
+		least    int
+	}{
+		{"high", search.MinFindings.High},
+		{"medium", search.MinFindings.Medium},
+		{"low", search.MinFindings.Low},
+		{"informational", search.MinFindings.Informational},
+	}
+	for _, finding := range minFindings {
+		if finding.least > 0 {
+			matches = matches.Where(fmt.Sprintf("COALESCE((audit_reports.findings_summary->>'%s')::int, 0) >= ?", finding.severity), finding.least)
+		}
+	}
+
+	matches = matches.OrderBy("rank DESC", "audit_reports.id DESC")
+	if search.Limit > 0 {
+		matches = matches.Limit(uint64(search.Limit))
+	}
+	if search.Offset > 0 {
+		matches = matches.Offset(uint64(search.Offset))
+	}
+
+	columns := append(qualified("audit_reports", auditReportColumns),
+		"matches.rank",
+		"ts_headline('english', concat_ws(' ', audit_reports.title, audit_reports.summary, audit_report_details.full_summary), "+
+			"matches.search_query, '"+headlineOptions+"') AS headline",
+	)
+	query := r.qb.Select(columns...).
+		FromSelect(matches, "matches").
+		Join("audit_reports ON audit_reports.id = matches.id").
+		LeftJoin("audit_report_details ON audit_report_details.report_id = audit_reports.id").
+		OrderBy("matches.rank DESC", "audit_reports.id DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build report search: %w", err)
+	}
+
+	var rows []searchHit
+	if err := r.db.Select(ctx, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to search reports: %w", err)
+	}
+
+	hits := make([]*ports.ReportSearchHit, len(rows))
+	for i := range rows {
+		hits[i] = &ports.ReportSearchHit{
+			Report:   &rows[i].AuditReport,
+			Rank:     rows[i].Rank,
+			Headline: markHeadline(rows[i].Headline),
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"shared/domain/entity"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// auditReportDetailColumns leaves out the search vector
+var auditReportDetailColumns = []string{
+	"id", "report_id", "full_summary", "raw_content", "created_at", "updated_at",
+}
+
+type auditReportDetailRepository struct {
+	*baseRepository[entity.AuditReportDetail]
+}
+
+func (r *auditReportDetailRepository) Create(ctx context.Context, detail *entity.AuditReportDetail) error {
+	query := r.qb.Insert("audit_report_details").
+		Columns("report_id", "full_summary", "raw_content", "created_at", "updated_at").
+		Values(detail.ReportID, detail.FullSummary, detail.RawContent, detail.CreatedAt, detail.UpdatedAt).
+		Suffix("RETURNING id")
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&detail.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create report detail: %w", err)
+	}
+	return nil
+}
+
+func (r *auditReportDetailRepository) Update(ctx context.Context, detail *entity.AuditReportDetail) error {
+	query := r.qb.Update("audit_report_details").
+		Set("updated_at", detail.UpdatedAt)
+
+	// Update nullable fields only if they have values
+	if detail.FullSummary != nil {
+		query = query.Set("full_summary", *detail.FullSummary)
+	}
+	if detail.RawContent != nil {
+		query = query.Set("raw_content", *detail.RawContent)
+	}
+
+	query = query.Where(squirrel.Eq{"id": detail.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+// Upsert creates the detail row for a report or overwrites the content of the existing one
+func (r *auditReportDetailRepository) Upsert(ctx context.Context, detail *entity.AuditReportDetail) error {
+	query := r.qb.Insert("audit_report_details").
+		Columns("report_id", "full_summary", "raw_content", "created_at", "updated_at").
+		Values(detail.ReportID, detail.FullSummary, detail.RawContent, detail.CreatedAt, detail.UpdatedAt).
+		Suffix(`ON CONFLICT (report_id) DO UPDATE SET
+			full_summary = COALESCE(EXCLUDED.full_summary, audit_report_details.full_summary),
+			raw_content = COALESCE(EXCLUDED.raw_content, audit_report_details.raw_content),
+			updated_at = EXCLUDED.updated_at
+		RETURNING id`)
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&detail.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert report detail: %w", err)
+	}
+	return nil
+}
+
+func (r *auditReportDetailRepository) GetByReportID(ctx context.Context, reportID int64) (*entity.AuditReportDetail, error) {
+	query := r.qb.Select(auditReportDetailColumns...).
+		From("audit_report_details").
+		Where(squirrel.Eq{"report_id": reportID})
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+
+	var d entity.AuditReportDetail
+	err := row.Scan(
+		&d.ID, &d.ReportID, &d.FullSummary,
+		&d.RawContent, &d.CreatedAt, &d.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
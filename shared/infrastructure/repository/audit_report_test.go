@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/auditreport"
+	"shared/domain/entity/download"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditReport_List(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	selectReports := "SELECT " + strings.Join(qualified("audit_reports", auditReportColumns), ", ") + " FROM audit_reports"
+	joinProvider := " JOIN audit_providers ON audit_providers.id = audit_reports.provider_id"
+	joinDownload := " JOIN downloads ON downloads.report_id = audit_reports.id AND downloads.kind = $"
+
+	tests := []struct {
+		name   string
+		filter ports.AuditReportFilter
+		sql    string
+		args   []interface{}
+	}{
+		{
+			name:   "no filter",
+			filter: ports.AuditReportFilter{},
+			sql:    selectReports + " ORDER BY audit_reports.id DESC",
+		},
+		{
+			name:   "provider",
+			filter: ports.AuditReportFilter{ProviderSlug: "code4rena", Limit: 51},
+			sql: selectReports + joinProvider +
+				" WHERE audit_providers.slug = $1 ORDER BY audit_reports.id DESC LIMIT 51",
+			args: []interface{}{"code4rena"},
+		},
+		{
+			name:   "status of the report download",
+			filter: ports.AuditReportFilter{Status: download.StatusFailed, Limit: 51},
+			sql: selectReports + joinDownload + "1" +
+				" WHERE downloads.status = $2 ORDER BY audit_reports.id DESC LIMIT 51",
+			args: []interface{}{download.ArtifactKindReport, download.StatusFailed},
+		},
+		{
+			name: "provider, status, since and cursor",
+			filter: ports.AuditReportFilter{
+				ProviderSlug: "cantina",
+				Status:       download.StatusCompleted,
+				Since:        since,
+				BeforeID:     120,
+				Limit:        11,
+			},
+			sql: selectReports + joinProvider + joinDownload + "1" +
+				" WHERE audit_providers.slug = $2 AND downloads.status = $3" +
+				" AND audit_reports.created_at >= $4 AND audit_reports.id < $5" +
+				" ORDER BY audit_reports.id DESC LIMIT 11",
+			args: []interface{}{download.ArtifactKindReport, "cantina", download.StatusCompleted, since, int64(120)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositories, querier := newTestRepositories(t)
+
+			_, err := repositories.AuditReport().List(t.Context(), tt.filter)
+			require.NoError(t, err)
+
+			query := querier.last(t)
+			assert.Equal(t, tt.sql, query.sql)
+			assert.Equal(t, tt.args, query.args)
+		})
+	}
+}
+
+func TestAuditReport_Search(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	selectHits := "SELECT " + strings.Join(qualified("audit_reports", auditReportColumns), ", ") +
+		", matches.rank, ts_headline('english', concat_ws(' ', audit_reports.title, audit_reports.summary," +
+		" audit_report_details.full_summary), matches.search_query, '" + headlineOptions + "') AS headline" +
+		" FROM (SELECT audit_reports.id," +
+		" ts_rank_cd(audit_reports.search_vector || COALESCE(audit_report_details.search_vector, ''), search_query) AS rank," +
+		" search_query FROM audit_reports" +
+		" LEFT JOIN audit_report_details ON audit_report_details.report_id = audit_reports.id" +
+		" CROSS JOIN websearch_to_tsquery('english', $1) AS search_query"
+	matching := " WHERE (audit_reports.search_vector @@ search_query OR audit_report_details.search_vector @@ search_query)"
+	ranked := " ORDER BY rank DESC, audit_reports.id DESC"
+	joinHits := ") AS matches JOIN audit_reports ON audit_reports.id = matches.id" +
+		" LEFT JOIN audit_report_details ON audit_report_details.report_id = audit_reports.id" +
+		" ORDER BY matches.rank DESC, audit_reports.id DESC"
+
+	tests := []struct {
+		name   string
+		search ports.ReportSearch
+		sql    string
+		args   []interface{}
+	}{
+		{
+			name:   "query only",
+			search: ports.ReportSearch{Query: "reentrancy"},
+			sql:    selectHits + matching + ranked + joinHits,
+			args:   []interface{}{"reentrancy"},
+		},
+		{
+			name:   "provider",
+			search: ports.ReportSearch{Query: "oracle", ProviderSlug: "cantina", Limit: 21},
+			sql: selectHits + " JOIN audit_providers ON audit_providers.id = audit_reports.provider_id" +
+				matching + " AND audit_providers.slug = $2" + ranked + " LIMIT 21" + joinHits,
+			args: []interface{}{"oracle", "cantina"},
+		},
+		{
+			name:   "engagement type and end date range",
+			search: ports.ReportSearch{Query: "oracle", EngagementType: auditreport.EngagementTypeSolo, EndedAfter: after, EndedBefore: before},
+			sql: selectHits + matching + " AND audit_reports.engagement_type = $2" +
+				" AND audit_reports.audit_end_date >= $3 AND audit_reports.audit_end_date <= $4" + ranked + joinHits,
+			args: []interface{}{"oracle", auditreport.EngagementTypeSolo, after, before},
+		},
+		{
+			name:   "least findings of the severities asked",
+			search: ports.ReportSearch{Query: "oracle", MinFindings: auditreport.FindingsSummary{High: 1, Informational: 3}},
+			sql: selectHits + matching +
+				" AND COALESCE((audit_reports.findings_summary->>'high')::int, 0) >= $2" +
+				" AND COALESCE((audit_reports.findings_summary->>'informational')::int, 0) >= $3" + ranked + joinHits,
+			args: []interface{}{"oracle", 1, 3},
+		},
+		{
+			name: "every filter and a page",
+			search: ports.ReportSearch{
+				Query:          "flash loan",
+				ProviderSlug:   "code4rena",
+				EngagementType: auditreport.EngagementTypeSolo,
+				EndedAfter:     after,
+				EndedBefore:    before,
+				MinFindings:    auditreport.FindingsSummary{Medium: 2},
+				Offset:         40,
+				Limit:          21,
+			},
+			sql: selectHits + " JOIN audit_providers ON audit_providers.id = audit_reports.provider_id" +
+				matching + " AND audit_providers.slug = $2 AND audit_reports.engagement_type = $3" +
+				" AND audit_reports.audit_end_date >= $4 AND audit_reports.audit_end_date <= $5" +
+				" AND COALESCE((audit_reports.findings_summary->>'medium')::int, 0) >= $6" +
+				ranked + " LIMIT 21 OFFSET 40" + joinHits,
+			args: []interface{}{"flash loan", "code4rena", auditreport.EngagementTypeSolo, after, before, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositories, querier := newTestRepositories(t)
+
+			_, err := repositories.AuditReport().Search(t.Context(), tt.search)
+			require.NoError(t, err)
+
+			query := querier.last(t)
+			assert.Equal(t, tt.sql, query.sql)
+			assert.Equal(t, tt.args, query.args)
+		})
+	}
+}
+
+func TestAuditReport_SearchEscapesHeadline(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+	querier.scan = func(dest interface{}) {
+		*dest.(*[]searchHit) = []searchHit{{
+			AuditReport: entity.AuditReport{ID: 3},
+			Rank:        0.5,
+			Headline:    `<img src=x onerror="alert(1)"> ` + headlineStart + "reentrancy" + headlineStop + " in </mark>withdraw & co",
+		}}
+	}
+
+	hits, err := repositories.AuditReport().Search(t.Context(), ports.ReportSearch{Query: "reentrancy"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(3), hits[0].Report.ID)
+	assert.Equal(t, "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>reentrancy</mark> in &lt;/mark&gt;withdraw &amp; co", hits[0].Headline)
+}
+
+func TestAuditReport_Create(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+	querier.scan = func(dest interface{}) { *dest.(*int64) = 5 }
+	report := &entity.AuditReport{SourceID: 7, ProviderID: 3, Title: "Vault", DetailsPageURL: "https://audits.example.com/vault"}
+
+	require.NoError(t, repositories.AuditReport().Create(t.Context(), report))
+	assert.Equal(t, int64(5), report.ID)
+
+	query := querier.last(t)
+	assert.True(t, strings.HasPrefix(query.sql, "INSERT INTO audit_reports "))
+	assert.True(t, strings.HasSuffix(query.sql, " ON CONFLICT (source_id, details_page_url) DO NOTHING RETURNING id"), query.sql)
+}
+
+func TestAuditReport_CreateStoredBefore(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+	querier.err = sql.ErrNoRows
+
+	err := repositories.AuditReport().Create(t.Context(), &entity.AuditReport{SourceID: 7, DetailsPageURL: "https://audits.example.com/vault"})
+	assert.ErrorIs(t, err, ports.ErrAlreadyExists)
+}
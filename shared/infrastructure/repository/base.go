@@ -10,20 +10,24 @@ import (
 )
 
 type baseRepository[T any] struct {
-	db      ports.Database
+	db      ports.Querier
 	logger  ports.Logger
 	metrics ports.Metrics
 	table   string
 	qb      squirrel.StatementBuilderType
+	// columns are those read by Get and ListAll; tables with columns no
+	// entity field maps, such as search vectors, list theirs
+	columns []string
 }
 
-func newBaseRepository[T any](db ports.Database, logger ports.Logger, metrics ports.Metrics, table string) *baseRepository[T] {
+func newBaseRepository[T any](db ports.Querier, logger ports.Logger, metrics ports.Metrics, table string) *baseRepository[T] {
 	return &baseRepository[T]{
 		db:      db,
 		logger:  logger,
 		metrics: metrics,
 		table:   table,
 		qb:      squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		columns: []string{"*"},
 	}
 }
 
@@ -41,7 +45,7 @@ func (r *baseRepository[T]) Get(ctx context.Context, id int64) (*T, error) {
 
 	// Build query with Squirrel
 	query := r.qb.
-		Select("*").
+		Select(r.columns...).
 		From(r.table).
 		Where(squirrel.Eq{"id": id})
 
@@ -53,7 +57,7 @@ func (r *baseRepository[T]) Get(ctx context.Context, id int64) (*T, error) {
 	// Execute and scan with sqlx
 	err = r.db.Get(ctx, &entity, sqlQuery, args...)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("entity not found")
+		return nil, ports.ErrNotFound
 	}
 	if err != nil {
 		r.logger.Error("Failed to get entity", "error", err)
@@ -93,7 +97,7 @@ func (r *baseRepository[T]) Delete(ctx context.Context, id int64) error {
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("entity not found")
+		return ports.ErrNotFound
 	}
 
 	return nil
@@ -105,7 +109,7 @@ func (r *baseRepository[T]) ListAll(ctx context.Context) ([]*T, error) {
 	r.metrics.IncrementCounter(fmt.Sprintf("repository.%s.list", r.table), nil)
 
 	query := r.qb.
-		Select("*").
+		Select(r.columns...).
 		From(r.table)
 
 	sql, args, err := query.ToSql()
@@ -156,3 +160,12 @@ func (r *baseRepository[T]) CountAll(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+// qualified prefixes columns with their table, for queries joining others
+func qualified(table string, columns []string) []string {
+	prefixed := make([]string, len(columns))
+	for i, column := range columns {
+		prefixed[i] = table + "." + column
+	}
+	return prefixed
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"shared/application/ports"
 	"shared/domain/entity/download"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -14,8 +18,8 @@ type downloadRepository struct {
 
 func (r *downloadRepository) Create(ctx context.Context, download *download.Download) error {
 	query := r.qb.Insert("downloads").
-		Columns("report_id", "status", "attempt_count", "created_at", "updated_at").
-		Values(download.ReportID, download.Status, download.AttemptCount, download.CreatedAt, download.UpdatedAt).
+		Columns("report_id", "kind", "source_url", "status", "attempt_count", "max_attempts", "created_at", "updated_at").
+		Values(download.ReportID, download.Kind, download.SourceURL, download.Status, download.AttemptCount, download.MaxAttempts(), download.CreatedAt, download.UpdatedAt).
 		Suffix("RETURNING id")
 
 	sql, args, _ := query.ToSql()
@@ -28,10 +32,45 @@ func (r *downloadRepository) Create(ctx context.Context, download *download.Down
 }
 
 func (r *downloadRepository) Update(ctx context.Context, download *download.Download) error {
+	query := r.updateQuery(download).Where(squirrel.Eq{"id": download.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+func (r *downloadRepository) UpdateFromStatus(ctx context.Context, download *download.Download, from download.Status) (bool, error) {
+	query := r.updateQuery(download).Where(squirrel.Eq{"id": download.ID, "status": from})
+
+	sql, args, _ := query.ToSql()
+	result, err := r.db.Execute(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *downloadRepository) updateQuery(download *download.Download) squirrel.UpdateBuilder {
 	query := r.qb.Update("downloads").
 		Set("status", download.Status).
 		Set("attempt_count", download.AttemptCount).
-		Set("updated_at", download.UpdatedAt)
+		Set("updated_at", download.UpdatedAt).
+		Set("etag", download.ETag).
+		Set("last_modified", download.LastModified).
+		Set("next_attempt_at", download.NextAttemptAt).
+		Set("error_code", download.ErrorCode).
+		Set("snapshot_path", download.SnapshotPath).
+		Set("manifest_path", download.ManifestPath)
+
+	// Zero means the entity was built by hand rather than loaded
+	if download.Version > 0 {
+		query = query.Set("version", download.Version)
+	}
 
 	// Update nullable fields only if they have values
 	if download.StoragePath != nil {
@@ -40,9 +79,18 @@ func (r *downloadRepository) Update(ctx context.Context, download *download.Down
 	if download.FileHash != nil {
 		query = query.Set("file_hash", *download.FileHash)
 	}
+	if download.ArchivePath != nil {
+		query = query.
+			Set("archive_path", *download.ArchivePath).
+			Set("archive_hash", download.ArchiveHash).
+			Set("archive_ref", download.ArchiveRef)
+	}
 	if download.FileExtension != nil {
 		query = query.Set("file_extension", *download.FileExtension)
 	}
+	if download.MIMEType != nil {
+		query = query.Set("mime_type", *download.MIMEType)
+	}
 	if download.ErrorMessage != nil {
 		query = query.Set("error_message", *download.ErrorMessage)
 	}
@@ -52,43 +100,183 @@ func (r *downloadRepository) Update(ctx context.Context, download *download.Down
 	if download.CompletedAt != nil {
 		query = query.Set("completed_at", *download.CompletedAt)
 	}
+	if download.CheckedAt != nil {
+		query = query.Set("checked_at", *download.CheckedAt)
+	}
 
-	query = query.Where(squirrel.Eq{"id": download.ID})
-
-	sql, args, _ := query.ToSql()
-	_, err := r.db.Execute(ctx, sql, args...)
-	return err
+	return query
 }
 
 func (r *downloadRepository) GetByReportID(ctx context.Context, reportID int64) (*download.Download, error) {
 	query := r.qb.Select("*").
 		From("downloads").
-		Where(squirrel.Eq{"report_id": reportID})
+		Where(squirrel.Eq{"report_id": reportID, "kind": download.ArtifactKindReport})
 
 	sql, args, _ := query.ToSql()
 	row := r.db.QueryRow(ctx, sql, args...)
 
-	var d download.Download
-	err := row.Scan(
-		&d.ID, &d.ReportID, &d.StoragePath, &d.FileHash,
-		&d.FileExtension, &d.Status, &d.ErrorMessage,
-		&d.AttemptCount, &d.CreatedAt, &d.StartedAt,
-		&d.CompletedAt, &d.UpdatedAt,
-	)
+	return scanDownload(row)
+}
 
+func (r *downloadRepository) ListByReportID(ctx context.Context, reportID int64) ([]*download.Download, error) {
+	query := r.qb.Select("*").
+		From("downloads").
+		Where(squirrel.Eq{"report_id": reportID}).
+		OrderBy("id ASC")
+
+	downloads, err := r.list(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list report downloads: %w", err)
 	}
-	return &d, nil
+	return downloads, nil
+}
+
+func (r *downloadRepository) List(ctx context.Context, filter ports.DownloadFilter) ([]*download.Download, error) {
+	query := r.qb.Select("*").From("downloads")
+	if filter.ReportIDs != nil {
+		query = query.Where(squirrel.Eq{"report_id": filter.ReportIDs})
+	}
+	if filter.Kind != "" {
+		query = query.Where(squirrel.Eq{"kind": filter.Kind})
+	}
+	if filter.Status != "" {
+		query = query.Where(squirrel.Eq{"status": filter.Status})
+	}
+	query = query.OrderBy("report_id ASC", "id ASC")
+
+	downloads, err := r.list(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list downloads: %w", err)
+	}
+	return downloads, nil
+}
+
+// Claim moves a startable download to in_progress in a single conditional
+// statement, so of two workers receiving the same event only one gets the row
+func (r *downloadRepository) Claim(ctx context.Context, id int64, workerID string) (*download.Download, error) {
+	now := time.Now()
+	query := r.qb.Update("downloads").
+		Set("status", download.StatusInProgress).
+		Set("attempt_count", squirrel.Expr("attempt_count + 1")).
+		Set("started_at", now).
+		Set("updated_at", now).
+		Set("error_message", nil).
+		Set("error_code", nil).
+		Set("claimed_by", workerID).
+		Set("next_attempt_at", nil).
+		Where(squirrel.Eq{
+			"id":     id,
+			"status": []download.Status{download.StatusPending, download.StatusFailed},
+		}).
+		Where("attempt_count < max_attempts").
+		Where(squirrel.Or{
+			squirrel.Eq{"next_attempt_at": nil},
+			squirrel.LtOrEq{"next_attempt_at": now},
+		}).
+		Suffix("RETURNING *")
+
+	sqlQuery, args, _ := query.ToSql()
+	d, err := scanDownload(r.db.QueryRow(ctx, sqlQuery, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, download.ErrClaimedElsewhere
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim download: %w", err)
+	}
+	return d, nil
 }
 
-func (r *downloadRepository) GetPendingDownloads(ctx context.Context, limit int) ([]*download.Download, error) {
+func (r *downloadRepository) GetPendingDownloads(ctx context.Context, updatedBefore time.Time, limit int) ([]*download.Download, error) {
 	query := r.qb.Select("*").
 		From("downloads").
 		Where(squirrel.Eq{"status": download.StatusPending}).
-		OrderBy("created_at ASC").
+		// Postponed downloads are not late before their next attempt is due
+		Where(squirrel.Lt{"GREATEST(updated_at, next_attempt_at)": updatedBefore}).
+		OrderBy("updated_at ASC").
+		Limit(uint64(limit))
+
+	return r.list(ctx, query)
+}
+
+func (r *downloadRepository) FindCompletedByHash(ctx context.Context, fileHash string, excludeID int64) (*download.Download, error) {
+	query := r.qb.Select("*").
+		From("downloads").
+		Where(squirrel.Eq{"status": download.StatusCompleted, "file_hash": fileHash}).
+		Where(squirrel.NotEq{"id": excludeID}).
+		OrderBy("completed_at ASC").
+		Limit(1)
+
+	sqlQuery, args, _ := query.ToSql()
+	d, err := scanDownload(r.db.QueryRow(ctx, sqlQuery, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find download by hash: %w", err)
+	}
+	return d, nil
+}
+
+// ClaimRecheck marks the download as checked now, so concurrent re-checks of
+// the same download stop here
+func (r *downloadRepository) ClaimRecheck(ctx context.Context, id int64, workerID string, checkedBefore time.Time) (*download.Download, error) {
+	now := time.Now()
+	query := r.qb.Update("downloads").
+		Set("checked_at", now).
+		Set("claimed_by", workerID).
+		Where(squirrel.Eq{"id": id, "status": download.StatusCompleted}).
+		Where(squirrel.Or{
+			squirrel.Eq{"checked_at": nil},
+			squirrel.Lt{"checked_at": checkedBefore},
+		}).
+		Suffix("RETURNING *")
+
+	sqlQuery, args, _ := query.ToSql()
+	d, err := scanDownload(r.db.QueryRow(ctx, sqlQuery, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, download.ErrClaimedElsewhere
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim download re-check: %w", err)
+	}
+	return d, nil
+}
+
+func (r *downloadRepository) GetDueForRecheck(ctx context.Context, checkedBefore time.Time, limit int) ([]*download.Download, error) {
+	query := r.qb.Select("*").
+		From("downloads").
+		Where(squirrel.Eq{"status": download.StatusCompleted}).
+		Where(squirrel.Lt{"COALESCE(checked_at, completed_at)": checkedBefore}).
+		OrderBy("COALESCE(checked_at, completed_at) ASC").
+		Limit(uint64(limit))
+
+	return r.list(ctx, query)
+}
+
+func (r *downloadRepository) GetDueRetries(ctx context.Context, dueBefore time.Time, limit int) ([]*download.Download, error) {
+	query := r.qb.Select("*").
+		From("downloads").
+		Where(squirrel.Eq{"status": download.StatusFailed}).
+		Where("attempt_count < max_attempts").
+		Where(squirrel.Lt{"COALESCE(next_attempt_at, updated_at)": dueBefore}).
+		OrderBy("COALESCE(next_attempt_at, updated_at) ASC").
 		Limit(uint64(limit))
 
+	return r.list(ctx, query)
+}
+
+func (r *downloadRepository) GetStuckDownloads(ctx context.Context, startedBefore time.Time, limit int) ([]*download.Download, error) {
+	query := r.qb.Select("*").
+		From("downloads").
+		Where(squirrel.Eq{"status": download.StatusInProgress}).
+		Where(squirrel.Lt{"started_at": startedBefore}).
+		OrderBy("started_at ASC").
+		Limit(uint64(limit))
+
+	return r.list(ctx, query)
+}
+
+func (r *downloadRepository) list(ctx context.Context, query squirrel.SelectBuilder) ([]*download.Download, error) {
 	sql, args, _ := query.ToSql()
 	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
@@ -98,18 +286,36 @@ func (r *downloadRepository) GetPendingDownloads(ctx context.Context, limit int)
 
 	var downloads []*download.Download
 	for rows.Next() {
-		var d download.Download
-		err := rows.Scan(
-			&d.ID, &d.ReportID, &d.StoragePath, &d.FileHash,
-			&d.FileExtension, &d.Status, &d.ErrorMessage,
-			&d.AttemptCount, &d.CreatedAt, &d.StartedAt,
-			&d.CompletedAt, &d.UpdatedAt,
-		)
+		d, err := scanDownload(rows)
 		if err != nil {
 			return nil, err
 		}
-		downloads = append(downloads, &d)
+		downloads = append(downloads, d)
 	}
 
 	return downloads, rows.Err()
 }
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDownload reads a row of SELECT * / RETURNING *, columns in table order
+func scanDownload(row rowScanner) (*download.Download, error) {
+	var d download.Download
+	err := row.Scan(
+		&d.ID, &d.ReportID, &d.StoragePath, &d.FileHash,
+		&d.FileExtension, &d.Status, &d.ErrorMessage,
+		&d.AttemptCount, &d.CreatedAt, &d.StartedAt,
+		&d.CompletedAt, &d.UpdatedAt, &d.ClaimedBy,
+		&d.ETag, &d.LastModified, &d.Version, &d.CheckedAt,
+		&d.AttemptLimit, &d.NextAttemptAt, &d.ErrorCode,
+		&d.MIMEType, &d.SnapshotPath,
+		&d.ArchivePath, &d.ArchiveHash, &d.ArchiveRef,
+		&d.Kind, &d.SourceURL, &d.ManifestPath,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+
+	"shared/application/ports"
+	"shared/domain/entity/download"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload_List(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ports.DownloadFilter
+		sql    string
+		args   []interface{}
+	}{
+		{
+			name:   "no filter",
+			filter: ports.DownloadFilter{},
+			sql:    "SELECT * FROM downloads ORDER BY report_id ASC, id ASC",
+		},
+		{
+			name:   "downloads of a page of reports",
+			filter: ports.DownloadFilter{ReportIDs: []int64{12, 11, 9}},
+			sql:    "SELECT * FROM downloads WHERE report_id IN ($1,$2,$3) ORDER BY report_id ASC, id ASC",
+			args:   []interface{}{int64(12), int64(11), int64(9)},
+		},
+		{
+			name: "kind and status",
+			filter: ports.DownloadFilter{
+				ReportIDs: []int64{3},
+				Kind:      download.ArtifactKindReport,
+				Status:    download.StatusPending,
+			},
+			sql: "SELECT * FROM downloads WHERE report_id IN ($1) AND kind = $2 AND status = $3" +
+				" ORDER BY report_id ASC, id ASC",
+			args: []interface{}{int64(3), download.ArtifactKindReport, download.StatusPending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositories, querier := newTestRepositories(t)
+
+			// The recording querier returns no rows to scan
+			_, _ = repositories.Download().List(t.Context(), tt.filter)
+
+			query := querier.last(t)
+			assert.Equal(t, tt.sql, query.sql)
+			assert.Equal(t, tt.args, query.args)
+		})
+	}
+}
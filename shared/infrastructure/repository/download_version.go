@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"shared/domain/entity"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type downloadVersionRepository struct {
+	*baseRepository[entity.DownloadVersion]
+}
+
+func (r *downloadVersionRepository) Create(ctx context.Context, version *entity.DownloadVersion) error {
+	query := r.qb.Insert("download_versions").
+		Columns(
+			"download_id", "version", "storage_path", "file_hash",
+			"file_extension", "etag", "last_modified", "created_at",
+		).
+		Values(
+			version.DownloadID, version.Version, version.StoragePath, version.FileHash,
+			version.FileExtension, version.ETag, version.LastModified, version.CreatedAt,
+		).
+		Suffix("RETURNING id")
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&version.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create download version: %w", err)
+	}
+	return nil
+}
+
+func (r *downloadVersionRepository) Update(ctx context.Context, version *entity.DownloadVersion) error {
+	query := r.qb.Update("download_versions").
+		Set("storage_path", version.StoragePath).
+		Set("file_hash", version.FileHash).
+		Set("file_extension", version.FileExtension).
+		Set("etag", version.ETag).
+		Set("last_modified", version.LastModified).
+		Where(squirrel.Eq{"id": version.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+func (r *downloadVersionRepository) ListByDownloadID(ctx context.Context, downloadID int64) ([]*entity.DownloadVersion, error) {
+	query := r.qb.Select("*").
+		From("download_versions").
+		Where(squirrel.Eq{"download_id": downloadID}).
+		OrderBy("version ASC")
+
+	sql, args, _ := query.ToSql()
+	var versions []*entity.DownloadVersion
+	if err := r.db.Select(ctx, &versions, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to list download versions: %w", err)
+	}
+	return versions, nil
+}
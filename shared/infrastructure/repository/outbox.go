@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"shared/domain/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type outboxRepository struct {
+	*baseRepository[entity.OutboxMessage]
+}
+
+func (r *outboxRepository) Create(ctx context.Context, message *entity.OutboxMessage) error {
+	query := r.qb.Insert("outbox").
+		Columns("target", "event_type", "payload", "attempt_count", "created_at").
+		Values(message.Target, message.EventType, message.Payload, message.AttemptCount, message.CreatedAt).
+		Suffix("RETURNING id")
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&message.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox message: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	query := r.qb.Update("outbox").
+		Set("attempt_count", message.AttemptCount).
+		Set("last_error", message.LastError).
+		Set("published_at", message.PublishedAt).
+		Where(squirrel.Eq{"id": message.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+func (r *outboxRepository) GetUnpublished(ctx context.Context, createdBefore time.Time, limit int) ([]*entity.OutboxMessage, error) {
+	query := r.qb.Select("*").
+		From("outbox").
+		Where(squirrel.Eq{"published_at": nil}).
+		Where(squirrel.Lt{"created_at": createdBefore}).
+		OrderBy("created_at ASC").
+		Limit(uint64(limit))
+
+	sql, args, _ := query.ToSql()
+	var messages []*entity.OutboxMessage
+	if err := r.db.Select(ctx, &messages, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+	return messages, nil
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"shared/domain/entity"
 	"shared/domain/entity/process"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -29,6 +32,62 @@ func (r *processRepository) Create(ctx context.Context, process *entity.Process)
 }
 
 func (r *processRepository) Update(ctx context.Context, process *entity.Process) error {
+	query := r.updateQuery(process).Where(squirrel.Eq{"id": process.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+func (r *processRepository) UpdateFromStatus(ctx context.Context, proc *entity.Process, from process.ProcessStatus) (bool, error) {
+	query := r.updateQuery(proc).Where(squirrel.Eq{"id": proc.ID, "status": from})
+
+	sql, args, _ := query.ToSql()
+	result, err := r.db.Execute(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// Claim moves a startable process to in_progress in a single conditional
+// statement, so of two workers receiving the same request only one gets the row
+func (r *processRepository) Claim(ctx context.Context, id int64) (*entity.Process, error) {
+	now := time.Now()
+	query := r.qb.Update("processes").
+		Set("status", process.StatusInProgress).
+		Set("attempt_count", squirrel.Expr("attempt_count + 1")).
+		Set("started_at", now).
+		Set("updated_at", now).
+		Set("error_message", nil).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Or{
+			squirrel.Eq{"status": process.StatusPending},
+			squirrel.And{
+				squirrel.Eq{"status": process.StatusFailed},
+				squirrel.Lt{"attempt_count": process.DefaultMaxAttempts},
+			},
+		}).
+		Suffix("RETURNING *")
+
+	sqlQuery, args, _ := query.ToSql()
+	var p entity.Process
+	err := r.db.Get(ctx, &p, sqlQuery, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, process.ErrClaimedElsewhere
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim process: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *processRepository) updateQuery(process *entity.Process) squirrel.UpdateBuilder {
 	query := r.qb.Update("processes").
 		Set("status", process.Status).
 		Set("attempt_count", process.AttemptCount).
@@ -47,11 +106,7 @@ func (r *processRepository) Update(ctx context.Context, process *entity.Process)
 		query = query.Set("completed_at", *process.CompletedAt)
 	}
 
-	query = query.Where(squirrel.Eq{"id": process.ID})
-
-	sql, args, _ := query.ToSql()
-	_, err := r.db.Execute(ctx, sql, args...)
-	return err
+	return query
 }
 
 func (r *processRepository) GetByDownloadID(ctx context.Context, downloadID int64) (*entity.Process, error) {
@@ -75,13 +130,29 @@ func (r *processRepository) GetByDownloadID(ctx context.Context, downloadID int6
 	return &p, nil
 }
 
-func (r *processRepository) GetPendingProcesses(ctx context.Context, limit int) ([]*entity.Process, error) {
+func (r *processRepository) GetPendingProcesses(ctx context.Context, updatedBefore time.Time, limit int) ([]*entity.Process, error) {
 	query := r.qb.Select("*").
 		From("processes").
 		Where(squirrel.Eq{"status": process.StatusPending}).
-		OrderBy("created_at ASC").
+		Where(squirrel.Lt{"updated_at": updatedBefore}).
+		OrderBy("updated_at ASC").
 		Limit(uint64(limit))
 
+	return r.list(ctx, query)
+}
+
+func (r *processRepository) GetStuckProcesses(ctx context.Context, startedBefore time.Time, limit int) ([]*entity.Process, error) {
+	query := r.qb.Select("*").
+		From("processes").
+		Where(squirrel.Eq{"status": process.StatusInProgress}).
+		Where(squirrel.Lt{"started_at": startedBefore}).
+		OrderBy("started_at ASC").
+		Limit(uint64(limit))
+
+	return r.list(ctx, query)
+}
+
+func (r *processRepository) list(ctx context.Context, query squirrel.SelectBuilder) ([]*entity.Process, error) {
 	sql, args, _ := query.ToSql()
 	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
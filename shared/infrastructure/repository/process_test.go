@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"shared/domain/entity"
+	"shared/domain/entity/process"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcess_Claim(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+	querier.scan = func(dest interface{}) {
+		*dest.(*entity.Process) = entity.Process{ID: 42, Status: process.StatusInProgress, AttemptCount: 2}
+	}
+
+	claimed, err := repositories.Process().Claim(t.Context(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, process.StatusInProgress, claimed.Status)
+	assert.Equal(t, 2, claimed.AttemptCount)
+
+	// Only pending processes and failed ones with attempts left are claimed
+	query := querier.last(t)
+	assert.Equal(t, "UPDATE processes SET status = $1, attempt_count = attempt_count + 1,"+
+		" started_at = $2, updated_at = $3, error_message = $4"+
+		" WHERE id = $5 AND (status = $6 OR (status = $7 AND attempt_count < $8)) RETURNING *", query.sql)
+	require.Len(t, query.args, 8)
+	assert.Equal(t, process.StatusInProgress, query.args[0])
+	assert.IsType(t, time.Time{}, query.args[1])
+	assert.Equal(t, query.args[1], query.args[2])
+	assert.Equal(t, []interface{}{nil, int64(42), process.StatusPending, process.StatusFailed, process.DefaultMaxAttempts}, query.args[3:])
+}
+
+func TestProcess_ClaimElsewhere(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+
+	querier.err = sql.ErrNoRows
+	_, err := repositories.Process().Claim(t.Context(), 42)
+	assert.ErrorIs(t, err, process.ErrClaimedElsewhere)
+
+	querier.err = sql.ErrConnDone
+	_, err = repositories.Process().Claim(t.Context(), 42)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NotErrorIs(t, err, process.ErrClaimedElsewhere)
+}
+
+func TestProcess_GetStuckProcesses(t *testing.T) {
+	startedBefore := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	repositories, querier := newTestRepositories(t)
+
+	// The recording querier returns no rows to scan
+	_, _ = repositories.Process().GetStuckProcesses(t.Context(), startedBefore, 100)
+
+	query := querier.last(t)
+	assert.Equal(t, "SELECT * FROM processes WHERE status = $1 AND started_at < $2 ORDER BY started_at ASC LIMIT 100", query.sql)
+	assert.Equal(t, []interface{}{process.StatusInProgress, startedBefore}, query.args)
+}
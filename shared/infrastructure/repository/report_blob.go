@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"shared/domain/entity"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type reportBlobRepository struct {
+	*baseRepository[entity.ReportBlob]
+}
+
+// Create links an artifact to its blob, replacing the previous link of the download
+func (r *reportBlobRepository) Create(ctx context.Context, blob *entity.ReportBlob) error {
+	query := r.qb.Insert("report_blobs").
+		Columns("report_id", "download_id", "file_hash", "storage_path", "created_at").
+		Values(blob.ReportID, blob.DownloadID, blob.FileHash, blob.StoragePath, blob.CreatedAt).
+		Suffix(`ON CONFLICT (download_id) DO UPDATE SET
+			file_hash = EXCLUDED.file_hash,
+			storage_path = EXCLUDED.storage_path
+		RETURNING id`)
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&blob.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create report blob: %w", err)
+	}
+	return nil
+}
+
+func (r *reportBlobRepository) Update(ctx context.Context, blob *entity.ReportBlob) error {
+	query := r.qb.Update("report_blobs").
+		Set("download_id", blob.DownloadID).
+		Set("file_hash", blob.FileHash).
+		Set("storage_path", blob.StoragePath).
+		Where(squirrel.Eq{"id": blob.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+// CountByStoragePath counts the downloads linked to the blob, and the
+// previous versions of downloads still kept at it
+func (r *reportBlobRepository) CountByStoragePath(ctx context.Context, storagePath string) (int64, error) {
+	sql, args, _ := r.countByStoragePathQuery(storagePath).ToSql()
+	var count int64
+	if err := r.db.Get(ctx, &count, sql, args...); err != nil {
+		return 0, fmt.Errorf("failed to count report blobs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *reportBlobRepository) countByStoragePathQuery(storagePath string) squirrel.SelectBuilder {
+	// Subqueries keep ? placeholders, numbered once in the outer query
+	blobs := squirrel.Select("COUNT(*)").
+		From("report_blobs").
+		Where(squirrel.Eq{"storage_path": storagePath})
+	versions := squirrel.Select("COUNT(*)").
+		From("download_versions").
+		Where(squirrel.Eq{"storage_path": storagePath})
+
+	return r.qb.Select().Column(squirrel.Expr("(?) + (?)", blobs, versions))
+}
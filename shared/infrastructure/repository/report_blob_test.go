@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportBlob_CountByStoragePath(t *testing.T) {
+	repositories, querier := newTestRepositories(t)
+	querier.scan = func(dest interface{}) { *dest.(*int64) = 2 }
+
+	count, err := repositories.ReportBlob().CountByStoragePath(t.Context(), "code4rena/1_vault/report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Blobs kept for previous versions are references too
+	query := querier.last(t)
+	assert.Equal(t,
+		"SELECT (SELECT COUNT(*) FROM report_blobs WHERE storage_path = $1) + "+
+			"(SELECT COUNT(*) FROM download_versions WHERE storage_path = $2)",
+		query.sql)
+	assert.Equal(t, []interface{}{"code4rena/1_vault/report.pdf", "code4rena/1_vault/report.pdf"}, query.args)
+}
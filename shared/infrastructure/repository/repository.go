@@ -7,10 +7,18 @@ import (
 )
 
 type Repositories struct {
-	download      ports.DownloadRepository
-	auditReport   ports.AuditReportRepository
-	auditProvider ports.AuditProviderRepository
-	process       ports.ProcessRepository
+	download          ports.DownloadRepository
+	downloadVersion   ports.DownloadVersionRepository
+	auditReport       ports.AuditReportRepository
+	auditReportDetail ports.AuditReportDetailRepository
+	auditProvider     ports.AuditProviderRepository
+	process           ports.ProcessRepository
+	source            ports.SourceRepository
+	outbox            ports.OutboxRepository
+	reportBlob        ports.ReportBlobRepository
+
+	logger  ports.Logger
+	metrics ports.Metrics
 }
 
 // NewRepositories creates all repository instances
@@ -20,47 +28,103 @@ func NewRepositories(db ports.Database, obs ports.Observability) (*Repositories,
 		return nil, fmt.Errorf("failed to get observability: %w", err)
 	}
 
+	return newRepositories(db, logger, metrics), nil
+}
+
+// WithTx returns repositories running every query inside tx
+func (r *Repositories) WithTx(tx ports.Transaction) ports.Repositories {
+	return newRepositories(tx, r.logger, r.metrics)
+}
+
+func newRepositories(db ports.Querier, logger ports.Logger, metrics ports.Metrics) *Repositories {
 	return &Repositories{
-		download:      newDownloadRepository(db, logger, metrics),
-		auditReport:   newAuditReportRepository(db, logger, metrics),
-		auditProvider: newAuditProviderRepository(db, logger, metrics),
-		process:       newProcessRepository(db, logger, metrics),
-	}, nil
+		download:          newDownloadRepository(db, logger, metrics),
+		downloadVersion:   newDownloadVersionRepository(db, logger, metrics),
+		auditReport:       newAuditReportRepository(db, logger, metrics),
+		auditReportDetail: newAuditReportDetailRepository(db, logger, metrics),
+		auditProvider:     newAuditProviderRepository(db, logger, metrics),
+		process:           newProcessRepository(db, logger, metrics),
+		source:            newSourceRepository(db, logger, metrics),
+		outbox:            newOutboxRepository(db, logger, metrics),
+		reportBlob:        newReportBlobRepository(db, logger, metrics),
+		logger:            logger,
+		metrics:           metrics,
+	}
 }
 
 // Each repository constructor
-func newDownloadRepository(db ports.Database, logger ports.Logger, metrics ports.Metrics) ports.DownloadRepository {
+func newDownloadRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.DownloadRepository {
 	repo := &downloadRepository{}
 	repo.baseRepository = newBaseRepository[entity.Download](db, logger, metrics, "downloads")
 	return repo
 }
 
-func newAuditReportRepository(db ports.Database, logger ports.Logger, metrics ports.Metrics) ports.AuditReportRepository {
+func newDownloadVersionRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.DownloadVersionRepository {
+	repo := &downloadVersionRepository{}
+	repo.baseRepository = newBaseRepository[entity.DownloadVersion](db, logger, metrics, "download_versions")
+	return repo
+}
+
+func newAuditReportRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.AuditReportRepository {
 	repo := &auditReportRepository{}
 	repo.baseRepository = newBaseRepository[entity.AuditReport](db, logger, metrics, "audit_reports")
+	repo.columns = auditReportColumns
+	return repo
+}
+
+func newAuditReportDetailRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.AuditReportDetailRepository {
+	repo := &auditReportDetailRepository{}
+	repo.baseRepository = newBaseRepository[entity.AuditReportDetail](db, logger, metrics, "audit_report_details")
+	repo.columns = auditReportDetailColumns
 	return repo
 }
 
-func newAuditProviderRepository(db ports.Database, logger ports.Logger, metrics ports.Metrics) ports.AuditProviderRepository {
+func newAuditProviderRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.AuditProviderRepository {
 	repo := &auditProviderRepository{}
 	repo.baseRepository = newBaseRepository[entity.AuditProvider](db, logger, metrics, "audit_providers")
 	return repo
 }
 
-func newProcessRepository(db ports.Database, logger ports.Logger, metrics ports.Metrics) ports.ProcessRepository {
+func newProcessRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.ProcessRepository {
 	repo := &processRepository{}
 	repo.baseRepository = newBaseRepository[entity.Process](db, logger, metrics, "processes")
 	return repo
 }
 
+func newSourceRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.SourceRepository {
+	repo := &sourceRepository{}
+	repo.baseRepository = newBaseRepository[entity.Source](db, logger, metrics, "sources")
+	return repo
+}
+
+func newOutboxRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.OutboxRepository {
+	repo := &outboxRepository{}
+	repo.baseRepository = newBaseRepository[entity.OutboxMessage](db, logger, metrics, "outbox")
+	return repo
+}
+
+func newReportBlobRepository(db ports.Querier, logger ports.Logger, metrics ports.Metrics) ports.ReportBlobRepository {
+	repo := &reportBlobRepository{}
+	repo.baseRepository = newBaseRepository[entity.ReportBlob](db, logger, metrics, "report_blobs")
+	return repo
+}
+
 func (r *Repositories) Download() ports.DownloadRepository {
 	return r.download
 }
 
+func (r *Repositories) DownloadVersion() ports.DownloadVersionRepository {
+	return r.downloadVersion
+}
+
 func (r *Repositories) AuditReport() ports.AuditReportRepository {
 	return r.auditReport
 }
 
+func (r *Repositories) AuditReportDetail() ports.AuditReportDetailRepository {
+	return r.auditReportDetail
+}
+
 func (r *Repositories) AuditProvider() ports.AuditProviderRepository {
 	return r.auditProvider
 }
@@ -68,3 +132,15 @@ func (r *Repositories) AuditProvider() ports.AuditProviderRepository {
 func (r *Repositories) Process() ports.ProcessRepository {
 	return r.process
 }
+
+func (r *Repositories) Source() ports.SourceRepository {
+	return r.source
+}
+
+func (r *Repositories) Outbox() ports.OutboxRepository {
+	return r.outbox
+}
+
+func (r *Repositories) ReportBlob() ports.ReportBlobRepository {
+	return r.reportBlob
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordedQuery is a statement run through a recordingQuerier
+type recordedQuery struct {
+	sql  string
+	args []interface{}
+}
+
+// recordingQuerier records the statements of a repository instead of running
+// them. Get and Select fill dest through scan when it is set, or fail with
+// err.
+type recordingQuerier struct {
+	queries []recordedQuery
+	scan    func(dest interface{})
+	err     error
+}
+
+func (q *recordingQuerier) record(query string, args []interface{}) {
+	q.queries = append(q.queries, recordedQuery{sql: query, args: args})
+}
+
+func (q *recordingQuerier) last(t *testing.T) recordedQuery {
+	t.Helper()
+	require.NotEmpty(t, q.queries, "no query was run")
+	return q.queries[len(q.queries)-1]
+}
+
+func (q *recordingQuerier) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	q.record(query, args)
+	return driverResult(1), nil
+}
+
+func (q *recordingQuerier) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	q.record(query, args)
+	return nil, sql.ErrConnDone
+}
+
+func (q *recordingQuerier) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	q.record(query, args)
+	return nil
+}
+
+func (q *recordingQuerier) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	q.record(query, args)
+	if q.err != nil {
+		return q.err
+	}
+	if q.scan != nil {
+		q.scan(dest)
+	}
+	return nil
+}
+
+func (q *recordingQuerier) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	q.record(query, args)
+	if q.err != nil {
+		return q.err
+	}
+	if q.scan != nil {
+		q.scan(dest)
+	}
+	return nil
+}
+
+// driverResult is the result of a statement affecting that many rows
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+// newTestRepositories returns repositories recording their statements in the
+// returned querier
+func newTestRepositories(t *testing.T) (*Repositories, *recordingQuerier) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+	logger, metrics, err := obs.ComponentsScoped("repository")
+	require.NoError(t, err)
+
+	querier := &recordingQuerier{}
+	return newRepositories(querier, logger, metrics), querier
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"shared/domain/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type sourceRepository struct {
+	*baseRepository[entity.Source]
+}
+
+func (r *sourceRepository) Create(ctx context.Context, source *entity.Source) error {
+	query := r.qb.Insert("sources").
+		Columns(
+			"provider_id", "name", "engagement_type", "index_page_url",
+			"scraper_type", "is_active", "created_at", "updated_at",
+		).
+		Values(
+			source.ProviderID, source.Name, source.EngagementType, source.IndexPageURL,
+			source.ScraperType, source.IsActive, source.CreatedAt, source.UpdatedAt,
+		).
+		Suffix("RETURNING id")
+
+	sql, args, _ := query.ToSql()
+	row := r.db.QueryRow(ctx, sql, args...)
+	err := row.Scan(&source.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create source: %w", err)
+	}
+	return nil
+}
+
+func (r *sourceRepository) Update(ctx context.Context, source *entity.Source) error {
+	query := r.qb.Update("sources").
+		Set("name", source.Name).
+		Set("is_active", source.IsActive).
+		Set("updated_at", source.UpdatedAt)
+
+	// Update nullable fields only if they have values
+	if source.ScraperType != nil {
+		query = query.Set("scraper_type", *source.ScraperType)
+	}
+	if source.LastVisitedAt != nil {
+		query = query.Set("last_visited_at", *source.LastVisitedAt)
+	}
+	if source.LastReportsCount != nil {
+		query = query.Set("last_reports_count", *source.LastReportsCount)
+	}
+	if source.LastMainDivHash != nil {
+		query = query.Set("last_main_div_hash", *source.LastMainDivHash)
+	}
+
+	query = query.Where(squirrel.Eq{"id": source.ID})
+
+	sql, args, _ := query.ToSql()
+	_, err := r.db.Execute(ctx, sql, args...)
+	return err
+}
+
+func (r *sourceRepository) ListDueForVisit(ctx context.Context, visitedBefore, scheduledBefore time.Time, limit int) ([]*entity.Source, error) {
+	query := r.qb.Select("*").
+		From("sources").
+		Where(squirrel.Eq{"is_active": true}).
+		Where(squirrel.NotEq{"scraper_type": nil}).
+		Where(squirrel.Or{
+			squirrel.Eq{"last_visited_at": nil},
+			squirrel.Lt{"last_visited_at": visitedBefore},
+		}).
+		Where(notScheduledSince(scheduledBefore)).
+		OrderBy("last_visited_at ASC NULLS FIRST").
+		Limit(uint64(limit))
+
+	sql, args, _ := query.ToSql()
+
+	var sources []entity.Source
+	if err := r.db.Select(ctx, &sources, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to list sources due for visit: %w", err)
+	}
+
+	result := make([]*entity.Source, len(sources))
+	for i := range sources {
+		result[i] = &sources[i]
+	}
+	return result, nil
+}
+
+func (r *sourceRepository) ScheduleCrawl(ctx context.Context, source *entity.Source, scheduledBefore time.Time) (bool, error) {
+	query := r.qb.Update("sources").
+		Set("crawl_scheduled_at", source.CrawlScheduledAt).
+		Set("updated_at", source.UpdatedAt).
+		Where(squirrel.Eq{"id": source.ID}).
+		Where(notScheduledSince(scheduledBefore))
+
+	sql, args, _ := query.ToSql()
+	result, err := r.db.Execute(ctx, sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule crawl: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// notScheduledSince matches the sources whose last crawl, if any, was
+// scheduled before scheduledBefore
+func notScheduledSince(scheduledBefore time.Time) squirrel.Sqlizer {
+	return squirrel.Or{
+		squirrel.Eq{"crawl_scheduled_at": nil},
+		squirrel.Lt{"crawl_scheduled_at": scheduledBefore},
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"shared/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_ListDueForVisit(t *testing.T) {
+	visitedBefore := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	scheduledBefore := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+	repositories, querier := newTestRepositories(t)
+
+	_, err := repositories.Source().ListDueForVisit(t.Context(), visitedBefore, scheduledBefore, 100)
+	require.NoError(t, err)
+
+	query := querier.last(t)
+	assert.Equal(t, "SELECT * FROM sources WHERE is_active = $1 AND scraper_type IS NOT NULL"+
+		" AND (last_visited_at IS NULL OR last_visited_at < $2)"+
+		" AND (crawl_scheduled_at IS NULL OR crawl_scheduled_at < $3)"+
+		" ORDER BY last_visited_at ASC NULLS FIRST LIMIT 100", query.sql)
+	assert.Equal(t, []interface{}{true, visitedBefore, scheduledBefore}, query.args)
+}
+
+func TestSource_ScheduleCrawl(t *testing.T) {
+	now := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	scheduledBefore := now.Add(-time.Hour)
+	source := &entity.Source{ID: 7}
+	source.ScheduleCrawl(now)
+	repositories, querier := newTestRepositories(t)
+
+	scheduled, err := repositories.Source().ScheduleCrawl(t.Context(), source, scheduledBefore)
+	require.NoError(t, err)
+	assert.True(t, scheduled)
+
+	// Of two ticks scheduling the source, the second matches no row
+	query := querier.last(t)
+	assert.Equal(t, "UPDATE sources SET crawl_scheduled_at = $1, updated_at = $2"+
+		" WHERE id = $3 AND (crawl_scheduled_at IS NULL OR crawl_scheduled_at < $4)", query.sql)
+	assert.Equal(t, []interface{}{&now, now, int64(7), scheduledBefore}, query.args)
+}
@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"shared/application/middleware"
+	"shared/application/ports"
+	"shared/infrastructure/config"
+	"shared/infrastructure/idempotency"
+)
+
+// NewChain wraps handler with the middleware every runtime applies to the
+// requests it hands over, outermost first: panics are recovered, requests
+// logged and measured, unreadable ones rejected, redelivered ones skipped,
+// and the handler given at most timeout.
+func NewChain(handler ports.Handler, cfg *config.MiddlewareConfig, timeout time.Duration, obs ports.Observability) (ports.Handler, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler is required")
+	}
+
+	logger, metrics, err := obs.ComponentsScoped("runtime.middleware")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return middleware.Chain(handler,
+		middleware.Recovery(logger, metrics),
+		middleware.Logging(logger),
+		middleware.Metrics(metrics),
+		middleware.Validation(logger, metrics),
+		middleware.Idempotency(idempotency.NewMemoryStore(), cfg.IdempotencyTTL, logger, metrics),
+		middleware.Timeout(timeout),
+	), nil
+}
+
+// requestTimeout is the time the handler is given for one request by the
+// configured runtime
+func requestTimeout(cfg *config.Config) time.Duration {
+	switch cfg.Adapters.Runtime {
+	case "lambda":
+		return cfg.Lambda.Timeout
+	case "http":
+		return cfg.HTTP.Timeout
+	case "rabbitmq":
+		return cfg.Queue.RabbitMQ.Timeout
+	case "schedule":
+		return cfg.Schedule.Interval
+	default:
+		return 0
+	}
+}
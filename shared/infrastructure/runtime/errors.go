@@ -0,0 +1,9 @@
+package runtime
+
+import (
+	"fmt"
+)
+
+func ErrNoRoute(requestType string) error {
+	return fmt.Errorf("no handler for request type %q", requestType)
+}
@@ -7,15 +7,24 @@ import (
 	"shared/infrastructure/config"
 )
 
-// Create creates the appropriate runtime based on configuration
-func Create(cfg *config.Config, handler ports.Handler, obs ports.Observability) (ports.Runtime, error) {
+// Create creates the appropriate runtime based on configuration, handing
+// requests to handler through the middleware chain. Only the HTTP runtime
+// serves endpoints.
+func Create(cfg *config.Config, handler ports.Handler, obs ports.Observability, endpoints ...ports.Endpoints) (ports.Runtime, error) {
+	handler, err := NewChain(handler, &cfg.Middleware, requestTimeout(cfg), obs)
+	if err != nil {
+		return nil, fmt.Errorf("middleware chain creation: %w", err)
+	}
+
 	switch cfg.Adapters.Runtime {
 	case "lambda":
 		return NewLambdaRuntime(&cfg.Lambda, handler, obs), nil
 	case "http":
-		return NewHTTPRuntime(&cfg.HTTP, handler, obs), nil
+		return NewHTTPRuntime(&cfg.HTTP, handler, obs, endpoints...), nil
 	case "rabbitmq":
 		return NewRabbitMQRuntime(&cfg.Queue, handler, obs), nil
+	case "schedule":
+		return NewScheduleRuntime(&cfg.Schedule, handler, obs), nil
 	default:
 		return nil, fmt.Errorf("unsupported handler adapter: %s", cfg.Adapters.Runtime)
 	}
@@ -14,15 +14,17 @@ import (
 
 // handles HTTP server runtime integration
 type httpRuntime struct {
-	handler ports.Handler
-	logger  ports.Logger
-	metrics ports.Metrics
-	config  *config.HTTPConfig
-	server  *http.Server
+	handler   ports.Handler
+	endpoints []ports.Endpoints
+	logger    ports.Logger
+	metrics   ports.Metrics
+	config    *config.HTTPConfig
+	server    *http.Server
 }
 
-// NewAdapter creates a new HTTP adapter
-func NewHTTPRuntime(cfg *config.HTTPConfig, handler ports.Handler, obs ports.Observability) ports.Runtime {
+// NewAdapter creates a new HTTP adapter. Requests are posted to / for the
+// handler; endpoints add read-only routes next to it.
+func NewHTTPRuntime(cfg *config.HTTPConfig, handler ports.Handler, obs ports.Observability, endpoints ...ports.Endpoints) ports.Runtime {
 	logger, metrics, err := obs.ComponentsScoped("runtime.http")
 	if err != nil {
 		fmt.Errorf("failed to create runtime: Obervability was not initialized %w", err)
@@ -37,10 +39,11 @@ func NewHTTPRuntime(cfg *config.HTTPConfig, handler ports.Handler, obs ports.Obs
 	}
 
 	return &httpRuntime{
-		logger:  logger,
-		metrics: metrics,
-		handler: handler,
-		config:  cfg,
+		logger:    logger,
+		metrics:   metrics,
+		handler:   handler,
+		endpoints: endpoints,
+		config:    cfg,
 	}
 }
 
@@ -48,6 +51,9 @@ func NewHTTPRuntime(cfg *config.HTTPConfig, handler ports.Handler, obs ports.Obs
 func (httpRuntime *httpRuntime) Start() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", httpRuntime.handleRequest)
+	for _, endpoints := range httpRuntime.endpoints {
+		endpoints.Register(mux)
+	}
 
 	httpRuntime.server = &http.Server{
 		Addr:         httpRuntime.config.Addr,
@@ -106,22 +112,11 @@ func (httpRuntime *httpRuntime) handleRequest(resWriter http.ResponseWriter, req
 	// Add HTTP metadata
 	httpRuntime.enrichRequest(&httpRuntimeReq, request)
 
-	// Apply timeout if configured
-	ctx := request.Context()
-	if httpRuntime.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, httpRuntime.config.Timeout)
-		defer cancel()
-	}
-
-	// Process request
-	resp, err := httpRuntime.handler.Handle(ctx, httpRuntimeReq)
+	// Process request, logged by the handler chain
+	resp, err := httpRuntime.handler.Handle(request.Context(), httpRuntimeReq)
 
 	// Send response
 	httpRuntime.sendResponse(resWriter, resp, err)
-
-	// Log result
-	httpRuntime.logRequestComplete(httpRuntimeReq, resp, err)
 }
 
 // enrichRequest adds HTTP-specific metadata to the request
@@ -229,23 +224,6 @@ func (httpRuntime *httpRuntime) logStartup() {
 	httpRuntime.metrics.IncrementCounter("http.starts", nil)
 }
 
-func (httpRuntime *httpRuntime) logRequestComplete(req ports.RuntimeRequest, resp ports.RuntimeResponse, err error) {
-	logger := httpRuntime.logger
-
-	if err != nil {
-		logger.Error("Request processing failed",
-			"request_id", req.ID,
-			"error", err)
-	} else if !resp.Success {
-		logger.Info("Request processing unsuccessful",
-			"request_id", req.ID,
-			"error", resp.Error)
-	} else {
-		logger.Info("Request processed successfully",
-			"request_id", req.ID)
-	}
-}
-
 // --- Metrics Helpers ---
 
 type requestTracker struct {
@@ -100,9 +100,10 @@ type batchProcessor struct {
 }
 
 type batchStats struct {
-	successCount int
-	failureCount int
-	totalCount   int
+	successCount  int
+	rejectedCount int
+	failureCount  int
+	totalCount    int
 }
 
 func newBatchProcessor(h ports.Handler, logger ports.Logger, metrics ports.Metrics, cfg *config.LambdaConfig) *batchProcessor {
@@ -120,35 +121,35 @@ func newBatchProcessor(h ports.Handler, logger ports.Logger, metrics ports.Metri
 func (b *batchProcessor) process(ctx context.Context, event events.SQSEvent) events.SQSEventResponse {
 	b.stats.totalCount = len(event.Records)
 
-	for i, record := range event.Records {
-		b.processMessage(ctx, record, i)
+	for _, record := range event.Records {
+		b.processMessage(ctx, record)
 	}
 
 	return b.response
 }
 
-func (b *batchProcessor) processMessage(ctx context.Context, record events.SQSMessage, index int) {
-	b.logMessageStart(record, index)
+// processMessage hands one message to the handler; its chain logs and
+// measures the outcome, the batch only tells SQS which messages to keep
+func (b *batchProcessor) processMessage(ctx context.Context, record events.SQSMessage) {
+	resp, err := b.handler.Handle(ctx, b.convertToRequest(record))
 
-	request := b.convertToRequest(record)
-	reqCtx := b.applyTimeout(ctx)
-
-	resp, err := b.handler.Handle(reqCtx, request)
-
-	if b.isFailure(resp, err) {
-		b.handleFailure(record, err, resp)
-	} else {
+	switch {
+	case b.isFailure(resp, err):
+		b.handleFailure(record)
+	case !resp.Success:
+		// Acknowledged failure, SQS deletes the message
+		b.stats.rejectedCount++
+	default:
 		b.stats.successCount++
 	}
 }
 
 func (b *batchProcessor) isFailure(resp ports.RuntimeResponse, err error) bool {
-	return err != nil || !resp.Success
+	return err != nil || (!resp.Success && !resp.Ack)
 }
 
-func (b *batchProcessor) handleFailure(record events.SQSMessage, err error, resp ports.RuntimeResponse) {
+func (b *batchProcessor) handleFailure(record events.SQSMessage) {
 	b.stats.failureCount++
-	b.logMessageFailure(record, err, resp)
 
 	if b.config.EnablePartialBatchFailure {
 		b.response.BatchItemFailures = append(b.response.BatchItemFailures,
@@ -169,15 +170,6 @@ func (b *batchProcessor) convertToRequest(record events.SQSMessage) ports.Runtim
 	}
 }
 
-func (b *batchProcessor) applyTimeout(ctx context.Context) context.Context {
-	if b.config.Timeout <= 0 {
-		return ctx
-	}
-
-	reqCtx, _ := context.WithTimeout(ctx, b.config.Timeout)
-	return reqCtx
-}
-
 func (b *batchProcessor) getStats() batchStats {
 	return b.stats
 }
@@ -194,7 +186,6 @@ func (b *batchProcessor) getError() error {
 
 // processDirectRequest handles direct handler requests
 func (runtime *lambdaRuntime) processDirectRequest(ctx context.Context, req ports.RuntimeRequest) (interface{}, error) {
-	runtime.logDirectRequest(req)
 	runtime.recordDirectRequestMetric()
 
 	return runtime.handler.Handle(ctx, req)
@@ -263,28 +254,11 @@ func (runtime *lambdaRuntime) logBatchComplete(stats batchStats, event events.SQ
 	runtime.logger.Info("SQS batch processing complete",
 		"total_messages", stats.totalCount,
 		"success_count", stats.successCount,
+		"rejected_count", stats.rejectedCount,
 		"failure_count", stats.failureCount,
 		"partial_batch_enabled", runtime.config.EnablePartialBatchFailure)
 }
 
-func (b *batchProcessor) logMessageStart(record events.SQSMessage, index int) {
-	b.logger.Info("Processing SQS message",
-		"message_id", record.MessageId,
-		"position", index+1,
-		"total", b.stats.totalCount)
-}
-
-func (b *batchProcessor) logMessageFailure(record events.SQSMessage, err error, resp ports.RuntimeResponse) {
-	b.logger.Error("Message processing failed",
-		"message_id", record.MessageId,
-		"error", err,
-		"response_success", resp.Success)
-}
-
-func (runtime *lambdaRuntime) logDirectRequest(req ports.RuntimeRequest) {
-	runtime.logger.Info("Processing direct request", "request_id", req.ID)
-}
-
 // --- Metrics Helpers ---
 
 // invocationTracker tracks metrics for a single invocation
@@ -322,6 +296,7 @@ func (runtime *lambdaRuntime) recordBatchMetrics(event events.SQSEvent) {
 
 func (runtime *lambdaRuntime) recordBatchResults(stats batchStats) {
 	runtime.metrics.RecordHistogram("lambda.batch.success_count", float64(stats.successCount), nil)
+	runtime.metrics.RecordHistogram("lambda.batch.rejected_count", float64(stats.rejectedCount), nil)
 	runtime.metrics.RecordHistogram("lambda.batch.failure_count", float64(stats.failureCount), nil)
 
 	switch {
@@ -111,18 +111,9 @@ func (runtime *rabbitmqRuntime) Start() error {
 	return nil
 }
 
-// processMessage handles a single message
+// processMessage hands a single message to the handler, whose chain logs and
+// measures it, and settles it with the broker
 func (runtime *rabbitmqRuntime) processMessage(msg amqp.Delivery) {
-	startTime := time.Now()
-
-	// Create context with timeout
-	ctx := context.Background()
-	if runtime.config.RabbitMQ.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, runtime.config.RabbitMQ.Timeout)
-		defer cancel()
-	}
-
 	// Convert to ports.RuntimeRequest
 	req := ports.RuntimeRequest{
 		ID:        msg.MessageId,
@@ -140,55 +131,40 @@ func (runtime *rabbitmqRuntime) processMessage(msg amqp.Delivery) {
 	if req.Timestamp.IsZero() {
 		req.Timestamp = time.Now().UTC()
 	}
-
-	// Log request
-	runtime.logger.Info("Processing RabbitMQ message",
-		"message_id", req.ID,
-		"routing_key", msg.RoutingKey,
-		"redelivered", msg.Redelivered)
 	runtime.metrics.IncrementCounter("rabbitmq.messages", nil)
 
 	// Process
-	resp, err := runtime.handler.Handle(ctx, req)
+	resp, err := runtime.handler.Handle(context.Background(), req)
 
 	// Handle result
-	if err == nil && resp.Success {
-		// Success - acknowledge
+	if err == nil && (resp.Success || resp.Ack) {
+		// Success, or permanent failure - acknowledge so it is not delivered again
 		if err := msg.Ack(false); err != nil {
 			runtime.logger.Error("Failed to ack message",
 				"id", req.ID,
 				"error", err)
 		}
-		runtime.logger.Info("Message processed successfully",
-			"id", req.ID,
-			"duration_ms", time.Since(startTime).Milliseconds())
-		runtime.metrics.IncrementCounter("rabbitmq.success", nil)
-	} else {
-		// Failure - reject and requeue if not already redelivered
-		requeue := !msg.Redelivered
-		if err := msg.Nack(false, requeue); err != nil {
-			runtime.logger.Error("Failed to nack message",
-				"id", req.ID,
-				"error", err)
+		if resp.Success {
+			runtime.metrics.IncrementCounter("rabbitmq.success", nil)
+		} else {
+			runtime.metrics.IncrementCounter("rabbitmq.rejected", nil)
 		}
-
-		if err != nil {
-			runtime.logger.Error("Message processing failed",
-				"id", req.ID,
-				"error", err,
-				"requeued", requeue)
-		} else if !resp.Success {
-			runtime.logger.Error("Message processing unsuccessful",
-				"id", req.ID,
-				"error", resp.Error,
-				"requeued", requeue)
-		}
-		runtime.metrics.IncrementCounter("rabbitmq.failure", nil)
+		return
 	}
 
-	// Record duration
-	runtime.metrics.RecordHistogram("rabbitmq.duration_ms",
-		float64(time.Since(startTime).Milliseconds()), nil)
+	// Failure - reject and requeue if not already redelivered
+	requeue := !msg.Redelivered
+	if err := msg.Nack(false, requeue); err != nil {
+		runtime.logger.Error("Failed to nack message",
+			"id", req.ID,
+			"error", err)
+	}
+	if !requeue {
+		runtime.logger.Error("Message dropped after redelivery failed",
+			"id", req.ID,
+			"routing_key", msg.RoutingKey)
+	}
+	runtime.metrics.IncrementCounter("rabbitmq.failure", map[string]string{"requeued": fmt.Sprint(requeue)})
 }
 
 // Stop gracefully shuts down the consumer
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"shared/application/ports"
+)
+
+// Router is a ports.Handler dispatching each request to the handler routed
+// for its type, so that one process can serve several worker roles.
+//
+// The type of a request is RuntimeRequest.Type when a handler is routed for
+// it, else the event_type of its payload: queues set Type to their routing
+// key or leave it empty, while every event carries its event_type. Requests
+// of no routed type go to the fallback handler, or are rejected without
+// redelivery when there is none.
+type Router struct {
+	logger  ports.Logger
+	metrics ports.Metrics
+
+	mu       sync.RWMutex
+	routes   map[string]ports.Handler
+	fallback ports.Handler
+}
+
+func NewRouter(obs ports.Observability) (*Router, error) {
+	logger, metrics, err := obs.ComponentsScoped("runtime.router")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observability: %w", err)
+	}
+
+	return &Router{
+		logger:  logger,
+		metrics: metrics,
+		routes:  make(map[string]ports.Handler),
+	}, nil
+}
+
+// Route sends requests of the given types to handler. Routing a type twice
+// panics, like registering a pattern twice on an http.ServeMux.
+func (r *Router) Route(handler ports.Handler, types ...string) {
+	if handler == nil {
+		panic("runtime: nil handler routed")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range types {
+		if t == "" {
+			panic("runtime: empty request type routed")
+		}
+		if _, exists := r.routes[t]; exists {
+			panic(fmt.Sprintf("runtime: request type %q routed twice", t))
+		}
+		r.routes[t] = handler
+	}
+}
+
+// Fallback sends requests of no routed type to handler
+func (r *Router) Fallback(handler ports.Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = handler
+}
+
+func (r *Router) Handle(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	requestType, handler := r.match(req)
+	if handler != nil {
+		r.metrics.IncrementCounter("router.requests", map[string]string{"type": requestType})
+		return handler.Handle(ctx, req)
+	}
+
+	r.mu.RLock()
+	fallback := r.fallback
+	r.mu.RUnlock()
+	if fallback != nil {
+		r.metrics.IncrementCounter("router.requests", map[string]string{"type": "fallback"})
+		return fallback.Handle(ctx, req)
+	}
+
+	r.metrics.IncrementCounter("router.unrouted", nil)
+	r.logger.Error("No handler for request",
+		"request_id", req.ID,
+		"type", requestType)
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   ErrNoRoute(requestType).Error(),
+		Ack:     true,
+	}, nil
+}
+
+// match returns the type of req and its handler, nil when none is routed
+func (r *Router) match(req ports.RuntimeRequest) (string, ports.Handler) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if handler, ok := r.routes[req.Type]; ok {
+		return req.Type, handler
+	}
+
+	eventType := req.EventType()
+	if handler, ok := r.routes[eventType]; ok {
+		return eventType, handler
+	}
+
+	if req.Type != "" {
+		return req.Type, nil
+	}
+	return eventType, nil
+}
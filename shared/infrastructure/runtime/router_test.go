@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"shared/application/ports"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestObservability(t *testing.T) ports.Observability {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+	return obs
+}
+
+// namedHandler answers with its name as data, to tell which handler was routed
+func namedHandler(name string) ports.Handler {
+	return ports.HandlerFunc(func(ctx context.Context, req ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+		data, _ := json.Marshal(name)
+		return ports.RuntimeResponse{Success: true, Data: data}, nil
+	})
+}
+
+func newTestRouter(t *testing.T) *Router {
+	t.Helper()
+	router, err := NewRouter(newTestObservability(t))
+	require.NoError(t, err)
+	router.Route(namedHandler("download"), "download.requested", "download.retry")
+	router.Route(namedHandler("process"), "process.requested")
+	return router
+}
+
+func handledBy(t *testing.T, resp ports.RuntimeResponse) string {
+	t.Helper()
+	var name string
+	require.NoError(t, json.Unmarshal(resp.Data, &name))
+	return name
+}
+
+func TestRouter_Handle(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ports.RuntimeRequest
+		handler string
+	}{
+		{
+			name:    "request type",
+			req:     ports.RuntimeRequest{ID: "1", Type: "download.retry"},
+			handler: "download",
+		},
+		{
+			name:    "event type of the payload when type is a queue name",
+			req:     ports.RuntimeRequest{ID: "2", Type: "processor", Payload: json.RawMessage(`{"event_type":"process.requested"}`)},
+			handler: "process",
+		},
+		{
+			name:    "event type of the payload when type is empty",
+			req:     ports.RuntimeRequest{ID: "3", Payload: json.RawMessage(`{"event_type":"download.requested"}`)},
+			handler: "download",
+		},
+		{
+			name:    "request type before the event type",
+			req:     ports.RuntimeRequest{ID: "4", Type: "process.requested", Payload: json.RawMessage(`{"event_type":"download.requested"}`)},
+			handler: "process",
+		},
+	}
+
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := router.Handle(context.Background(), tt.req)
+			require.NoError(t, err)
+			assert.True(t, resp.Success)
+			assert.Equal(t, tt.handler, handledBy(t, resp))
+		})
+	}
+}
+
+func TestRouter_Fallback(t *testing.T) {
+	router := newTestRouter(t)
+	router.Fallback(namedHandler("fallback"))
+
+	resp, err := router.Handle(context.Background(), ports.RuntimeRequest{
+		ID:      "1",
+		Type:    "extractor",
+		Payload: json.RawMessage(`{"event_type":"leaf"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", handledBy(t, resp))
+}
+
+func TestRouter_Unrouted(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ports.RuntimeRequest
+		err  string
+	}{
+		{
+			name: "unknown type",
+			req:  ports.RuntimeRequest{ID: "1", Type: "extractor", Payload: json.RawMessage(`{"event_type":"leaf"}`)},
+			err:  ErrNoRoute("extractor").Error(),
+		},
+		{
+			name: "unknown event type",
+			req:  ports.RuntimeRequest{ID: "2", Payload: json.RawMessage(`{"event_type":"leaf"}`)},
+			err:  ErrNoRoute("leaf").Error(),
+		},
+		{
+			name: "payload not an object",
+			req:  ports.RuntimeRequest{ID: "3", Payload: json.RawMessage(`[]`)},
+			err:  ErrNoRoute("").Error(),
+		},
+	}
+
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := router.Handle(context.Background(), tt.req)
+			require.NoError(t, err)
+			assert.False(t, resp.Success)
+			assert.True(t, resp.Ack, "an unrouted request must not be redelivered")
+			assert.Equal(t, tt.err, resp.Error)
+		})
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	router := newTestRouter(t)
+
+	assert.Panics(t, func() { router.Route(namedHandler("other"), "download.retry") })
+	assert.Panics(t, func() { router.Route(namedHandler("other"), "") })
+	assert.Panics(t, func() { router.Route(nil, "extract") })
+}
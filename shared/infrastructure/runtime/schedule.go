@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"shared/application/ports"
+	"shared/infrastructure/config"
+)
+
+// ScheduleTickType is the request type sent by the schedule runtime
+const ScheduleTickType = "schedule.tick"
+
+// handles periodic invocation of the handler, for workers driven by time
+// rather than by messages. On Lambda the same ticks come from a scheduled rule.
+type scheduleRuntime struct {
+	handler ports.Handler
+	logger  ports.Logger
+	metrics ports.Metrics
+	config  *config.ScheduleConfig
+}
+
+// NewScheduleRuntime creates a new schedule runtime
+func NewScheduleRuntime(cfg *config.ScheduleConfig, handler ports.Handler, obs ports.Observability) ports.Runtime {
+	logger, metrics, err := obs.ComponentsScoped("runtime.schedule")
+	if err != nil {
+		panic(fmt.Errorf("failed to create runtime: Observability was not initialized %w", err))
+	}
+
+	if handler == nil {
+		panic(fmt.Errorf("failed to create runtime: handler is required"))
+	}
+
+	return &scheduleRuntime{
+		handler: handler,
+		logger:  logger,
+		metrics: metrics,
+		config:  cfg,
+	}
+}
+
+// Start ticks once immediately, then every interval, and never returns
+func (runtime *scheduleRuntime) Start() error {
+	runtime.logger.Info("Starting schedule runtime", "interval", runtime.config.Interval.String())
+	runtime.metrics.IncrementCounter("schedule.starts", nil)
+
+	ticker := time.NewTicker(runtime.config.Interval)
+	defer ticker.Stop()
+
+	runtime.tick(time.Now().UTC())
+	for now := range ticker.C {
+		runtime.tick(now.UTC())
+	}
+
+	return nil
+}
+
+// tick invokes the handler once, through the chain bounding it to the
+// interval and logging it
+func (runtime *scheduleRuntime) tick(now time.Time) {
+	request := ports.RuntimeRequest{
+		ID:        fmt.Sprintf("schedule-%d", now.UnixNano()),
+		Source:    "schedule",
+		Type:      ScheduleTickType,
+		Payload:   json.RawMessage(`{}`),
+		Metadata:  map[string]string{},
+		Timestamp: now,
+	}
+
+	resp, err := runtime.handler.Handle(context.Background(), request)
+	if err != nil || !resp.Success {
+		runtime.metrics.IncrementCounter("schedule.failures", nil)
+		return
+	}
+	runtime.metrics.IncrementCounter("schedule.ticks", nil)
+}
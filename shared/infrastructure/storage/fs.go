@@ -69,6 +69,9 @@ func (s *Storage) Put(ctx context.Context, bucket, key string, reader io.Reader,
 
 	bytesWritten, err := io.Copy(file, reader)
 	if err != nil {
+		// Don't leave a truncated object behind
+		file.Close()
+		os.Remove(objectPath)
 		s.logger.Error("Failed to write data", "bucket", bucket, "key", key, "error", err)
 		s.metrics.IncrementCounter("storage.put.errors", map[string]string{"bucket": bucket, "error": "write"})
 		return fmt.Errorf("failed to write data: %w", err)
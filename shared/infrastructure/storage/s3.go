@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -12,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 
@@ -19,12 +19,19 @@ import (
 	"shared/infrastructure/config"
 )
 
+// Multipart upload tuning: memory held per upload is roughly PartSize * Concurrency
+const (
+	uploadPartSize    = 5 * 1024 * 1024 // S3 minimum part size
+	uploadConcurrency = 3
+)
+
 // Client implements ObjectStorage interface for AWS S3
 type Client struct {
-	s3      *s3.Client
-	config  *config.StorageConfig
-	logger  ports.Logger
-	metrics ports.Metrics
+	s3       *s3.Client
+	uploader *manager.Uploader
+	config   *config.StorageConfig
+	logger   ports.Logger
+	metrics  ports.Metrics
 }
 
 // New creates a new S3 storage client
@@ -105,11 +112,17 @@ func (b *clientBuilder) createClient(awsCfg aws.Config) *Client {
 		o.UsePathStyle = true
 	})
 
+	uploader := manager.NewUploader(s3Client, func(u *manager.Uploader) {
+		u.PartSize = uploadPartSize
+		u.Concurrency = uploadConcurrency
+	})
+
 	return &Client{
-		s3:      s3Client,
-		config:  b.config,
-		logger:  b.logger,
-		metrics: b.metrics,
+		s3:       s3Client,
+		uploader: uploader,
+		config:   b.config,
+		logger:   b.logger,
+		metrics:  b.metrics,
 	}
 }
 
@@ -240,41 +253,23 @@ func (c *Client) newPutOperation(bucket, key string, reader io.Reader, metadata
 func (op *putOperation) Execute(ctx context.Context) error {
 	timer := op.client.startTimer()
 
-	content, size, err := op.readContent()
-	if err != nil {
-		op.recordError("read_error")
-		return err
-	}
-
-	input := op.buildInput(content)
+	body := &countingReader{reader: op.reader}
+	input := op.buildInput(body)
 
 	if err := op.upload(ctx, input); err != nil {
 		op.recordError("s3_error")
 		return err
 	}
 
-	op.recordSuccess(timer.Elapsed(), size)
+	op.recordSuccess(timer.Elapsed(), body.count)
 	return nil
 }
 
-func (op *putOperation) readContent() ([]byte, int64, error) {
-	buf := &bytes.Buffer{}
-	size, err := io.Copy(buf, op.reader)
-	if err != nil {
-		op.client.logger.Error("Failed to read content",
-			"error", err,
-			"bucket", op.bucket,
-			"key", op.key)
-		return nil, 0, fmt.Errorf("failed to read content: %w", err)
-	}
-	return buf.Bytes(), size, nil
-}
-
-func (op *putOperation) buildInput(content []byte) *s3.PutObjectInput {
+func (op *putOperation) buildInput(body io.Reader) *s3.PutObjectInput {
 	input := &s3.PutObjectInput{
 		Bucket:      aws.String(op.bucket),
 		Key:         aws.String(op.key),
-		Body:        bytes.NewReader(content),
+		Body:        body,
 		ContentType: aws.String(op.metadata.ContentType),
 	}
 
@@ -294,8 +289,10 @@ func (op *putOperation) applyOptionalMetadata(input *s3.PutObjectInput) {
 	}
 }
 
+// upload streams the body through the multipart uploader, so only a few
+// parts are ever held in memory regardless of the object size
 func (op *putOperation) upload(ctx context.Context, input *s3.PutObjectInput) error {
-	_, err := op.client.s3.PutObject(ctx, input)
+	_, err := op.client.uploader.Upload(ctx, input)
 	if err != nil {
 		op.client.logger.Error("Failed to put object",
 			"error", err,
@@ -788,6 +785,18 @@ func (t *timer) Elapsed() time.Duration {
 	return time.Since(t.start)
 }
 
+// countingReader counts bytes as they are streamed to S3
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
 // isNotFoundError checks if error indicates object not found
 func isNotFoundError(err error) bool {
 	var noSuchKey *s3types.NoSuchKey
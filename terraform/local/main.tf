@@ -113,6 +113,12 @@ variable "max_receive_count" {
   default     = 5
 }
 
+variable "orchestrator_schedule" {
+  description = "Schedule expression of the orchestrator tick"
+  type        = string
+  default     = "rate(5 minutes)"
+}
+
 variable "log_retention_days" {
   description = "CloudWatch log retention in days"
   type        = number
@@ -289,6 +295,7 @@ resource "aws_iam_policy" "lambda_downloader" {
         ]
         Resource = [
           aws_sqs_queue.processor.arn,
+          aws_sqs_queue.downloader.arn, # delayed retries
           aws_sqs_queue.dlq.arn
         ]
       },
@@ -426,7 +433,11 @@ resource "aws_iam_policy" "lambda_extractor" {
         Action = [
           "sqs:SendMessage"
         ]
-        Resource = aws_sqs_queue.dlq.arn
+        Resource = [
+          aws_sqs_queue.extractor.arn,
+          aws_sqs_queue.downloader.arn,
+          aws_sqs_queue.dlq.arn
+        ]
       },
       {
         Sid    = "S3Operations"
@@ -457,6 +468,47 @@ resource "aws_iam_policy" "lambda_extractor" {
   })
 }
 
+resource "aws_iam_policy" "lambda_orchestrator" {
+  name        = "${var.project_name}-${var.environment}-lambda-orchestrator-policy"
+  description = "Policy for Lambda orchestrator worker"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid    = "CloudWatchLogs"
+        Effect = "Allow"
+        Action = [
+          "logs:CreateLogGroup",
+          "logs:CreateLogStream",
+          "logs:PutLogEvents"
+        ]
+        Resource = "arn:aws:logs:${var.aws_region}:*:*"
+      },
+      {
+        Sid    = "SQSPublish"
+        Effect = "Allow"
+        Action = [
+          "sqs:SendMessage"
+        ]
+        Resource = [
+          aws_sqs_queue.extractor.arn,
+          aws_sqs_queue.downloader.arn,
+          aws_sqs_queue.processor.arn
+        ]
+      },
+      {
+        Sid    = "CloudWatchMetrics"
+        Effect = "Allow"
+        Action = [
+          "cloudwatch:PutMetricData"
+        ]
+        Resource = "*"
+      }
+    ]
+  })
+}
+
 # Attach policies to role
 resource "aws_iam_role_policy_attachment" "lambda_downloader" {
   role       = aws_iam_role.lambda_execution.name
@@ -473,6 +525,11 @@ resource "aws_iam_role_policy_attachment" "lambda_extractor" {
   policy_arn = aws_iam_policy.lambda_extractor.arn
 }
 
+resource "aws_iam_role_policy_attachment" "lambda_orchestrator" {
+  role       = aws_iam_role.lambda_execution.name
+  policy_arn = aws_iam_policy.lambda_orchestrator.arn
+}
+
 # ==================== CloudWatch ====================
 
 # CloudWatch Log Group for Lambda
@@ -509,6 +566,17 @@ resource "aws_cloudwatch_log_group" "extractor" {
   }
 }
 
+resource "aws_cloudwatch_log_group" "orchestrator" {
+  name              = "/aws/lambda/${var.project_name}-${var.environment}-orchestrator"
+  retention_in_days = var.log_retention_days
+
+  tags = {
+    Name        = "/aws/lambda/${var.project_name}-${var.environment}-orchestrator"
+    Environment = var.environment
+    Worker      = "orchestrator"
+  }
+}
+
 # CloudWatch Metric Alarm for DLQ
 resource "aws_cloudwatch_metric_alarm" "dlq_messages" {
   alarm_name          = "${var.project_name}-${var.environment}-downloader-dlq-messages"
@@ -664,8 +732,8 @@ resource "aws_lambda_function" "processor" {
       ADAPTER_DATABASE = "postgres"
 
       CLOUDWATCH_REGION = "us-east-2"
-      CLOUDWATCH_LOG_GROUP = "/workers/downloader"
-      CLOUDWATCH_NAMESPACE = "workers/downloader"
+      CLOUDWATCH_LOG_GROUP = "/workers/processor"
+      CLOUDWATCH_NAMESPACE = "workers/processor"
 
       QUEUE_DOWNLOADER=aws_sqs_queue.downloader.id
       QUEUE_PROCESSOR=aws_sqs_queue.processor.id
@@ -731,8 +799,8 @@ resource "aws_lambda_function" "extractor" {
       ADAPTER_DATABASE = "postgres"
 
       CLOUDWATCH_REGION = "us-east-2"
-      CLOUDWATCH_LOG_GROUP = "/workers/downloader"
-      CLOUDWATCH_NAMESPACE = "workers/downloader"
+      CLOUDWATCH_LOG_GROUP = "/workers/extractor"
+      CLOUDWATCH_NAMESPACE = "workers/extractor"
 
       QUEUE_DOWNLOADER=aws_sqs_queue.downloader.id
       QUEUE_PROCESSOR=aws_sqs_queue.processor.id
@@ -765,6 +833,73 @@ resource "aws_lambda_function" "extractor" {
   ]
 }
 
+# Lambda Function for Orchestrator
+resource "aws_lambda_function" "orchestrator" {
+  function_name = "${var.project_name}-${var.environment}-orchestrator"
+  role          = aws_iam_role.lambda_execution.arn
+  
+  handler = "bootstrap"
+  runtime = "provided.al2023"
+  
+  timeout     = var.lambda_timeout
+  memory_size = var.lambda_memory_size
+  
+  filename         = var.lambda_deployment_package
+  source_code_hash = fileexists(var.lambda_deployment_package) ? filebase64sha256(var.lambda_deployment_package) : null
+
+  environment {
+    variables = {
+      ENVIRONMENT       = var.environment
+      SERVICE_NAME      = "orchestrator-worker"
+      AWS_REGION        = var.aws_region
+      STORAGE_BUCKET_OR_PATH    = aws_s3_bucket.reports.id
+      LOG_LEVEL        = var.environment == "prod" ? "info" : "debug"
+      METRICS_NAMESPACE = "${var.project_name}/${var.environment}"
+      
+      # Adapter configuration
+      ADAPTER_RUNTIME  = "lambda"
+      ADAPTER_STORAGE  = "s3"
+      ADAPTER_QUEUE    = "sqs"
+      ADAPTER_LOGGER   = "cloudwatch"
+      ADAPTER_METRICS  = "cloudwatch"
+      ADAPTER_DATABASE = "postgres"
+
+      CLOUDWATCH_REGION = "us-east-2"
+      CLOUDWATCH_LOG_GROUP = "/workers/orchestrator"
+      CLOUDWATCH_NAMESPACE = "workers/orchestrator"
+
+      QUEUE_DOWNLOADER=aws_sqs_queue.downloader.id
+      QUEUE_PROCESSOR=aws_sqs_queue.processor.id
+      QUEUE_EXTRACTOR=aws_sqs_queue.extractor.id
+
+      DB_HOST = "postgres"
+      DB_NAME = "audit_reports_aggregator"
+      DB_USER = "postgres"
+      DB_PASSWORD = "postgres"
+      DB_MAX_OPEN_CONNS = 25
+      DB_MAX_IDLE_CONNS = 5
+    }
+  }
+
+  # A single scheduling pass at a time
+  reserved_concurrent_executions = 1
+
+  tracing_config {
+    mode = var.environment == "prod" ? "Active" : "PassThrough"
+  }
+
+  tags = {
+    Name        = "${var.project_name}-${var.environment}-orchestrator"
+    Environment = var.environment
+    Worker      = "orchestrator"
+  }
+
+  depends_on = [
+    aws_iam_role_policy_attachment.lambda_orchestrator,
+    aws_cloudwatch_log_group.orchestrator
+  ]
+}
+
 # ==================== Event Source Mapping ====================
 
 # SQS to Lambda Event Source Mapping
@@ -803,6 +938,35 @@ resource "aws_lambda_event_source_mapping" "extractor" {
   function_response_types = ["ReportBatchItemFailures"]
 }
 
+# ==================== Schedules ====================
+
+# Periodic tick of the orchestrator, delivered as a direct runtime request
+resource "aws_cloudwatch_event_rule" "orchestrator_tick" {
+  name                = "${var.project_name}-${var.environment}-orchestrator-tick"
+  description         = "Periodic scheduling pass of the orchestrator"
+  schedule_expression = var.orchestrator_schedule
+}
+
+resource "aws_cloudwatch_event_target" "orchestrator_tick" {
+  rule = aws_cloudwatch_event_rule.orchestrator_tick.name
+  arn  = aws_lambda_function.orchestrator.arn
+
+  input = jsonencode({
+    id      = "orchestrator-tick"
+    source  = "eventbridge"
+    type    = "schedule.tick"
+    payload = {}
+  })
+}
+
+resource "aws_lambda_permission" "orchestrator_tick" {
+  statement_id  = "AllowEventBridgeInvoke"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.orchestrator.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.orchestrator_tick.arn
+}
+
 # ==================== Outputs ====================
 
 output "lambda_functions" {
@@ -819,6 +983,10 @@ output "lambda_functions" {
       arn           = aws_lambda_function.extractor.arn
       function_name = aws_lambda_function.extractor.function_name
     }
+    orchestrator = {
+      arn           = aws_lambda_function.orchestrator.arn
+      function_name = aws_lambda_function.orchestrator.function_name
+    }
   }
   description = "Lambda function details"
 }
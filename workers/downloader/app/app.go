@@ -0,0 +1,114 @@
+// Package app assembles the handler of the downloader, for its own binary and
+// for the local binary running several workers in one process.
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"downloader/internal/application/dto"
+	"downloader/internal/application/handler"
+	"downloader/internal/application/ports"
+	"downloader/internal/application/usecase"
+	"downloader/internal/domain/entity/download"
+	"downloader/internal/domain/entity/downloadresult"
+	"downloader/internal/domain/service"
+	"downloader/internal/infrastructure/httpclient"
+	"downloader/internal/infrastructure/ratelimit"
+	"shared/infrastructure/config"
+	"shared/infrastructure/crawlpolicy"
+	"shared/infrastructure/outbox"
+)
+
+// EventTypes are the types of the requests the downloader handles
+var EventTypes = []string{
+	dto.DownloadEventRequested,
+	dto.DownloadEventRetry,
+	dto.DownloadEventRecheck,
+}
+
+// Dependencies holds the infrastructure the downloader is built on
+type Dependencies struct {
+	Storage      ports.Storage
+	Database     ports.Database
+	Repositories ports.Repositories
+	Queue        ports.Queue
+}
+
+// NewHandler builds the downloader handler, with its HTTP client throttled
+// per remote host within its crawl policy
+func NewHandler(cfg *config.Config, deps Dependencies, obs ports.Observability) (ports.Handler, error) {
+	client, err := httpclient.New(cfg.Downloader)
+	if err != nil {
+		return nil, fmt.Errorf("http client creation: %w", err)
+	}
+	httpClient, err := crawlpolicy.NewClient(
+		ratelimit.NewClient(client, cfg.RateLimit),
+		deps.Repositories.AuditProvider(),
+		cfg.CrawlPolicy,
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("crawl policy client creation: %w", err)
+	}
+
+	downloadService := service.NewDownloadService(
+		httpClient,
+		cfg.Downloader,
+	).WithMismatchPolicy(downloadresult.MismatchPolicy(cfg.Content.MismatchPolicy))
+
+	resolver := service.NewArtifactResolver(resolveRules(cfg.Content.ResolveRules))
+
+	relay, err := outbox.NewRelay(deps.Repositories.Outbox(), deps.Queue, obs)
+	if err != nil {
+		return nil, fmt.Errorf("outbox relay creation: %w", err)
+	}
+
+	downloadFile, err := usecase.NewDownloadFile(
+		downloadService,
+		resolver,
+		service.NewArchiveExpander(service.DefaultExpandLimits()),
+		deps.Storage,
+		deps.Database,
+		relay,
+		deps.Queue,
+		retryPolicy(cfg.Retry),
+		cfg.Queue.Queues,
+		deps.Repositories,
+		workerID(),
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("usecase creation: %w", err)
+	}
+
+	return handler.NewDownloadHandler(downloadFile, obs), nil
+}
+
+// workerID identifies this instance in the claimed_by column of downloads
+func workerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "downloader"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// retryPolicy maps the retry configuration onto the download backoff policy
+func retryPolicy(cfg config.RetryConfig) download.RetryPolicy {
+	return download.RetryPolicy{
+		BaseDelay:  cfg.BaseDelay,
+		Multiplier: cfg.Multiplier,
+		Jitter:     cfg.Jitter,
+		MaxDelay:   cfg.MaxDelay,
+	}
+}
+
+// resolveRules maps the configured rule names onto the artifact resolver rules
+func resolveRules(rules map[string]string) map[string]service.ResolveRule {
+	resolved := make(map[string]service.ResolveRule, len(rules))
+	for slug, rule := range rules {
+		resolved[slug] = service.ResolveRule(rule)
+	}
+	return resolved
+}
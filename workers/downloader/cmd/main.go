@@ -4,16 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"time"
 
-	// Domain layer
-	"downloader/internal/application/handler"
+	// Application layer
+	"downloader/app"
 	"downloader/internal/application/ports"
-	"downloader/internal/application/usecase"
-	"downloader/internal/domain/service"
 
 	// Infrastructure layer
+	"shared/infrastructure/api"
 	"shared/infrastructure/config"
 	"shared/infrastructure/database"
 	"shared/infrastructure/observability"
@@ -34,7 +32,6 @@ func main() {
 type Dependencies struct {
 	storage      ports.Storage
 	database     ports.Database
-	httpClient   ports.HTTPClient
 	repositories ports.Repositories
 	queue        ports.Queue
 }
@@ -69,15 +66,10 @@ func initializeDependencies(cfg *config.Config, obs ports.Observability) *Depend
 		log.Fatalf("Failed to create storage: %v", err)
 	}
 
-	// HTTP client
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	// Repositories
 	repositories, err := repository.NewRepositories(db, obs)
 	if err != nil {
-		log.Fatalf("Failed to create http client: %v", err)
+		log.Fatalf("Failed to create repositories: %v", err)
 	}
 
 	// Queue initialization - optional component
@@ -92,7 +84,6 @@ func initializeDependencies(cfg *config.Config, obs ports.Observability) *Depend
 	return &Dependencies{
 		storage:      storageClient,
 		database:     db,
-		httpClient:   httpClient,
 		repositories: repositories,
 		queue:        publisher,
 	}
@@ -109,25 +100,36 @@ func initializeObservability(cfg *config.Config) ports.Observability {
 
 // buildApplication assembles the application layers
 func buildApplication(cfg *config.Config, deps *Dependencies, obs ports.Observability) (ports.Runtime, error) {
-	// Create use case
-	downloadService := service.NewDownloadService(
-		deps.httpClient,
-		100*1024*1024, // (hardcoding 100MB by now)
-	)
-
-	downloadFile, err := usecase.NewDownloadFile(
-		downloadService,
-		deps.storage,
-		deps.queue,
-		cfg.Queue.Queues,
-		deps.repositories,
-		obs,
-	)
-
-	handler := handler.NewDownloadHandler(downloadFile, obs)
+	handler, err := app.NewHandler(cfg, app.Dependencies{
+		Storage:      deps.storage,
+		Database:     deps.database,
+		Repositories: deps.repositories,
+		Queue:        deps.queue,
+	}, obs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Route the events of the worker; requests of any other type are
+	// rejected by the router
+	router, err := runtime.NewRouter(obs)
+	if err != nil {
+		return nil, fmt.Errorf("router creation: %w", err)
+	}
+	router.Route(handler, app.EventTypes...)
+
+	// Status and search queries, served by the HTTP runtime
+	statusAPI, err := api.NewStatusAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("status api creation: %w", err)
+	}
+	searchAPI, err := api.NewSearchAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("search api creation: %w", err)
+	}
 
 	// Create runtime
-	runtime, err := runtime.Create(cfg, handler, obs)
+	runtime, err := runtime.Create(cfg, router, obs, statusAPI, searchAPI)
 	if err != nil {
 		return nil, fmt.Errorf("runtime creation: %w", err)
 	}
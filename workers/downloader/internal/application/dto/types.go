@@ -6,3 +6,11 @@ type (
 	DownloadRequest = shared.DownloadRequest
 	ProcessRequest  = shared.ProcessRequest
 )
+
+const (
+	DownloadEventRequested = shared.DownloadEventRequested
+	DownloadEventRetry     = shared.DownloadEventRetry
+	DownloadEventRecheck   = shared.DownloadEventRecheck
+
+	ProcessEventRequested = shared.ProcessEventRequested
+)
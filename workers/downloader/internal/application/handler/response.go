@@ -19,6 +19,15 @@ func errorResponse(message string) ports.RuntimeResponse {
 	}
 }
 
+// rejectedResponse reports a failure that must not be delivered again
+func rejectedResponse(message string) ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   message,
+		Ack:     true,
+	}
+}
+
 func (h *DownloadHandler) handleDownloadSuccess() (ports.RuntimeResponse, error) {
 	h.logger.Info("Download successfully completed!")
 	return successResponse(), nil
@@ -30,6 +39,30 @@ func (h *DownloadHandler) handleDownloadError(downloadID int64, err error) (port
 		h.logger.Info("Download already completed")
 		return successResponse(), nil
 
+	case errors.Is(err, download.ErrRetryNotDue):
+		// Early redelivery; the retry is published again once due
+		h.logger.Info("Download retry not due yet", "download_id", downloadID)
+		return successResponse(), nil
+
+	case errors.Is(err, download.ErrPostponed):
+		h.logger.Info("Download postponed by rate limit", "download_id", downloadID)
+		return successResponse(), nil
+
+	case errors.Is(err, download.ErrNotCompleted):
+		h.logger.Info("Download not completed yet, nothing to re-check", "download_id", downloadID)
+		return successResponse(), nil
+
+	case errors.Is(err, download.ErrClaimedElsewhere):
+		h.logger.Info("Download claimed by another worker", "download_id", downloadID)
+		return successResponse(), nil
+
+	case errors.Is(err, download.ErrRejected), errors.Is(err, download.ErrMaxAttemptsExceeded):
+		// Terminal failure, ack so the message is not redelivered
+		h.logger.Error("Download failed permanently",
+			"download_id", downloadID,
+			"error", err.Error())
+		return rejectedResponse(err.Error()), nil
+
 	default:
 		h.logger.Error("Download failed",
 			"download_id", downloadID,
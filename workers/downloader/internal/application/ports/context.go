@@ -0,0 +1,17 @@
+package ports
+
+import (
+	shared "shared/application/ports"
+)
+
+// WithProviderSlug tags ctx with the provider whose file is requested, so the
+// HTTPClient can apply the rate and crawl policy configured for it.
+// ProviderSlug returns the provider ctx was tagged with.
+var (
+	WithProviderSlug = shared.WithProviderSlug
+	ProviderSlug     = shared.ProviderSlug
+)
+
+// CheckRedirect runs the check of the crawl policy on a redirect, see
+// shared.WithRedirectCheck
+var CheckRedirect = shared.CheckRedirect
@@ -10,7 +10,9 @@ type (
 	Storage         = shared.Storage
 	ObjectMetadata  = shared.ObjectMetadata
 	Database        = shared.Database
+	Transaction     = shared.Transaction
 	Runtime         = shared.Runtime
+	Handler         = shared.Handler
 	Repositories    = shared.Repositories
 	Logger          = shared.Logger
 	Metrics         = shared.Metrics
@@ -18,4 +20,9 @@ type (
 	Observability   = shared.Observability
 	RuntimeRequest  = shared.RuntimeRequest
 	RuntimeResponse = shared.RuntimeResponse
+	CrawlDelayError = shared.CrawlDelayError
 )
+
+// ErrDisallowed is returned by the HTTPClient for URLs the crawl policy
+// keeps us away from
+var ErrDisallowed = shared.ErrDisallowed
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+
+	"downloader/internal/application/ports"
+	downloadPkg "downloader/internal/domain/entity/download"
+	"downloader/internal/domain/service"
+)
+
+// sourceArchiveExtension is the extension of the repository tarballs
+const sourceArchiveExtension = ".tar.gz"
+
+// storeSourceArchive downloads the tarball of the audited repository next to
+// the report file, at path, and links it to the download; the caller saves
+// the download. The archive is an extra: failures are logged and not
+// returned, and the next re-check tries again. It returns whether the
+// download changed.
+func (p *DownloadFile) storeSourceArchive(
+	ctx context.Context,
+	download *downloadPkg.Download,
+	repositoryURL, repositoryRef *string,
+	path string,
+	metadata ports.ObjectMetadata,
+) bool {
+	if repositoryURL == nil || download.HasSourceArchive() {
+		return false
+	}
+
+	ref := ""
+	if repositoryRef != nil {
+		ref = *repositoryRef
+	}
+	archive, err := service.ArchiveFor(*repositoryURL, ref)
+	if err != nil {
+		p.logger.Info("Repository has no downloadable archive",
+			"download_id", download.ID,
+			"repository_url", *repositoryURL,
+			"reason", err.Error())
+		return false
+	}
+
+	stream, err := p.downloadService.Download(ctx, archive.URL)
+	if err != nil {
+		p.logArchiveError(download.ID, archive, err)
+		return false
+	}
+	defer stream.Close()
+
+	metadata.UserMetadata = maps.Clone(metadata.UserMetadata)
+	metadata.UserMetadata["repository_url"] = *repositoryURL
+	metadata.UserMetadata["ref"] = archive.Ref
+	file, err := p.put(ctx, stream, stream, path, metadata)
+	if err != nil {
+		p.logArchiveError(download.ID, archive, err)
+		return false
+	}
+
+	download.RecordSourceArchive(file.path, file.result.Hash(), archive.Ref)
+
+	p.logger.Info("Source archive stored",
+		"download_id", download.ID,
+		"ref", archive.Ref,
+		"storage_path", file.path,
+		"size", file.result.Size())
+	p.metrics.IncrementCounter("downloader.source_archives", map[string]string{"result": "stored"})
+	return true
+}
+
+func (p *DownloadFile) logArchiveError(downloadID int64, archive *service.SourceArchive, err error) {
+	p.logger.Error("Failed to store source archive",
+		"download_id", downloadID,
+		"url", archive.URL,
+		"error", err.Error())
+	p.metrics.IncrementCounter("downloader.source_archives", map[string]string{
+		"result":     "failed",
+		"error_code": string(service.Classify(err)),
+	})
+}
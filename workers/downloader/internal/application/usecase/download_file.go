@@ -1,43 +1,67 @@
 package usecase
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"downloader/internal/application/dto"
 	"downloader/internal/application/ports"
 	downloadPkg "downloader/internal/domain/entity/download"
+	"downloader/internal/domain/entity/downloadresult"
+	"downloader/internal/domain/entity/downloadversion"
+	outboxPkg "downloader/internal/domain/entity/outbox"
 	"downloader/internal/domain/entity/process"
+	"downloader/internal/domain/entity/reportblob"
 	"downloader/internal/domain/service"
+	"errors"
 	"fmt"
 	"shared/infrastructure/config"
+	"shared/infrastructure/outbox"
 	"time"
 )
 
 type DownloadFile struct {
 	downloadService *service.DownloadService
+	resolver        *service.ArtifactResolver
+	expander        *service.ArchiveExpander
 	storage         ports.Storage
+	database        ports.Database
+	relay           *outbox.Relay
 	queue           ports.Queue
+	retryPolicy     downloadPkg.RetryPolicy
 	queueNames      config.QueueNames
 	repositories    ports.Repositories
+	workerID        string
 	logger          ports.Logger
 	metrics         ports.Metrics
 }
 
 func NewDownloadFile(
 	downloadService *service.DownloadService,
+	resolver *service.ArtifactResolver,
+	expander *service.ArchiveExpander,
 	storage ports.Storage,
+	database ports.Database,
+	relay *outbox.Relay,
 	queue ports.Queue,
+	retryPolicy downloadPkg.RetryPolicy,
 	queueNames config.QueueNames,
 	repositories ports.Repositories,
+	workerID string,
 	obs ports.Observability,
 ) (*DownloadFile, error) {
 	logger, metrics, _ := obs.ComponentsScoped("usecase.download_file")
 	return &DownloadFile{
 		downloadService: downloadService,
+		resolver:        resolver,
+		expander:        expander,
 		storage:         storage,
+		database:        database,
+		relay:           relay,
 		queue:           queue,
+		retryPolicy:     retryPolicy,
 		queueNames:      queueNames,
 		repositories:    repositories,
+		workerID:        workerID,
 		logger:          logger,
 		metrics:         metrics,
 	}, nil
@@ -49,6 +73,14 @@ func (p *DownloadFile) Download(ctx context.Context, req *dto.DownloadRequest) e
 	if err != nil {
 		return ErrDownloadFileAuditReportNotFound(err)
 	}
+	if req.Kind != "" && req.Kind != string(download.Kind) {
+		return ErrArtifactKindMismatch(req.Kind, download.Kind)
+	}
+
+	// Re-checks revalidate the file of a completed download, see recheck
+	if req.EventType == dto.DownloadEventRecheck {
+		return p.recheck(ctx, download)
+	}
 
 	// 2. Check current status (skip if completed)
 	if download.IsCompleted() {
@@ -57,15 +89,25 @@ func (p *DownloadFile) Download(ctx context.Context, req *dto.DownloadRequest) e
 
 	// 3. Check if can start (business rule in entity)
 	if !download.CanStart() {
+		if download.IsRejected() {
+			return downloadPkg.ErrRejected
+		}
 		if download.HasExceededMaxAttempts() {
 			return downloadPkg.ErrMaxAttemptsExceeded
 		}
+		if !download.IsRetryDue(time.Now()) {
+			return downloadPkg.ErrRetryNotDue
+		}
 		return downloadPkg.ErrInvalidStateTransition
 	}
 
-	// 3. Update status to in_progress ("lock" the register so no other worker will be able to get it)
-	download.Start()
-	if err := p.repositories.Download().Update(ctx, download); err != nil {
+	// 3. Claim the register so no other worker will be able to get it; a
+	// duplicate delivery racing with us loses here with ErrClaimedElsewhere
+	download, err = p.repositories.Download().Claim(ctx, download.ID, p.workerID)
+	if err != nil {
+		if errors.Is(err, downloadPkg.ErrClaimedElsewhere) {
+			return err
+		}
 		return ErrDownloadFileUpdateFailed(err)
 	}
 
@@ -81,89 +123,324 @@ func (p *DownloadFile) Download(ctx context.Context, req *dto.DownloadRequest) e
 		return p.commitDownloadFailWithError(ctx, download, ErrAuditProviderNotFound(err))
 	}
 
-	// 5. Download file from URL
-	result, err := p.downloadService.Download(ctx, report.SourceDownloadURL)
+	// 5. Open a stream over the remote file, at the rate allowed to the provider
+	ctx = ports.WithProviderSlug(ctx, provider.Slug)
+	stream, err := p.downloadService.Download(ctx, download.URL(report.SourceDownloadURL))
 	if err != nil {
-		return p.commitDownloadFailWithError(ctx, download, ErrDownloadFileDownloadFailed(err))
+		return p.failDownload(ctx, download, ErrDownloadFileDownloadFailed(err))
 	}
+	defer stream.Close()
+	p.logMismatch(download.ID, stream)
 
-	// 6. Generate storage path
-	storagePath := report.StoragePath(provider, result.Extension())
-
-	// Create metadata for the upload
+	// Create metadata for the upload (hash and size are only known once streamed)
 	metadata := ports.ObjectMetadata{
-		ContentType:   result.ContentType(),
-		ContentLength: result.Size(),
 		UserMetadata: map[string]string{
 			"report_id": fmt.Sprintf("%d", report.ID),
 			"provider":  provider.Slug,
-			"file_hash": result.Hash(),
+			"kind":      string(download.Kind),
 		},
 	}
 
-	// Convert byte slice to reader
-	resultContent := bytes.NewReader(result.Content())
-	// Upload with bucket and metadata
-	if err := p.storage.Put(ctx, "", storagePath, resultContent, metadata); err != nil {
-		return p.commitDownloadFailWithError(ctx, download, ErrDownloadFileUploadFailed(err))
+	// 6. Stream the body straight into storage, or the artifact its page links to
+	file, err := p.storeFile(ctx, provider.Slug, stream, func(extension string) string {
+		return report.ArtifactStoragePath(provider, string(download.Kind), extension)
+	}, metadata)
+	if err != nil {
+		return p.failDownload(ctx, download, err)
+	}
+
+	// 7. Expand archives into their PDF and markdown files
+	manifestPath, err := p.expandArchive(ctx, file, metadata)
+	if err != nil {
+		p.discard(ctx, file)
+		return p.failDownload(ctx, download, err)
 	}
 
-	// 8. Update download record with results
-	if err := download.Complete(storagePath, result.Hash(), result.Extension()); err != nil {
+	// 8. Reuse the blob of an identical file already downloaded for another report
+	blobPath := p.sharedBlobPath(ctx, download.ID, file.result.Hash(), file.path)
+
+	// 9. Update download record with results
+	if err := download.Complete(blobPath, file.result.Hash(), file.result.Extension()); err != nil {
 		// This should never happen, so we don't need a custom error for it
 		return err
 	}
+	download.RecordValidators(stream.Validators().ETag, stream.Validators().LastModified)
+	download.RecordMIMEType(file.result.MIMEType())
+	download.RecordSnapshot(file.snapshotPath)
+	download.RecordManifest(manifestPath)
 
-	if err := p.repositories.Download().Update(ctx, download); err != nil {
-		return ErrDownloadFileUpdateFailed(err)
+	// 10. Store the audited repository next to the report, best effort
+	if download.IsReport() {
+		p.storeSourceArchive(ctx, download, report.RepositoryURL, report.RepositoryRef,
+			report.StoragePath(provider, sourceArchiveExtension), metadata)
 	}
 
-	// 9. Create process record
-	if err := p.createProcessAndPublishEvent(ctx, download.ID); err != nil {
+	// 11. Commit the completion with its blob link, process record and event
+	message, err := p.commitCompletion(ctx, download, nil)
+	if err != nil {
 		return err
 	}
 
+	// 12. Our upload is a duplicate once the report links the shared blob
+	if blobPath != file.path {
+		p.removeUnreferencedBlob(ctx, file.path)
+	}
+
+	// 13. Publish the event right away; if this fails it stays in the outbox
+	// and the orchestrator relays it later
+	if message == nil {
+		return nil
+	}
+	if err := p.relay.Send(ctx, message); err != nil {
+		p.logger.Error("Failed to publish process event, left in outbox",
+			"download_id", download.ID,
+			"outbox_id", message.ID,
+			"error", err.Error())
+	}
+
 	return nil
 }
 
+// logMismatch reports a file whose content contradicts its declared type,
+// which the service kept or corrected depending on its policy
+func (p *DownloadFile) logMismatch(downloadID int64, stream *downloadresult.Stream) {
+	if !stream.Mismatch() {
+		return
+	}
+
+	p.logger.Info("Downloaded content does not match its declared type",
+		"download_id", downloadID,
+		"url", stream.URL(),
+		"content_type", stream.ContentType(),
+		"detected_type", stream.MIMEType(),
+		"extension", stream.Extension())
+	p.metrics.IncrementCounter("downloader.content_mismatch", map[string]string{"detected_type": stream.MIMEType()})
+}
+
+// sharedBlobPath returns the storage path of a completed download with the
+// same content hash, or uploadedPath when the file is new. Deduplication is
+// best effort: a failed lookup keeps the freshly uploaded object.
+func (p *DownloadFile) sharedBlobPath(ctx context.Context, downloadID int64, fileHash, uploadedPath string) string {
+	existing, err := p.repositories.Download().FindCompletedByHash(ctx, fileHash, downloadID)
+	if err != nil {
+		p.logger.Error("Failed to look up duplicate download",
+			"download_id", downloadID,
+			"error", err.Error())
+		return uploadedPath
+	}
+	if existing == nil || existing.StoragePath == nil {
+		return uploadedPath
+	}
+
+	p.logger.Info("Reusing blob of identical download",
+		"download_id", downloadID,
+		"duplicate_of", existing.ID,
+		"storage_path", *existing.StoragePath)
+	p.metrics.IncrementCounter("downloader.deduplicated", nil)
+	return *existing.StoragePath
+}
+
+// removeUnreferencedBlob deletes the object at storagePath unless a report
+// still links to it. A blob left behind is only wasted space, so failures are
+// logged and not returned.
+func (p *DownloadFile) removeUnreferencedBlob(ctx context.Context, storagePath string) {
+	references, err := p.repositories.ReportBlob().CountByStoragePath(ctx, storagePath)
+	if err != nil {
+		p.logger.Error("Failed to count blob references", "storage_path", storagePath, "error", err.Error())
+		return
+	}
+	if references > 0 {
+		p.logger.Info("Keeping referenced blob", "storage_path", storagePath, "references", references)
+		return
+	}
+
+	if err := p.storage.Delete(ctx, "", storagePath); err != nil {
+		p.logger.Error("Failed to delete duplicate blob", "storage_path", storagePath, "error", err.Error())
+	}
+}
+
 func (d *DownloadFile) commitDownloadFailWithError(
 	ctx context.Context,
 	download *downloadPkg.Download,
 	err error,
 ) error {
-	if err := download.Fail(err.Error()); err != nil {
+	code := service.Classify(err)
+	if err := download.Fail(code, err.Error()); err != nil {
 		return err
 	}
+	delay, retry := download.ScheduleRetry(d.retryPolicy)
 
 	if err := d.repositories.Download().Update(ctx, download); err != nil {
 		return ErrDownloadFileUpdateFailed(err)
 	}
+	d.metrics.IncrementCounter("downloader.failures", map[string]string{"error_code": string(code)})
+
+	// Permanent failures are not retried, the message must not be either
+	if download.IsRejected() {
+		return ErrDownloadFileRejected(err)
+	}
+
+	// The orchestrator publishes the retry later if this is lost
+	if retry {
+		d.publishRetry(ctx, download, delay)
+	}
 	return err
 }
 
-func (p *DownloadFile) createProcessAndPublishEvent(ctx context.Context, downloadID int64) error {
-	// Create process record
-	proc := process.NewProcess(downloadID)
-	if err := p.repositories.Process().Create(ctx, proc); err != nil {
-		return ErrProcessCreationFailed(err)
+// failDownload postpones the download when a host rate limited us, and
+// fails it otherwise
+func (d *DownloadFile) failDownload(ctx context.Context, download *downloadPkg.Download, err error) error {
+	var limited *service.RateLimitError
+	if errors.As(err, &limited) {
+		return d.commitDownloadPostponed(ctx, download, limited)
 	}
+	return d.commitDownloadFailWithError(ctx, download, err)
+}
 
-	// Publish event
-	event := &dto.ProcessRequest{
-		EventID:   fmt.Sprintf("process-%d-%d", proc.ID, time.Now().Unix()),
-		EventType: "process.requested",
-		ProcessID: proc.ID,
-		Timestamp: time.Now(),
+// commitDownloadPostponed gives the download back until the rate limited host
+// accepts requests again, without spending the attempt
+func (d *DownloadFile) commitDownloadPostponed(
+	ctx context.Context,
+	download *downloadPkg.Download,
+	limited *service.RateLimitError,
+) error {
+	delay := limited.RetryAfter
+	if delay <= 0 {
+		delay = d.retryPolicy.Delay(download.AttemptCount)
+	}
+
+	if err := download.Postpone(time.Now().Add(delay), downloadPkg.ErrorCodeRateLimited, limited.Error()); err != nil {
+		return err
+	}
+	if err := d.repositories.Download().Update(ctx, download); err != nil {
+		return ErrDownloadFileUpdateFailed(err)
+	}
+	d.metrics.IncrementCounter("downloader.postponed", nil)
+
+	// The orchestrator publishes the request again if this is lost
+	d.publishRetry(ctx, download, delay)
+	return downloadPkg.ErrPostponed
+}
+
+// publishRetry asks for the next attempt of a failed download once its
+// backoff delay is over
+func (d *DownloadFile) publishRetry(ctx context.Context, download *downloadPkg.Download, delay time.Duration) {
+	event := &dto.DownloadRequest{
+		EventID:    fmt.Sprintf("download-%d-%d", download.ID, time.Now().UnixNano()),
+		EventType:  dto.DownloadEventRetry,
+		DownloadID: download.ID,
+		Timestamp:  time.Now(),
 	}
 
 	message := &ports.QueueMessage{
-		Target: p.queueNames.Processor,
+		Target: d.queueNames.Downloader,
 		Body:   event,
+		Delay:  delay,
 	}
 
-	if err := p.queue.Publish(ctx, message); err != nil {
-		return ErrPublishProcessEvent(err)
+	if err := d.queue.Publish(ctx, message); err != nil {
+		d.logger.Error("Failed to publish download retry",
+			"download_id", download.ID,
+			"error", err.Error())
+		return
 	}
 
-	return nil
+	d.logger.Info("Download retry scheduled",
+		"download_id", download.ID,
+		"attempt_count", download.AttemptCount,
+		"delay", delay.String())
+}
+
+// commitCompletion stores the completed download with its blob link, the
+// previous version of a revised file, the process record and the
+// process.requested event in one transaction, so none exists without the others.
+// Only the report itself is processed; for other artifacts the message is nil.
+func (p *DownloadFile) commitCompletion(
+	ctx context.Context,
+	download *downloadPkg.Download,
+	previous *downloadversion.DownloadVersion,
+) (*outboxPkg.Message, error) {
+	var message *outboxPkg.Message
+	err := p.database.Transaction(ctx, func(tx ports.Transaction) error {
+		repositories := p.repositories.WithTx(tx)
+
+		if err := repositories.Download().Update(ctx, download); err != nil {
+			return ErrDownloadFileUpdateFailed(err)
+		}
+
+		// Keep the record of the replaced file
+		if previous != nil {
+			if err := repositories.DownloadVersion().Create(ctx, previous); err != nil {
+				return ErrDownloadVersionFailed(err)
+			}
+		}
+
+		// Link the report to its blob
+		blob, err := reportblob.NewReportBlob(download)
+		if err != nil {
+			return err
+		}
+		if err := repositories.ReportBlob().Create(ctx, blob); err != nil {
+			return ErrReportBlobLinkFailed(err)
+		}
+
+		// The text of the report describes it, secondary artifacts would overwrite it
+		if !download.IsReport() {
+			return nil
+		}
+
+		// Create process record, or schedule the existing one again
+		proc, err := p.scheduleProcess(ctx, repositories, download.ID)
+		if err != nil {
+			return err
+		}
+
+		// Record the event
+		event := &dto.ProcessRequest{
+			EventID:   fmt.Sprintf("process-%d-%d", proc.ID, time.Now().UnixNano()),
+			EventType: dto.ProcessEventRequested,
+			ProcessID: proc.ID,
+			Timestamp: time.Now(),
+		}
+
+		msg, err := outboxPkg.NewMessage(p.queueNames.Processor, event.EventType, event)
+		if err != nil {
+			return ErrOutboxMessageFailed(err)
+		}
+		if err := repositories.Outbox().Create(ctx, msg); err != nil {
+			return ErrOutboxMessageFailed(err)
+		}
+
+		message = msg
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// scheduleProcess creates the process of a download, or resets it to pending
+// when the download was already processed and its file has been revised
+func (p *DownloadFile) scheduleProcess(ctx context.Context, repositories ports.Repositories, downloadID int64) (*process.Process, error) {
+	proc, err := repositories.Process().GetByDownloadID(ctx, downloadID)
+	if errors.Is(err, sql.ErrNoRows) {
+		proc = process.NewProcess(downloadID)
+		if err := repositories.Process().Create(ctx, proc); err != nil {
+			return nil, ErrProcessCreationFailed(err)
+		}
+		return proc, nil
+	}
+	if err != nil {
+		return nil, ErrProcessCreationFailed(err)
+	}
+
+	if err := proc.Reprocess(); err != nil {
+		return nil, err
+	}
+	if err := repositories.Process().Update(ctx, proc); err != nil {
+		return nil, ErrProcessUpdateFailed(err)
+	}
+	return proc, nil
 }
@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"downloader/internal/application/dto"
+	"downloader/internal/application/ports"
+	downloadPkg "downloader/internal/domain/entity/download"
+	"downloader/internal/domain/service"
+	sharedPorts "shared/application/ports"
+	"shared/domain/entity"
+	sharedDownload "shared/domain/entity/download"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+	"shared/infrastructure/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// memoryStorage keeps objects by key
+type memoryStorage struct {
+	ports.Storage
+	objects map[string][]byte
+	deleted []string
+}
+
+func (s *memoryStorage) Put(ctx context.Context, bucket, key string, reader io.Reader, metadata ports.ObjectMetadata) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, bucket, key string) error {
+	delete(s.objects, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+// fakeDatabase runs transactions straight on the fake repositories
+type fakeDatabase struct {
+	ports.Database
+}
+
+func (d *fakeDatabase) Transaction(ctx context.Context, fn func(tx ports.Transaction) error) error {
+	return fn(nil)
+}
+
+// fakeRepositories holds the rows of one report and its downloads
+type fakeRepositories struct {
+	sharedPorts.Repositories
+	report    *entity.AuditReport
+	downloads map[int64]*entity.Download
+	blobs     []*entity.ReportBlob
+	outbox    []*entity.OutboxMessage
+
+	// references and countErr answer CountByStoragePath when set
+	references map[string]int64
+	countErr   error
+	// findErr fails FindCompletedByHash
+	findErr error
+}
+
+func (r *fakeRepositories) WithTx(tx ports.Transaction) ports.Repositories { return r }
+func (r *fakeRepositories) Download() sharedPorts.DownloadRepository       { return &fakeDownloads{r: r} }
+func (r *fakeRepositories) AuditReport() sharedPorts.AuditReportRepository { return &fakeReports{r: r} }
+func (r *fakeRepositories) AuditProvider() sharedPorts.AuditProviderRepository {
+	return &fakeProviders{}
+}
+func (r *fakeRepositories) ReportBlob() sharedPorts.ReportBlobRepository { return &fakeBlobs{r: r} }
+func (r *fakeRepositories) Process() sharedPorts.ProcessRepository       { return &fakeProcesses{} }
+func (r *fakeRepositories) Outbox() sharedPorts.OutboxRepository         { return &fakeOutbox{r: r} }
+
+type fakeDownloads struct {
+	sharedPorts.DownloadRepository
+	r *fakeRepositories
+}
+
+func (d *fakeDownloads) Get(ctx context.Context, id int64) (*entity.Download, error) {
+	dl, ok := d.r.downloads[id]
+	if !ok {
+		return nil, sharedPorts.ErrNotFound
+	}
+	return dl, nil
+}
+
+func (d *fakeDownloads) Claim(ctx context.Context, id int64, workerID string) (*entity.Download, error) {
+	dl := d.r.downloads[id]
+	if err := dl.Start(); err != nil {
+		return nil, downloadPkg.ErrClaimedElsewhere
+	}
+	return dl, nil
+}
+
+func (d *fakeDownloads) Update(ctx context.Context, dl *entity.Download) error {
+	d.r.downloads[dl.ID] = dl
+	return nil
+}
+
+func (d *fakeDownloads) FindCompletedByHash(ctx context.Context, fileHash string, excludeID int64) (*entity.Download, error) {
+	if d.r.findErr != nil {
+		return nil, d.r.findErr
+	}
+	for id, dl := range d.r.downloads {
+		if id != excludeID && dl.IsCompleted() && dl.FileHash != nil && *dl.FileHash == fileHash {
+			return dl, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeReports struct {
+	sharedPorts.AuditReportRepository
+	r *fakeRepositories
+}
+
+func (f *fakeReports) Get(ctx context.Context, id int64) (*entity.AuditReport, error) {
+	return f.r.report, nil
+}
+
+type fakeProviders struct {
+	sharedPorts.AuditProviderRepository
+}
+
+func (p *fakeProviders) Get(ctx context.Context, id int64) (*entity.AuditProvider, error) {
+	return &entity.AuditProvider{ID: id, Slug: "code4rena"}, nil
+}
+
+// fakeBlobs counts the links of the fake, plus the references set by the test
+type fakeBlobs struct {
+	sharedPorts.ReportBlobRepository
+	r *fakeRepositories
+}
+
+func (b *fakeBlobs) Create(ctx context.Context, blob *entity.ReportBlob) error {
+	b.r.blobs = append(b.r.blobs, blob)
+	return nil
+}
+
+func (b *fakeBlobs) CountByStoragePath(ctx context.Context, storagePath string) (int64, error) {
+	if b.r.countErr != nil {
+		return 0, b.r.countErr
+	}
+	count := b.r.references[storagePath]
+	for _, blob := range b.r.blobs {
+		if blob.StoragePath == storagePath {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeProcesses struct {
+	sharedPorts.ProcessRepository
+}
+
+func (p *fakeProcesses) GetByDownloadID(ctx context.Context, downloadID int64) (*entity.Process, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (p *fakeProcesses) Create(ctx context.Context, proc *entity.Process) error {
+	proc.ID = proc.DownloadID
+	return nil
+}
+
+type fakeOutbox struct {
+	sharedPorts.OutboxRepository
+	r *fakeRepositories
+}
+
+func (o *fakeOutbox) Create(ctx context.Context, msg *entity.OutboxMessage) error {
+	msg.ID = int64(len(o.r.outbox) + 1)
+	o.r.outbox = append(o.r.outbox, msg)
+	return nil
+}
+
+func (o *fakeOutbox) Update(ctx context.Context, msg *entity.OutboxMessage) error {
+	return nil
+}
+
+// fakeQueue accepts every message
+type fakeQueue struct {
+	published []*ports.QueueMessage
+}
+
+func (q *fakeQueue) Publish(ctx context.Context, message *ports.QueueMessage) error {
+	q.published = append(q.published, message)
+	return nil
+}
+
+func (q *fakeQueue) PublishBatch(ctx context.Context, messages []*ports.QueueMessage) error {
+	q.published = append(q.published, messages...)
+	return nil
+}
+
+type downloadFixture struct {
+	repositories *fakeRepositories
+	storage      *memoryStorage
+	download     *DownloadFile
+	server       *httptest.Server
+}
+
+// newDownloadFixture builds the use case over fakes, downloading from a test
+// server answering every path with the same PDF
+func newDownloadFixture(t *testing.T) *downloadFixture {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4\nthe same report\n%%EOF\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	repositories := &fakeRepositories{
+		report: &entity.AuditReport{
+			ID:                1,
+			ProviderID:        1,
+			Title:             "Vault",
+			SourceDownloadURL: server.URL + "/vault.pdf",
+		},
+		downloads:  make(map[int64]*entity.Download),
+		references: make(map[string]int64),
+	}
+	storage := &memoryStorage{objects: make(map[string][]byte)}
+	queue := &fakeQueue{}
+
+	relay, err := outbox.NewRelay(repositories.Outbox(), queue, obs)
+	require.NoError(t, err)
+
+	download, err := NewDownloadFile(
+		service.NewDownloadService(server.Client(), config.DefaultDownloaderConfig()),
+		service.NewArtifactResolver(nil),
+		service.NewArchiveExpander(service.DefaultExpandLimits()),
+		storage,
+		&fakeDatabase{},
+		relay,
+		queue,
+		downloadPkg.RetryPolicy{},
+		config.QueueNames{Downloader: "downloader", Processor: "processor"},
+		repositories,
+		"worker-1",
+		obs,
+	)
+	require.NoError(t, err)
+
+	return &downloadFixture{repositories: repositories, storage: storage, download: download, server: server}
+}
+
+// addDownload adds a pending download of the report, of kind fetched from path
+func (f *downloadFixture) addDownload(t *testing.T, id int64, kind downloadPkg.ArtifactKind, path string) {
+	t.Helper()
+	dl := sharedDownload.NewDownload(f.repositories.report.ID, 3)
+	if kind != downloadPkg.ArtifactKindReport {
+		var err error
+		dl, err = sharedDownload.NewArtifactDownload(f.repositories.report.ID, kind, f.server.URL+path, 3)
+		require.NoError(t, err)
+	}
+	dl.ID = id
+	f.repositories.downloads[id] = dl
+}
+
+func (f *downloadFixture) run(t *testing.T, id int64) {
+	t.Helper()
+	err := f.download.Download(t.Context(), &dto.DownloadRequest{
+		EventType:  dto.DownloadEventRequested,
+		DownloadID: id,
+	})
+	require.NoError(t, err)
+}
+
+func TestDownload_IdenticalFileReusesBlob(t *testing.T) {
+	f := newDownloadFixture(t)
+	f.addDownload(t, 1, downloadPkg.ArtifactKindReport, "")
+	f.addDownload(t, 2, downloadPkg.ArtifactKindFindings, "/vault-findings.pdf")
+
+	f.run(t, 1)
+	first := f.repositories.downloads[1]
+	require.True(t, first.IsCompleted())
+	require.NotNil(t, first.StoragePath)
+
+	f.run(t, 2)
+	second := f.repositories.downloads[2]
+	require.True(t, second.IsCompleted())
+
+	// The second file has the same hash, it links the blob of the first
+	require.NotNil(t, second.StoragePath)
+	assert.Equal(t, *first.StoragePath, *second.StoragePath)
+	assert.Equal(t, *first.FileHash, *second.FileHash)
+	require.Len(t, f.repositories.blobs, 2)
+	assert.Equal(t, *first.StoragePath, f.repositories.blobs[1].StoragePath)
+	assert.Equal(t, int64(2), f.repositories.blobs[1].DownloadID)
+
+	// Its own upload is deleted, the shared blob is kept
+	assert.Len(t, f.storage.deleted, 1)
+	assert.NotEqual(t, *first.StoragePath, f.storage.deleted[0])
+	assert.Contains(t, f.storage.objects, *first.StoragePath)
+	assert.Len(t, f.storage.objects, 1)
+}
+
+func TestSharedBlobPath(t *testing.T) {
+	const hash = "5f2b"
+	sharedPath := "code4rena/1_vault/report.pdf"
+
+	tests := []struct {
+		name     string
+		existing *entity.Download
+		findErr  error
+		want     string
+	}{
+		{
+			name: "new file keeps its upload",
+			want: "uploaded.pdf",
+		},
+		{
+			name:     "identical file reuses the blob",
+			existing: &entity.Download{ID: 1, Status: downloadPkg.StatusCompleted, FileHash: ptr(hash), StoragePath: &sharedPath},
+			want:     sharedPath,
+		},
+		{
+			name:     "file of another hash is no duplicate",
+			existing: &entity.Download{ID: 1, Status: downloadPkg.StatusCompleted, FileHash: ptr("9a01"), StoragePath: &sharedPath},
+			want:     "uploaded.pdf",
+		},
+		{
+			name:    "failed lookup keeps the upload",
+			findErr: errUnavailable,
+			want:    "uploaded.pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDownloadFixture(t)
+			f.repositories.findErr = tt.findErr
+			if tt.existing != nil {
+				f.repositories.downloads[tt.existing.ID] = tt.existing
+			}
+
+			assert.Equal(t, tt.want, f.download.sharedBlobPath(t.Context(), 2, hash, "uploaded.pdf"))
+		})
+	}
+}
+
+func TestRemoveUnreferencedBlob(t *testing.T) {
+	const path = "code4rena/1_vault/report.pdf"
+
+	tests := []struct {
+		name       string
+		references int64
+		countErr   error
+		deleted    bool
+	}{
+		{name: "unreferenced blob is deleted", deleted: true},
+		{name: "blob linked by a download is kept", references: 1},
+		{name: "blob of a previous version is kept", references: 2},
+		{name: "failed count keeps the blob", countErr: errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDownloadFixture(t)
+			f.storage.objects[path] = []byte("%PDF-1.4")
+			f.repositories.references[path] = tt.references
+			f.repositories.countErr = tt.countErr
+
+			f.download.removeUnreferencedBlob(t.Context(), path)
+
+			_, kept := f.storage.objects[path]
+			assert.Equal(t, !tt.deleted, kept)
+		})
+	}
+}
+
+func TestRemoveUnreferencedBlob_KeepsLinkedBlob(t *testing.T) {
+	f := newDownloadFixture(t)
+	f.addDownload(t, 1, downloadPkg.ArtifactKindReport, "")
+	f.run(t, 1)
+
+	// The completed download links its blob
+	path := *f.repositories.downloads[1].StoragePath
+	f.download.removeUnreferencedBlob(t.Context(), path)
+
+	assert.Contains(t, f.storage.objects, path)
+	assert.Empty(t, f.storage.deleted)
+	assert.True(t, bytes.HasPrefix(f.storage.objects[path], []byte("%PDF")))
+}
+
+func TestDiscard(t *testing.T) {
+	const (
+		path     = "code4rena/1_vault/report.zip"
+		snapshot = "code4rena/1_vault/report.html"
+	)
+
+	tests := []struct {
+		name    string
+		file    *storedFile
+		deleted []string
+	}{
+		{name: "file", file: &storedFile{path: path}, deleted: []string{path}},
+		{name: "file resolved from a page", file: &storedFile{path: path, snapshotPath: snapshot}, deleted: []string{path, snapshot}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDownloadFixture(t)
+			f.storage.objects[path] = []byte("PK\x03\x04")
+			f.storage.objects[snapshot] = []byte("<html></html>")
+
+			f.download.discard(t.Context(), tt.file)
+
+			assert.Equal(t, tt.deleted, f.storage.deleted)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
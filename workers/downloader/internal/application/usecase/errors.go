@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"downloader/internal/domain/entity/download"
 	"fmt"
 )
 
@@ -20,6 +21,17 @@ func ErrDownloadFileDownloadFailed(err error) error {
 	return fmt.Errorf("failed to download file: %w", err)
 }
 
+// ErrDownloadFileRejected marks err as a permanent failure, see downloadPkg.ErrRejected
+func ErrDownloadFileRejected(err error) error {
+	return fmt.Errorf("%w: %w", download.ErrRejected, err)
+}
+
+// ErrArtifactKindMismatch rejects an event addressing another artifact than
+// the one its download holds
+func ErrArtifactKindMismatch(requested string, stored download.ArtifactKind) error {
+	return fmt.Errorf("%w: event addresses the %q artifact, download holds %q", download.ErrRejected, requested, stored)
+}
+
 func ErrDownloadFileUploadFailed(err error) error {
 	return fmt.Errorf("failed to upload download status: %w", err)
 }
@@ -28,6 +40,22 @@ func ErrProcessCreationFailed(err error) error {
 	return fmt.Errorf("failed to create process: %w", err)
 }
 
-func ErrPublishProcessEvent(err error) error {
-	return fmt.Errorf("failed to publish process event: %w", err)
+func ErrProcessUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update process: %w", err)
+}
+
+func ErrDownloadVersionFailed(err error) error {
+	return fmt.Errorf("failed to store previous download version: %w", err)
+}
+
+func ErrOutboxMessageFailed(err error) error {
+	return fmt.Errorf("failed to store process event: %w", err)
+}
+
+func ErrArchiveExpansionFailed(err error) error {
+	return fmt.Errorf("failed to expand archive: %w", err)
+}
+
+func ErrReportBlobLinkFailed(err error) error {
+	return fmt.Errorf("failed to link report to its blob: %w", err)
 }
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"strings"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/service"
+)
+
+// manifestName is the object listing the files expanded out of an archive
+const manifestName = "manifest.json"
+
+// entryContentTypes are the content types of the expanded files
+var entryContentTypes = map[string]string{
+	".pdf":      "application/pdf",
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+}
+
+// expandArchive stores the PDF and markdown files of an archive next to it,
+// under a folder named after the archive, with a manifest listing them. It
+// returns the path of the manifest, empty when the file is no archive.
+// Entries stored before a failure are removed.
+func (p *DownloadFile) expandArchive(ctx context.Context, file *storedFile, metadata ports.ObjectMetadata) (string, error) {
+	format, ok := service.ArchiveFormatOf(file.result.MIMEType(), file.result.Extension())
+	if !ok {
+		return "", nil
+	}
+
+	archive, size, err := p.spool(ctx, file.path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		archive.Close()
+		os.Remove(archive.Name())
+	}()
+
+	prefix := expandedPrefix(file.path)
+	var stored []string
+	manifest, err := p.expander.Expand(ctx, format, archive, size, func(entryPath string, body io.Reader) (string, error) {
+		entryMetadata := metadata
+		entryMetadata.ContentType = entryContentTypes[strings.ToLower(path.Ext(entryPath))]
+		entryMetadata.UserMetadata = maps.Clone(metadata.UserMetadata)
+		entryMetadata.UserMetadata["archive_entry"] = entryPath
+
+		storagePath := prefix + entryPath
+		if err := p.storage.Put(ctx, "", storagePath, body, entryMetadata); err != nil {
+			return "", err
+		}
+		stored = append(stored, storagePath)
+		return storagePath, nil
+	})
+	if err == nil && manifest != nil {
+		err = p.putManifest(ctx, prefix+manifestName, manifest, metadata)
+	}
+	if err != nil {
+		for _, storagePath := range stored {
+			p.removeUnreferencedBlob(ctx, storagePath)
+		}
+		return "", ErrArchiveExpansionFailed(err)
+	}
+	if manifest == nil {
+		return "", nil
+	}
+
+	p.logger.Info("Archive expanded",
+		"storage_path", file.path,
+		"format", string(format),
+		"entries", len(manifest.Entries),
+		"skipped", manifest.Skipped)
+	p.metrics.IncrementCounter("downloader.archives_expanded", map[string]string{"format": string(format)})
+	p.metrics.RecordHistogram("downloader.archive_entries", float64(len(manifest.Entries)), nil)
+	return prefix + manifestName, nil
+}
+
+func (p *DownloadFile) putManifest(ctx context.Context, storagePath string, manifest *service.Manifest, metadata ports.ObjectMetadata) error {
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	metadata.ContentType = "application/json"
+	return p.storage.Put(ctx, "", storagePath, bytes.NewReader(encoded), metadata)
+}
+
+// spool copies a stored object to a temporary file, as zip archives can
+// only be read with random access. The caller closes and removes the file.
+func (p *DownloadFile) spool(ctx context.Context, storagePath string) (*os.File, int64, error) {
+	reader, err := p.storage.Get(ctx, "", storagePath)
+	if err != nil {
+		return nil, 0, ErrArchiveExpansionFailed(err)
+	}
+	defer reader.Close()
+
+	file, err := os.CreateTemp("", "archive-*")
+	if err != nil {
+		return nil, 0, ErrArchiveExpansionFailed(err)
+	}
+
+	size, err := io.Copy(file, reader)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, 0, ErrArchiveExpansionFailed(err)
+	}
+	return file, size, nil
+}
+
+// expandedPrefix is the folder of the files expanded out of the archive at
+// storagePath: the archive path without its extensions
+func expandedPrefix(storagePath string) string {
+	base := strings.TrimSuffix(storagePath, path.Ext(storagePath))
+	return strings.TrimSuffix(base, ".tar") + "/"
+}
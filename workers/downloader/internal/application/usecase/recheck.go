@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"downloader/internal/application/ports"
+	downloadPkg "downloader/internal/domain/entity/download"
+	"downloader/internal/domain/entity/downloadresult"
+	"downloader/internal/domain/entity/downloadversion"
+	"downloader/internal/domain/service"
+)
+
+// recheckClaimWindow keeps concurrent deliveries of the same re-check from
+// running twice; it must exceed the time a re-download can take
+const recheckClaimWindow = 15 * time.Minute
+
+// recheck asks the server whether the file of a completed download changed,
+// using the validators of the stored copy. An unchanged file costs no attempt
+// and no transfer; a changed one is stored as a new version next to the
+// previous one, which is kept, and is processed again.
+//
+// Failures leave the download completed with its current file.
+func (p *DownloadFile) recheck(ctx context.Context, download *downloadPkg.Download) error {
+	if !download.IsCompleted() {
+		return downloadPkg.ErrNotCompleted
+	}
+
+	// 1. Reserve the re-check
+	download, err := p.repositories.Download().ClaimRecheck(ctx, download.ID, p.workerID, time.Now().Add(-recheckClaimWindow))
+	if err != nil {
+		if errors.Is(err, downloadPkg.ErrClaimedElsewhere) {
+			return err
+		}
+		return ErrDownloadFileUpdateFailed(err)
+	}
+
+	report, err := p.repositories.AuditReport().Get(ctx, download.ReportID)
+	if err != nil {
+		return ErrDownloadFileAuditReportNotFound(err)
+	}
+
+	provider, err := p.repositories.AuditProvider().Get(ctx, report.ProviderID)
+	if err != nil {
+		return ErrAuditProviderNotFound(err)
+	}
+
+	ctx = ports.WithProviderSlug(ctx, provider.Slug)
+
+	// 2. Fill in the source archive of files downloaded without one
+	archiveMetadata := ports.ObjectMetadata{
+		UserMetadata: map[string]string{
+			"report_id": fmt.Sprintf("%d", report.ID),
+			"provider":  provider.Slug,
+		},
+	}
+	if download.IsReport() && p.storeSourceArchive(ctx, download, report.RepositoryURL, report.RepositoryRef,
+		report.StoragePath(provider, sourceArchiveExtension), archiveMetadata) {
+		if err := p.repositories.Download().Update(ctx, download); err != nil {
+			return ErrDownloadFileUpdateFailed(err)
+		}
+	}
+
+	// 3. Conditional request
+	stream, err := p.downloadService.DownloadIfModified(ctx, download.URL(report.SourceDownloadURL), validatorsOf(download))
+	if errors.Is(err, service.ErrNotModified) {
+		p.logger.Info("Download unchanged", "download_id", download.ID, "version", download.Version)
+		p.metrics.IncrementCounter("downloader.recheck", map[string]string{"result": "unchanged"})
+		return nil
+	}
+	if err != nil {
+		return ErrDownloadFileDownloadFailed(err)
+	}
+	defer stream.Close()
+	p.logMismatch(download.ID, stream)
+
+	// 4. Stream the new file next to the current one
+	metadata := ports.ObjectMetadata{
+		UserMetadata: map[string]string{
+			"report_id": fmt.Sprintf("%d", report.ID),
+			"provider":  provider.Slug,
+			"kind":      string(download.Kind),
+			"version":   fmt.Sprintf("%d", download.Version+1),
+		},
+	}
+
+	file, err := p.storeFile(ctx, provider.Slug, stream, func(extension string) string {
+		return download.VersionedPath(report.ArtifactStoragePath(provider, string(download.Kind), extension))
+	}, metadata)
+	if err != nil {
+		return err
+	}
+
+	validators := stream.Validators()
+
+	// 5. Servers without validators, or with weak ones, resend identical files
+	if download.FileHash != nil && *download.FileHash == file.result.Hash() {
+		p.discard(ctx, file)
+
+		download.RecordValidators(validators.ETag, validators.LastModified)
+		if err := p.repositories.Download().Update(ctx, download); err != nil {
+			return ErrDownloadFileUpdateFailed(err)
+		}
+
+		p.logger.Info("Download unchanged", "download_id", download.ID, "version", download.Version)
+		p.metrics.IncrementCounter("downloader.recheck", map[string]string{"result": "unchanged"})
+		return nil
+	}
+
+	// 6. Revise the download, keeping the previous version
+	manifestPath, err := p.expandArchive(ctx, file, metadata)
+	if err != nil {
+		p.discard(ctx, file)
+		return err
+	}
+
+	previous, err := downloadversion.NewDownloadVersion(download)
+	if err != nil {
+		return err
+	}
+
+	blobPath := p.sharedBlobPath(ctx, download.ID, file.result.Hash(), file.path)
+	if err := download.Revise(blobPath, file.result.Hash(), file.result.Extension()); err != nil {
+		return err
+	}
+	download.RecordValidators(validators.ETag, validators.LastModified)
+	download.RecordMIMEType(file.result.MIMEType())
+	download.RecordSnapshot(file.snapshotPath)
+	download.RecordManifest(manifestPath)
+
+	message, err := p.commitCompletion(ctx, download, previous)
+	if err != nil {
+		return err
+	}
+
+	if blobPath != file.path {
+		p.removeUnreferencedBlob(ctx, file.path)
+	}
+
+	p.logger.Info("Download revised",
+		"download_id", download.ID,
+		"version", download.Version,
+		"storage_path", blobPath)
+	p.metrics.IncrementCounter("downloader.recheck", map[string]string{"result": "revised"})
+
+	// 7. Publish the process event; the outbox covers a failure
+	if message == nil {
+		return nil
+	}
+	if err := p.relay.Send(ctx, message); err != nil {
+		p.logger.Error("Failed to publish process event, left in outbox",
+			"download_id", download.ID,
+			"outbox_id", message.ID,
+			"error", err.Error())
+	}
+
+	return nil
+}
+
+func validatorsOf(download *downloadPkg.Download) downloadresult.Validators {
+	var validators downloadresult.Validators
+	if download.ETag != nil {
+		validators.ETag = *download.ETag
+	}
+	if download.LastModified != nil {
+		validators.LastModified = *download.LastModified
+	}
+	return validators
+}
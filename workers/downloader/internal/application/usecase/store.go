@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/entity/downloadresult"
+	"downloader/internal/domain/service"
+)
+
+// maxScannedPage caps the part of a report page kept to look for its artifact
+const maxScannedPage = 2 * 1024 * 1024
+
+// storedFile is a file streamed to storage. When it was resolved from a
+// report page, snapshotPath holds the copy of that page.
+type storedFile struct {
+	path         string
+	result       *downloadresult.DownloadResult
+	snapshotPath string
+}
+
+// storeFile streams the remote file into storage at pathFor(extension). When
+// the provider has a resolve rule and the file is a report page, the page is
+// kept as a snapshot and the artifact it links to is stored as the file.
+//
+// Resolution is best effort: without a link, or with an artifact that cannot
+// ever be downloaded, the page itself is the file. Transient failures of the
+// artifact are returned so the download is retried.
+func (p *DownloadFile) storeFile(
+	ctx context.Context,
+	providerSlug string,
+	stream *downloadresult.Stream,
+	pathFor func(extension string) string,
+	metadata ports.ObjectMetadata,
+) (*storedFile, error) {
+	rule, resolvable := p.resolver.Rule(providerSlug)
+	if !resolvable || stream.MIMEType() != downloadresult.MIMETypeHTML {
+		return p.put(ctx, stream, stream, pathFor(stream.Extension()), metadata)
+	}
+
+	page := &pageBuffer{limit: maxScannedPage}
+	snapshot, err := p.put(ctx, stream, io.TeeReader(stream, page), pathFor(stream.Extension()), metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactURL, err := p.resolver.Resolve(rule, stream.URL(), page.Bytes())
+	if err != nil {
+		p.logger.Info("No artifact linked from report page, keeping the page",
+			"url", stream.URL(),
+			"rule", string(rule),
+			"error", err.Error())
+		return snapshot, nil
+	}
+
+	artifact, err := p.downloadService.Download(ctx, artifactURL)
+	if err != nil {
+		return p.keepPage(snapshot, artifactURL, ErrDownloadFileDownloadFailed(err))
+	}
+	defer artifact.Close()
+
+	// An artifact of the same type is another page, and would overwrite this one
+	if artifact.Extension() == stream.Extension() {
+		p.logger.Info("Report page links another page, keeping the page",
+			"url", stream.URL(),
+			"artifact_url", artifactURL)
+		return snapshot, nil
+	}
+
+	file, err := p.put(ctx, artifact, artifact, pathFor(artifact.Extension()), metadata)
+	if err != nil {
+		return p.keepPage(snapshot, artifactURL, err)
+	}
+
+	p.logger.Info("Resolved report page to its artifact",
+		"url", stream.URL(),
+		"artifact_url", artifactURL,
+		"storage_path", file.path)
+	p.metrics.IncrementCounter("downloader.artifacts_resolved", map[string]string{"rule": string(rule)})
+
+	file.snapshotPath = snapshot.path
+	return file, nil
+}
+
+// discard removes a stored file that will not be recorded, with its snapshot
+func (p *DownloadFile) discard(ctx context.Context, file *storedFile) {
+	p.removeUnreferencedBlob(ctx, file.path)
+	if file.snapshotPath != "" {
+		p.removeUnreferencedBlob(ctx, file.snapshotPath)
+	}
+}
+
+// keepPage falls back to the page when its artifact failed for good, and
+// returns transient failures
+func (p *DownloadFile) keepPage(page *storedFile, artifactURL string, err error) (*storedFile, error) {
+	if !service.Classify(err).IsPermanent() {
+		return nil, err
+	}
+
+	p.logger.Info("Artifact of report page unavailable, keeping the page",
+		"artifact_url", artifactURL,
+		"error", err.Error())
+	return page, nil
+}
+
+// put streams source, which reads through stream, into storage at path
+func (p *DownloadFile) put(
+	ctx context.Context,
+	stream *downloadresult.Stream,
+	source io.Reader,
+	path string,
+	metadata ports.ObjectMetadata,
+) (*storedFile, error) {
+	metadata.ContentType = stream.ContentType()
+	if err := p.storage.Put(ctx, "", path, source, metadata); err != nil {
+		// If the stream itself broke (e.g. file too large) it is a download failure
+		if streamErr := stream.Err(); streamErr != nil {
+			return nil, ErrDownloadFileDownloadFailed(streamErr)
+		}
+		return nil, ErrDownloadFileUploadFailed(err)
+	}
+
+	result, err := stream.Result()
+	if err != nil {
+		p.removeUnreferencedBlob(ctx, path)
+		return nil, ErrDownloadFileDownloadFailed(err)
+	}
+
+	return &storedFile{path: path, result: result}, nil
+}
+
+// pageBuffer keeps the first limit bytes written to it and drops the rest,
+// so teeing a large page into it never fails the upload
+type pageBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *pageBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
@@ -3,7 +3,16 @@ package download
 import "shared/domain/entity/download"
 
 type (
-	Download = download.Download
+	Download     = download.Download
+	RetryPolicy  = download.RetryPolicy
+	ErrorCode    = download.ErrorCode
+	ArtifactKind = download.ArtifactKind
+)
+
+const (
+	ArtifactKindReport    = download.ArtifactKindReport
+	ArtifactKindFindings  = download.ArtifactKindFindings
+	ArtifactKindFixReview = download.ArtifactKindFixReview
 )
 
 const (
@@ -11,6 +20,19 @@ const (
 	StatusInProgress download.Status = "in_progress"
 	StatusCompleted  download.Status = "completed"
 	StatusFailed     download.Status = "failed"
+	StatusRejected   download.Status = "rejected"
+)
+
+const (
+	ErrorCodeNetwork        = download.ErrorCodeNetwork
+	ErrorCodeServerError    = download.ErrorCodeServerError
+	ErrorCodeInternal       = download.ErrorCodeInternal
+	ErrorCodeRateLimited    = download.ErrorCodeRateLimited
+	ErrorCodeClientError    = download.ErrorCodeClientError
+	ErrorCodeTooLarge       = download.ErrorCodeTooLarge
+	ErrorCodeInvalidContent = download.ErrorCodeInvalidContent
+	ErrorCodeBlocked        = download.ErrorCodeBlocked
+	ErrorCodeDisallowed     = download.ErrorCodeDisallowed
 )
 
 var (
@@ -19,10 +41,17 @@ var (
 	ErrAlreadyInProgress      = download.ErrAlreadyInProgress
 	ErrInvalidStateTransition = download.ErrInvalidStateTransition
 	ErrNotInProgress          = download.ErrNotInProgress
+	ErrNotCompleted           = download.ErrNotCompleted
+
+	// Concurrency errors
+	ErrClaimedElsewhere = download.ErrClaimedElsewhere
 
 	// Retry/attempt errors
 	ErrMaxAttemptsExceeded = download.ErrMaxAttemptsExceeded
 	ErrCannotRetry         = download.ErrCannotRetry
+	ErrRetryNotDue         = download.ErrRetryNotDue
+	ErrRejected            = download.ErrRejected
+	ErrPostponed           = download.ErrPostponed
 
 	// Validation errors
 	ErrEmptyStoragePath   = download.ErrEmptyStoragePath
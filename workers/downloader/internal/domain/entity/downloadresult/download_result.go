@@ -1,22 +1,24 @@
 package downloadresult
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"io"
 	"path/filepath"
 	"strings"
 )
 
+// DownloadResult describes a file that has been fully streamed to its destination.
+// The content itself is never held in memory; only its digest and size are kept.
 type DownloadResult struct {
-	content     []byte
+	hash        string
+	size        int64
 	contentType string
 	url         string
+	extension   string // set when streamed, see Stream.Check
+	mimeType    string
 }
 
 // NewDownloadResult creates a valid DownloadResult with validation
-func NewDownloadResult(content []byte, url string, contentType string) (*DownloadResult, error) {
-	if len(content) == 0 {
+func NewDownloadResult(hash string, size int64, url string, contentType string) (*DownloadResult, error) {
+	if size <= 0 {
 		return nil, ErrEmptyContent
 	}
 
@@ -25,68 +27,39 @@ func NewDownloadResult(content []byte, url string, contentType string) (*Downloa
 	}
 
 	return &DownloadResult{
-		content:     content,
+		hash:        hash,
+		size:        size,
 		url:         url,
 		contentType: normalizeContentType(contentType),
 	}, nil
 }
 
-// Add this factory method to create DownloadResult from a reader
-func NewDownloadResultFromReader(
-	reader io.Reader,
-	url string,
-	contentType string,
-	maxSize int64,
-) (*DownloadResult, error) {
-	if url == "" {
-		return nil, ErrEmptyUrl
-	}
-
-	// Read with size limit
-	limitedReader := io.LimitReader(reader, maxSize+1)
-	content, err := io.ReadAll(limitedReader)
-	if err != nil {
-		return nil, ErrReadContent(err)
-	}
-
-	// Check if we exceeded the limit
-	currLen := int64(len(content))
-	if currLen > maxSize {
-		return nil, ErrSizeExceeded(maxSize)
-	}
-
-	// Delegate to the existing constructor
-	return NewDownloadResult(content, url, contentType)
-}
-
-// Extension determines the file extension from URL and content type
+// Extension determines the file extension from URL and content type, unless
+// the stream settled it from the detected type
 func (r *DownloadResult) Extension() string {
-	// First try to get from URL
-	if ext := r.extensionFromURL(); ext != "" {
-		return ext
+	if r.extension != "" {
+		return r.extension
 	}
-
-	// Fallback to content type
-	return r.extensionFromContentType()
+	return extensionFor(r.url, r.contentType)
 }
 
-// Content returns the downloaded content (immutable - returns copy)
-func (r *DownloadResult) Content() []byte {
-	// Return a copy to maintain immutability
-	result := make([]byte, len(r.content))
-	copy(result, r.content)
-	return result
+// MIMEType returns the type detected from the content, or the declared one
+// when the content was not sniffed
+func (r *DownloadResult) MIMEType() string {
+	if r.mimeType != "" {
+		return r.mimeType
+	}
+	return r.contentType
 }
 
-// Hash calculates and returns the SHA256 hash (computed, not stored)
+// Hash returns the SHA256 hash of the streamed content
 func (r *DownloadResult) Hash() string {
-	hash := sha256.Sum256(r.content)
-	return hex.EncodeToString(hash[:])
+	return r.hash
 }
 
-// Size returns the content size (computed, not stored)
+// Size returns the number of bytes streamed
 func (r *DownloadResult) Size() int64 {
-	return int64(len(r.content))
+	return r.size
 }
 
 // ContentType returns the normalized content type
@@ -117,10 +90,19 @@ func (r *DownloadResult) IsImage() bool {
 	return strings.HasPrefix(r.contentType, "image/")
 }
 
-// Private helper methods
-func (r *DownloadResult) extensionFromURL() string {
+// Private helper functions
+
+// extensionFor tries the URL suffix first and falls back to the content type
+func extensionFor(url string, contentType string) string {
+	if ext := extensionFromURL(url); ext != "" {
+		return ext
+	}
+	return extensionFromContentType(contentType)
+}
+
+func extensionFromURL(url string) string {
 	// Remove query parameters
-	cleanURL := r.url
+	cleanURL := url
 	if idx := strings.Index(cleanURL, "?"); idx != -1 {
 		cleanURL = cleanURL[:idx]
 	}
@@ -133,7 +115,7 @@ func (r *DownloadResult) extensionFromURL() string {
 	return ""
 }
 
-func (r *DownloadResult) extensionFromContentType() string {
+func extensionFromContentType(contentType string) string {
 	// Map content types to extensions
 	contentTypeToExt := map[string]string{
 		"application/pdf":   ".pdf",
@@ -147,15 +129,17 @@ func (r *DownloadResult) extensionFromContentType() string {
 		"image/jpeg":        ".jpg",
 		"image/png":         ".png",
 		"image/gif":         ".gif",
+		"image/webp":        ".webp",
+		"text/markdown":     ".md",
 	}
 
-	if ext, ok := contentTypeToExt[r.contentType]; ok {
+	if ext, ok := contentTypeToExt[contentType]; ok {
 		return ext
 	}
 
 	// Try to extract from content type (e.g., "image/jpeg" -> ".jpeg")
-	if strings.Contains(r.contentType, "/") {
-		parts := strings.Split(r.contentType, "/")
+	if strings.Contains(contentType, "/") {
+		parts := strings.Split(contentType, "/")
 		if len(parts) == 2 && parts[1] != "*" {
 			return "." + strings.ToLower(parts[1])
 		}
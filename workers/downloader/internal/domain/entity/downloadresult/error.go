@@ -6,14 +6,25 @@ import (
 )
 
 var (
-	ErrEmptyContent = errors.New("download content cannot be empty")
-	ErrEmptyUrl     = errors.New("download URL cannot be empty")
+	ErrEmptyContent      = errors.New("download content cannot be empty")
+	ErrEmptyUrl          = errors.New("download URL cannot be empty")
+	ErrContentTooLarge   = errors.New("content size exceeds maximum")
+	ErrStreamNotConsumed = errors.New("download stream was not read to the end")
+
+	// Content validation errors
+	ErrContentMismatch = errors.New("content does not match its declared type")
+	ErrSoftNotFound    = errors.New("content is a not found page")
+	ErrLoginWall       = errors.New("content is a login page")
 )
 
 func ErrSizeExceeded(maxLen int64) error {
-	return fmt.Errorf("content size exceeds maximum %d", maxLen)
+	return fmt.Errorf("%w %d", ErrContentTooLarge, maxLen)
 }
 
 func ErrReadContent(err error) error {
 	return fmt.Errorf("failed to read content: %w", err)
 }
+
+func ErrMismatch(mimeType, extension string) error {
+	return fmt.Errorf("%w: detected %s, declared %s", ErrContentMismatch, mimeType, extension)
+}
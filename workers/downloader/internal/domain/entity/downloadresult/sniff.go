@@ -0,0 +1,149 @@
+package downloadresult
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLength is how much of the body is inspected before it is streamed
+const sniffLength = 4096
+
+// Content types recognised from the first bytes of a file
+const (
+	MIMETypePDF      = "application/pdf"
+	MIMETypeZIP      = "application/zip"
+	MIMETypeGzip     = "application/gzip"
+	MIMETypeHTML     = "text/html"
+	MIMETypeMarkdown = "text/markdown"
+	MIMETypeText     = "text/plain"
+	MIMETypePNG      = "image/png"
+	MIMETypeJPEG     = "image/jpeg"
+	MIMETypeGIF      = "image/gif"
+	MIMETypeWebP     = "image/webp"
+	MIMETypeUnknown  = "application/octet-stream"
+)
+
+// MismatchPolicy decides what happens when the bytes of a file contradict
+// the extension given by its URL or Content-Type
+type MismatchPolicy string
+
+const (
+	MismatchReject  MismatchPolicy = "reject"  // fail the download as invalid content
+	MismatchWarn    MismatchPolicy = "warn"    // keep the declared extension, report the mismatch
+	MismatchCorrect MismatchPolicy = "correct" // store the file under the detected extension
+)
+
+// signatures are the magic bytes of binary formats, checked in order
+var signatures = []struct {
+	prefix   []byte
+	mimeType string
+}{
+	{[]byte("%PDF-"), MIMETypePDF},
+	{[]byte("PK\x03\x04"), MIMETypeZIP},
+	{[]byte("PK\x05\x06"), MIMETypeZIP}, // empty archive
+	{[]byte("\x1f\x8b"), MIMETypeGzip},
+	{[]byte("\x89PNG\r\n\x1a\n"), MIMETypePNG},
+	{[]byte("\xff\xd8\xff"), MIMETypeJPEG},
+	{[]byte("GIF87a"), MIMETypeGIF},
+	{[]byte("GIF89a"), MIMETypeGIF},
+}
+
+// extensionsByMIMEType lists the extensions a detected type may be declared
+// with. Markdown, plain text and unknown binaries are absent: they are only
+// guessed, so they never contradict the declared extension.
+var extensionsByMIMEType = map[string][]string{
+	MIMETypePDF:  {".pdf"},
+	MIMETypeZIP:  {".zip", ".docx", ".xlsx", ".pptx", ".odt", ".jar"},
+	MIMETypeGzip: {".gz", ".tgz"},
+	MIMETypeHTML: {".html", ".htm"},
+	MIMETypePNG:  {".png"},
+	MIMETypeJPEG: {".jpg", ".jpeg"},
+	MIMETypeGIF:  {".gif"},
+	MIMETypeWebP: {".webp"},
+}
+
+var (
+	titlePattern         = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	passwordInputPattern = regexp.MustCompile(`(?i)<input[^>]+type=["']?password`)
+	markdownPattern      = regexp.MustCompile(`(?m)^(#{1,6} |\x60\x60\x60|\|.*\|\s*$|[-*] \[[ x]\] )`)
+
+	// Titles of pages served with 200 in place of a missing or private file
+	softNotFoundTitles = []string{"404", "not found", "page not found", "does not exist", "no longer available"}
+	loginWallTitles    = []string{"sign in", "log in", "login", "access denied", "unauthorized"}
+)
+
+// DetectMIMEType identifies the format of a file from its first bytes
+func DetectMIMEType(head []byte) string {
+	for _, signature := range signatures {
+		if bytes.HasPrefix(head, signature.prefix) {
+			return signature.mimeType
+		}
+	}
+	if len(head) >= 12 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")) {
+		return MIMETypeWebP
+	}
+
+	if !isText(head) {
+		return MIMETypeUnknown
+	}
+
+	text := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(string(head), "\ufeff")))
+	if strings.HasPrefix(text, "<!doctype html") || strings.HasPrefix(text, "<html") ||
+		strings.Contains(text, "<head") || strings.Contains(text, "<body") {
+		return MIMETypeHTML
+	}
+	if markdownPattern.MatchString(text) {
+		return MIMETypeMarkdown
+	}
+	return MIMETypeText
+}
+
+// isText checks if head looks like UTF-8 text. A rune cut at the end of the
+// sniffed bytes is tolerated.
+func isText(head []byte) bool {
+	if bytes.IndexByte(head, 0) != -1 {
+		return false
+	}
+	for len(head) > 0 {
+		r, size := utf8.DecodeRune(head)
+		if r == utf8.RuneError && size == 1 && len(head) >= utf8.UTFMax {
+			return false
+		}
+		head = head[size:]
+	}
+	return true
+}
+
+// checkPage rejects HTML pages standing in for the file, as hosts often
+// answer 200 with an error or login page
+func checkPage(head []byte) error {
+	if passwordInputPattern.Match(head) {
+		return ErrLoginWall
+	}
+
+	match := titlePattern.FindSubmatch(head)
+	if match == nil {
+		return nil
+	}
+	title := strings.ToLower(strings.TrimSpace(string(match[1])))
+	for _, marker := range softNotFoundTitles {
+		if strings.Contains(title, marker) {
+			return ErrSoftNotFound
+		}
+	}
+	for _, marker := range loginWallTitles {
+		if strings.Contains(title, marker) {
+			return ErrLoginWall
+		}
+	}
+	return nil
+}
+
+// isMismatch checks if a file detected as mimeType was declared with ext
+func isMismatch(mimeType, ext string) bool {
+	accepted, known := extensionsByMIMEType[mimeType]
+	return known && !slices.Contains(accepted, strings.ToLower(ext))
+}
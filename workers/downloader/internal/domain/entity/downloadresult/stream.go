@@ -0,0 +1,187 @@
+package downloadresult
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// Stream is a size-limited, hashing reader over a download body.
+// Bytes flow straight from the source to whoever consumes the stream
+// (usually storage), so the file is never buffered in memory.
+type Stream struct {
+	body        io.ReadCloser
+	source      io.Reader // the sniffed head, then the rest of body
+	head        []byte
+	hasher      hash.Hash
+	url         string
+	contentType string
+	mimeType    string
+	extension   string
+	mismatch    bool
+	validators  Validators
+	maxSize     int64
+	size        int64
+	eof         bool
+	err         error
+}
+
+// NewStream wraps body so that every byte read is hashed and counted. The
+// first bytes are read up front to detect the actual type of the file.
+func NewStream(body io.ReadCloser, url string, contentType string, maxSize int64) (*Stream, error) {
+	if url == "" {
+		return nil, ErrEmptyUrl
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, ErrReadContent(err)
+	}
+	head = head[:n]
+
+	contentType = normalizeContentType(contentType)
+	return &Stream{
+		body:        body,
+		source:      io.MultiReader(bytes.NewReader(head), body),
+		head:        head,
+		hasher:      sha256.New(),
+		url:         url,
+		contentType: contentType,
+		mimeType:    DetectMIMEType(head),
+		extension:   extensionFor(url, contentType),
+		maxSize:     maxSize,
+	}, nil
+}
+
+// Read implements io.Reader. It fails with ErrSizeExceeded as soon as
+// more than maxSize bytes have been read from the body.
+func (s *Stream) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	// Allow reading one byte past the limit so we can detect oversize bodies
+	if remaining := s.maxSize + 1 - s.size; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := s.source.Read(p)
+	s.size += int64(n)
+	s.hasher.Write(p[:n])
+
+	if s.size > s.maxSize {
+		s.err = ErrSizeExceeded(s.maxSize)
+		return 0, s.err
+	}
+
+	if err == io.EOF {
+		s.eof = true
+		return n, io.EOF
+	}
+	if err != nil {
+		s.err = ErrReadContent(err)
+		return n, s.err
+	}
+
+	return n, nil
+}
+
+// Close closes the underlying body
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
+
+// Err returns the error that interrupted the stream, if any.
+// Consumers can use it to tell a source failure apart from a sink failure.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Check rejects pages standing in for the file, such as soft 404 and login
+// pages, and applies policy when the detected type contradicts the extension
+func (s *Stream) Check(policy MismatchPolicy) error {
+	if s.mimeType == MIMETypeHTML {
+		if err := checkPage(s.head); err != nil {
+			return err
+		}
+	}
+
+	if !isMismatch(s.mimeType, s.extension) {
+		return nil
+	}
+	s.mismatch = true
+
+	switch policy {
+	case MismatchReject:
+		return ErrMismatch(s.mimeType, s.extension)
+	case MismatchCorrect:
+		s.extension = extensionFromContentType(s.mimeType)
+	}
+	return nil
+}
+
+// Extension returns the file extension from URL and content type, or the
+// detected one once Check corrected it
+func (s *Stream) Extension() string {
+	return s.extension
+}
+
+// MIMEType returns the type detected from the first bytes of the file
+func (s *Stream) MIMEType() string {
+	return s.mimeType
+}
+
+// Mismatch reports whether Check found the detected type contradicting the
+// declared extension
+func (s *Stream) Mismatch() bool {
+	return s.mismatch
+}
+
+// WithValidators records the validators the server returned with the body
+func (s *Stream) WithValidators(validators Validators) *Stream {
+	s.validators = validators
+	return s
+}
+
+// Validators returns the validators of the streamed file
+func (s *Stream) Validators() Validators {
+	return s.validators
+}
+
+// ContentType returns the normalized content type
+func (s *Stream) ContentType() string {
+	return s.contentType
+}
+
+// URL returns the source URL
+func (s *Stream) URL() string {
+	return s.url
+}
+
+// Result returns the digest and size of the streamed content.
+// It can only be called once the stream has been read to the end.
+func (s *Stream) Result() (*DownloadResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if !s.eof {
+		return nil, ErrStreamNotConsumed
+	}
+
+	result, err := NewDownloadResult(
+		hex.EncodeToString(s.hasher.Sum(nil)),
+		s.size,
+		s.url,
+		s.contentType,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.extension = s.extension
+	result.mimeType = s.mimeType
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package downloadresult
+
+// Validators are the HTTP cache validators of a downloaded file. Sent back on
+// the next request, they let the server answer 304 when the file is unchanged.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether there is nothing to make the request conditional on
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
@@ -0,0 +1,14 @@
+package downloadversion
+
+import (
+	"shared/domain/entity/download"
+	"shared/domain/entity/downloadversion"
+)
+
+type (
+	DownloadVersion = downloadversion.DownloadVersion
+)
+
+func NewDownloadVersion(d *download.Download) (*DownloadVersion, error) {
+	return downloadversion.NewDownloadVersion(d)
+}
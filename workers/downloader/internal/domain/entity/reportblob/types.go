@@ -0,0 +1,16 @@
+package reportblob
+
+import (
+	"shared/domain/entity/download"
+	"shared/domain/entity/reportblob"
+)
+
+type (
+	ReportBlob = reportblob.ReportBlob
+)
+
+var (
+	ErrDownloadNotCompleted = reportblob.ErrDownloadNotCompleted
+)
+
+func NewReportBlob(d *download.Download) (*ReportBlob, error) { return reportblob.NewReportBlob(d) }
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultRef asks the host for the default branch of the repository
+const defaultRef = "HEAD"
+
+var refPattern = regexp.MustCompile(`^[\w][\w./-]*$`)
+
+// SourceArchive is the tarball of an audited repository at one ref
+type SourceArchive struct {
+	URL string
+	Ref string
+}
+
+// ArchiveFor returns the tarball of the GitHub or GitLab repository at
+// repositoryURL. Without ref, the one in the URL is used (tree/ and commit/
+// links), then the default branch.
+func ArchiveFor(repositoryURL, ref string) (*SourceArchive, error) {
+	parsed, err := url.Parse(strings.TrimSpace(repositoryURL))
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRepositoryURL, repositoryURL)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+
+	switch {
+	case host == "github.com" || host == "www.github.com":
+		return githubArchive(segments, ref)
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return gitlabArchive(host, segments, ref)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRepository, host)
+	}
+}
+
+// githubArchive handles github.com/<owner>/<repo>[/tree|commit/<ref>]
+func githubArchive(segments []string, ref string) (*SourceArchive, error) {
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return nil, fmt.Errorf("%w: missing owner or repository", ErrInvalidRepositoryURL)
+	}
+	owner, repository := segments[0], strings.TrimSuffix(segments[1], ".git")
+
+	if ref == "" && len(segments) >= 4 && isRefSegment(segments[2]) {
+		ref = strings.Join(segments[3:], "/")
+	}
+	ref, err := checkRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceArchive{
+		URL: fmt.Sprintf("https://github.com/%s/%s/archive/%s.tar.gz", owner, repository, ref),
+		Ref: ref,
+	}, nil
+}
+
+// gitlabArchive handles <host>/<group>/.../<project>[/-/tree|commit/<ref>]
+func gitlabArchive(host string, segments []string, ref string) (*SourceArchive, error) {
+	project := segments
+	for i, segment := range segments {
+		if segment != "-" {
+			continue
+		}
+		project = segments[:i]
+		if ref == "" && len(segments) >= i+3 && isRefSegment(segments[i+1]) {
+			ref = strings.Join(segments[i+2:], "/")
+		}
+		break
+	}
+	if len(project) < 2 || project[len(project)-1] == "" {
+		return nil, fmt.Errorf("%w: missing group or project", ErrInvalidRepositoryURL)
+	}
+	project[len(project)-1] = strings.TrimSuffix(project[len(project)-1], ".git")
+	name := project[len(project)-1]
+
+	ref, err := checkRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceArchive{
+		URL: fmt.Sprintf("https://%s/%s/-/archive/%s/%s-%s.tar.gz",
+			host, strings.Join(project, "/"), ref, name, strings.ReplaceAll(ref, "/", "-")),
+		Ref: ref,
+	}, nil
+}
+
+func isRefSegment(segment string) bool {
+	return segment == "tree" || segment == "commit"
+}
+
+// checkRef defaults ref and keeps it from escaping the archive path
+func checkRef(ref string) (string, error) {
+	if ref == "" {
+		return defaultRef, nil
+	}
+	if !refPattern.MatchString(ref) || strings.Contains(ref, "..") {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRepositoryRef, ref)
+	}
+	return ref, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveFor_GitHub(t *testing.T) {
+	archive, err := ArchiveFor("https://github.com/code-423n4/2025-06-panoptic-hypovault", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/code-423n4/2025-06-panoptic-hypovault/archive/HEAD.tar.gz", archive.URL)
+	assert.Equal(t, "HEAD", archive.Ref)
+
+	archive, err = ArchiveFor("https://github.com/sherlock-audit/2025-01-vault.git/tree/3f2c1ab", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/sherlock-audit/2025-01-vault/archive/3f2c1ab.tar.gz", archive.URL)
+
+	// An explicit ref wins over the one in the URL
+	archive, err = ArchiveFor("https://github.com/sherlock-audit/2025-01-vault/tree/main", "9e8d7c6")
+	require.NoError(t, err)
+	assert.Equal(t, "9e8d7c6", archive.Ref)
+}
+
+func TestArchiveFor_GitLab(t *testing.T) {
+	archive, err := ArchiveFor("https://gitlab.com/group/sub/project/-/tree/release/v1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/group/sub/project/-/archive/release/v1/project-release-v1.tar.gz", archive.URL)
+	assert.Equal(t, "release/v1", archive.Ref)
+}
+
+func TestArchiveFor_Rejects(t *testing.T) {
+	_, err := ArchiveFor("https://bitbucket.org/team/repo", "")
+	assert.True(t, errors.Is(err, ErrUnsupportedRepository))
+
+	_, err = ArchiveFor("https://github.com/only-owner", "")
+	assert.True(t, errors.Is(err, ErrInvalidRepositoryURL))
+
+	_, err = ArchiveFor("https://github.com/owner/repo", "../../etc")
+	assert.True(t, errors.Is(err, ErrInvalidRepositoryRef))
+}
@@ -3,56 +3,127 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
 	"downloader/internal/application/ports"
 	"downloader/internal/domain/entity/downloadresult"
+	"shared/infrastructure/config"
 )
 
 type DownloadService struct {
-	httpClient  ports.HTTPClient
-	maxFileSize int64
-	userAgent   string
+	httpClient     ports.HTTPClient
+	config         config.DownloaderConfig
+	mismatchPolicy downloadresult.MismatchPolicy
 }
 
-func NewDownloadService(httpClient ports.HTTPClient, maxFileSize int64) *DownloadService {
+func NewDownloadService(httpClient ports.HTTPClient, cfg config.DownloaderConfig) *DownloadService {
 	return &DownloadService{
-		httpClient:  httpClient,
-		maxFileSize: maxFileSize,
-		userAgent:   "AuditReportDownloader/1.0",
+		httpClient:     httpClient,
+		config:         cfg,
+		mismatchPolicy: downloadresult.MismatchCorrect,
 	}
 }
 
-func (s *DownloadService) Download(ctx context.Context, url string) (*downloadresult.DownloadResult, error) {
+// WithMismatchPolicy sets what happens to files whose content contradicts
+// their declared type
+func (s *DownloadService) WithMismatchPolicy(policy downloadresult.MismatchPolicy) *DownloadService {
+	s.mismatchPolicy = policy
+	return s
+}
+
+// Download opens the remote file and returns a hashing, size-limited stream
+// over the response body. The caller must consume and Close the stream, then
+// call Result to obtain the hash and size of what was read.
+func (s *DownloadService) Download(ctx context.Context, url string) (*downloadresult.Stream, error) {
+	return s.DownloadIfModified(ctx, url, downloadresult.Validators{})
+}
+
+// DownloadIfModified works like Download but sends the validators of the copy
+// we hold, and returns ErrNotModified when the server reports it unchanged
+func (s *DownloadService) DownloadIfModified(ctx context.Context, url string, validators downloadresult.Validators) (*downloadresult.Stream, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, ErrRequestCreation(err)
 	}
+	if !slices.Contains(s.config.AllowedSchemes, req.URL.Scheme) {
+		return nil, ErrRequestCreation(fmt.Errorf("%w: %s", ErrSchemeNotAllowed, req.URL.Scheme))
+	}
+
+	req.Header.Set("User-Agent", s.config.UserAgent)
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
 
-	req.Header.Set("User-Agent", s.userAgent)
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
+		// The client may throttle or refuse the request before sending it
+		var limited *RateLimitError
+		if errors.As(err, &limited) {
+			return nil, err
+		}
+		var delayed *ports.CrawlDelayError
+		if errors.As(err, &delayed) {
+			return nil, ErrRateLimited(delayed.RetryAfter)
+		}
+		if errors.Is(err, ports.ErrDisallowed) {
+			return nil, ErrCrawlDisallowed(err)
+		}
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, ErrAddressBlocked(err)
+		}
+		// Redirects the client refused to follow will not be followed next time
+		if errors.Is(err, ErrSchemeNotAllowed) || errors.Is(err, ErrTooManyRedirects) {
+			return nil, ErrRedirectRefused(err)
+		}
 		return nil, ErrHTTPRequest(err)
 	}
 
-	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, ErrNotModified
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		return nil, ErrRateLimited(ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	}
+
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, ErrUnexpectedStatus(resp.StatusCode)
 	}
 
-	result, err := downloadresult.NewDownloadResultFromReader(
+	// Fail fast when the server announces a body we would reject anyway
+	maxFileSize := s.config.MaxFileSizeFor(ports.ProviderSlug(ctx))
+	if resp.ContentLength > maxFileSize {
+		resp.Body.Close()
+		return nil, ErrFileTooLarge
+	}
+
+	stream, err := downloadresult.NewStream(
 		resp.Body,
 		url,
 		resp.Header.Get("Content-Type"),
-		s.maxFileSize,
+		maxFileSize,
 	)
-
 	if err != nil {
-		if errors.Is(err, downloadresult.ErrSizeExceeded(s.maxFileSize)) {
-			return nil, ErrFileTooLarge
-		}
+		resp.Body.Close()
 		return nil, ErrReadResponse(err)
 	}
 
-	return result, nil
+	if err := stream.Check(s.mismatchPolicy); err != nil {
+		resp.Body.Close()
+		return nil, ErrInvalidContent(err)
+	}
+
+	return stream.WithValidators(downloadresult.Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}), nil
 }
@@ -1,35 +1,368 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/entity/download"
+	"downloader/internal/domain/entity/downloadresult"
+	"shared/infrastructure/config"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// limits returns the default downloader settings with maxFileSize
+func limits(maxFileSize int64) config.DownloaderConfig {
+	cfg := config.DefaultDownloaderConfig()
+	cfg.MaxFileSize = maxFileSize
+	return cfg
+}
+
+// clientFunc is a ports.HTTPClient answering with a function
+type clientFunc func(*http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestCode4rena_DownloadHTML(t *testing.T) {
 	MB := int64(1024 * 1024)
 	url := "https://code4rena.com/reports/2025-06-panoptic-hypovault"
-	svc := NewDownloadService(&http.Client{}, 10*MB)
+	svc := NewDownloadService(&http.Client{}, limits(10*MB))
+
+	stream, err := svc.Download(t.Context(), url)
+	require.NoError(t, err)
+	defer stream.Close()
 
-	result, _ := svc.Download(t.Context(), url)
-	content := string(result.Content())
-	ext := result.Extension()
+	content, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	result, err := stream.Result()
+	require.NoError(t, err)
 
-	assert.Equal(t, ext, ".html")
+	assert.Equal(t, result.Extension(), ".html")
 	assert.Equal(t, result.Hash(), "f2852b49daf89966747f802fecde25a79930d8dcaa4bd3ad7c1bce07ee349aad")
-	assert.True(t, strings.Contains(content, "Panoptic Hypovault"))
+	assert.True(t, strings.Contains(string(content), "Panoptic Hypovault"))
 }
 func TestCantina_DownloadPDF(t *testing.T) {
 	MB := int64(1024 * 1024)
 	url := "https://cdn.cantina.xyz/reports/cantina_opentrade_aug2025.pdf"
-	svc := NewDownloadService(&http.Client{}, 10*MB)
+	svc := NewDownloadService(&http.Client{}, limits(10*MB))
+
+	stream, err := svc.Download(t.Context(), url)
+	require.NoError(t, err)
+	defer stream.Close()
 
-	result, _ := svc.Download(t.Context(), url)
-	ext := result.Extension()
+	_, err = io.Copy(io.Discard, stream)
+	require.NoError(t, err)
+	result, err := stream.Result()
+	require.NoError(t, err)
 
-	assert.Equal(t, ext, ".pdf")
+	assert.Equal(t, result.Extension(), ".pdf")
 	assert.Equal(t, result.Size(), int64(469704))
 	assert.Equal(t, result.Hash(), "ec32db41dd837bc5a4a7b231aebf38354940af179060608854fb1e7a32205641")
 }
+
+func TestDownload_StreamsHashAndSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, "hello world")
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	stream, err := svc.Download(t.Context(), server.URL+"/report")
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = io.Copy(io.Discard, stream)
+	require.NoError(t, err)
+	result, err := stream.Result()
+	require.NoError(t, err)
+
+	assert.Equal(t, ".pdf", result.Extension())
+	assert.Equal(t, int64(11), result.Size())
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", result.Hash())
+}
+
+func TestDownload_RejectsOversizedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Chunked response, so the limit can only be enforced while streaming
+		w.(http.Flusher).Flush()
+		io.WriteString(w, strings.Repeat("a", 64))
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(16))
+	stream, err := svc.Download(t.Context(), server.URL)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = io.Copy(io.Discard, stream)
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+	assert.True(t, errors.Is(stream.Err(), ErrFileTooLarge))
+
+	_, err = stream.Result()
+	assert.Error(t, err)
+}
+
+func TestDownload_RejectsAnnouncedOversizedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("a", 64))
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(16))
+	_, err := svc.Download(t.Context(), server.URL)
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+}
+
+func TestDownload_ProviderMaxFileSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("a", 64))
+	}))
+	defer server.Close()
+
+	cfg := limits(1024)
+	cfg.ProviderMaxFileSizes = map[string]int64{"tiny": 16}
+	svc := NewDownloadService(server.Client(), cfg)
+
+	_, err := svc.Download(ports.WithProviderSlug(t.Context(), "tiny"), server.URL)
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+
+	stream, err := svc.Download(ports.WithProviderSlug(t.Context(), "other"), server.URL)
+	require.NoError(t, err)
+	stream.Close()
+}
+
+func TestDownload_RefusesSchemesAndRedirects(t *testing.T) {
+	_, err := NewDownloadService(&http.Client{}, limits(1024)).Download(t.Context(), "ftp://example.com/report.pdf")
+	assert.True(t, errors.Is(err, ErrSchemeNotAllowed))
+	assert.Equal(t, download.ErrorCodeClientError, Classify(err))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/next", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return ErrTooManyRedirects }
+	_, err = NewDownloadService(client, limits(1024)).Download(t.Context(), server.URL)
+	assert.True(t, errors.Is(err, ErrTooManyRedirects))
+	assert.Equal(t, download.ErrorCodeClientError, Classify(err))
+}
+
+func TestDownload_BlockedAddressIsPermanent(t *testing.T) {
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(_ context.Context, _, address string) (net.Conn, error) {
+			return nil, fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+		},
+	}}
+
+	_, err := NewDownloadService(client, limits(1024)).Download(t.Context(), "http://169.254.169.254/latest/meta-data")
+	assert.True(t, errors.Is(err, ErrBlockedAddress))
+	assert.Equal(t, download.ErrorCodeBlocked, Classify(err))
+	assert.True(t, download.ErrorCodeBlocked.IsPermanent())
+}
+
+func TestDownload_CrawlPolicy(t *testing.T) {
+	refuse := func(err error) ports.HTTPClient {
+		return clientFunc(func(*http.Request) (*http.Response, error) { return nil, err })
+	}
+
+	_, err := NewDownloadService(refuse(fmt.Errorf("%w: robots.txt", ports.ErrDisallowed)), limits(1024)).
+		Download(t.Context(), "https://example.com/private/report.pdf")
+	assert.True(t, errors.Is(err, ports.ErrDisallowed))
+	assert.Equal(t, download.ErrorCodeDisallowed, Classify(err))
+	assert.True(t, download.ErrorCodeDisallowed.IsPermanent())
+
+	_, err = NewDownloadService(refuse(&ports.CrawlDelayError{RetryAfter: time.Minute}), limits(1024)).
+		Download(t.Context(), "https://example.com/report.pdf")
+	var limited *RateLimitError
+	require.True(t, errors.As(err, &limited))
+	assert.Equal(t, time.Minute, limited.RetryAfter)
+}
+
+func TestDownloadIfModified_ReturnsValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jun 2025 10:00:00 GMT")
+		io.WriteString(w, "report")
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	stream, err := svc.DownloadIfModified(t.Context(), server.URL, downloadresult.Validators{})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.Equal(t, `"v1"`, stream.Validators().ETag)
+	assert.Equal(t, "Mon, 02 Jun 2025 10:00:00 GMT", stream.Validators().LastModified)
+}
+
+func TestDownloadIfModified_NotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, "report")
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	_, err := svc.DownloadIfModified(t.Context(), server.URL, downloadresult.Validators{ETag: `"v1"`})
+	assert.True(t, errors.Is(err, ErrNotModified))
+}
+
+func TestClassify_HTTPStatus(t *testing.T) {
+	cases := map[int]download.ErrorCode{
+		http.StatusNotFound:            download.ErrorCodeClientError,
+		http.StatusForbidden:           download.ErrorCodeClientError,
+		http.StatusTooManyRequests:     download.ErrorCodeRateLimited,
+		http.StatusServiceUnavailable:  download.ErrorCodeServerError,
+		http.StatusInternalServerError: download.ErrorCodeServerError,
+	}
+
+	for status, code := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		svc := NewDownloadService(server.Client(), limits(1024))
+		_, err := svc.Download(t.Context(), server.URL)
+		server.Close()
+
+		require.Error(t, err)
+		assert.Equal(t, code, Classify(err), "status %d", status)
+	}
+}
+
+func TestClassify_TooLargeIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("a", 64))
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(16))
+	_, err := svc.Download(t.Context(), server.URL)
+	assert.Equal(t, download.ErrorCodeTooLarge, Classify(err))
+	assert.True(t, Classify(err).IsPermanent())
+}
+
+func TestClassify_NetworkErrorIsTransient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	_, err := svc.Download(t.Context(), server.URL)
+	assert.Equal(t, download.ErrorCodeNetwork, Classify(err))
+	assert.False(t, Classify(err).IsPermanent())
+}
+
+func TestDownload_RateLimitedHonoursRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	_, err := svc.Download(t.Context(), server.URL)
+
+	var limited *RateLimitError
+	require.True(t, errors.As(err, &limited))
+	assert.Equal(t, 2*time.Minute, limited.RetryAfter)
+	assert.False(t, Classify(err).IsPermanent())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, ParseRetryAfter("30", now))
+	assert.Equal(t, 5*time.Minute, ParseRetryAfter("Mon, 02 Jun 2025 10:05:00 GMT", now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter("Mon, 02 Jun 2025 09:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter("", now))
+}
+
+func TestDownload_SniffsPDF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		io.WriteString(w, "%PDF-1.7\n%report")
+	}))
+	defer server.Close()
+
+	svc := NewDownloadService(server.Client(), limits(1024))
+	stream, err := svc.Download(t.Context(), server.URL+"/report.pdf")
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = io.Copy(io.Discard, stream)
+	require.NoError(t, err)
+	result, err := stream.Result()
+	require.NoError(t, err)
+
+	assert.Equal(t, downloadresult.MIMETypePDF, result.MIMEType())
+	assert.Equal(t, ".pdf", result.Extension())
+	assert.False(t, stream.Mismatch())
+}
+
+func TestDownload_MismatchPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, "<!DOCTYPE html><html><head><title>Findings</title></head><body>report</body></html>")
+	}))
+	defer server.Close()
+
+	corrected, err := NewDownloadService(server.Client(), limits(1024)).
+		WithMismatchPolicy(downloadresult.MismatchCorrect).
+		Download(t.Context(), server.URL+"/report.pdf")
+	require.NoError(t, err)
+	defer corrected.Close()
+	assert.True(t, corrected.Mismatch())
+	assert.Equal(t, ".html", corrected.Extension())
+
+	kept, err := NewDownloadService(server.Client(), limits(1024)).
+		WithMismatchPolicy(downloadresult.MismatchWarn).
+		Download(t.Context(), server.URL+"/report.pdf")
+	require.NoError(t, err)
+	defer kept.Close()
+	assert.True(t, kept.Mismatch())
+	assert.Equal(t, ".pdf", kept.Extension())
+
+	_, err = NewDownloadService(server.Client(), limits(1024)).
+		WithMismatchPolicy(downloadresult.MismatchReject).
+		Download(t.Context(), server.URL+"/report.pdf")
+	assert.True(t, errors.Is(err, downloadresult.ErrContentMismatch))
+	assert.Equal(t, download.ErrorCodeInvalidContent, Classify(err))
+}
+
+func TestDownload_RejectsSoftNotFoundAndLoginPages(t *testing.T) {
+	pages := map[string]error{
+		"<html><head><title>Page Not Found</title></head><body>Oops</body></html>":                   downloadresult.ErrSoftNotFound,
+		"<html><head><title>Sign in</title></head><body><form></form></body></html>":                 downloadresult.ErrLoginWall,
+		"<html><body><form><input name=\"user\"><input type=\"password\" name=\"pw\"></form></body>": downloadresult.ErrLoginWall,
+	}
+
+	for page, expected := range pages {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, page)
+		}))
+
+		_, err := NewDownloadService(server.Client(), limits(1024)).Download(t.Context(), server.URL+"/report.pdf")
+		server.Close()
+
+		assert.True(t, errors.Is(err, expected), page)
+		assert.True(t, Classify(err).IsPermanent(), page)
+	}
+}
@@ -1,27 +1,200 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"downloader/internal/domain/entity/download"
+	"downloader/internal/domain/entity/downloadresult"
 )
 
 var (
-	ErrFileTooLarge = errors.New("file exceeds maximum size")
+	// ErrFileTooLarge is returned up front when Content-Length exceeds the limit,
+	// and by the stream itself when the body turns out to be larger than announced.
+	ErrFileTooLarge = downloadresult.ErrContentTooLarge
+
+	// ErrNotModified is returned when a conditional request answers 304
+	ErrNotModified = errors.New("remote file not modified")
+
+	// ErrArtifactNotFound is returned when a report page links no artifact
+	ErrArtifactNotFound = errors.New("no artifact linked from report page")
+
+	// Source archive errors
+	ErrInvalidRepositoryURL  = errors.New("invalid repository URL")
+	ErrInvalidRepositoryRef  = errors.New("invalid repository ref")
+	ErrUnsupportedRepository = errors.New("unsupported repository host")
+
+	// Errors of URLs the client settings forbid
+	ErrSchemeNotAllowed = errors.New("URL scheme not allowed")
+	ErrTooManyRedirects = errors.New("too many redirects")
+
+	// ErrBlockedAddress is returned by the client when a URL, or one of its
+	// redirects, resolves to a private or internal address
+	ErrBlockedAddress = errors.New("address not allowed")
+
+	// Archive expansion errors, the archive is refused as a whole
+	ErrUnsafeArchivePath = errors.New("archive entry path escapes the archive")
+	ErrArchiveBomb       = errors.New("archive expands beyond the allowed size")
+	ErrTooManyEntries    = errors.New("archive has too many entries")
 )
 
+// Error is a download error tagged with its category, see Classify
+type Error struct {
+	Code download.ErrorCode
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError reports a host asking us to slow down. RetryAfter is the
+// delay it advised, zero when it gave none.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "rate limited by remote host"
+	}
+	return fmt.Sprintf("rate limited by remote host, retry after %s", e.RetryAfter)
+}
+
+func ErrRateLimited(retryAfter time.Duration) error {
+	return &RateLimitError{RetryAfter: retryAfter}
+}
+
 // Error wrapping functions with context
 func ErrRequestCreation(err error) error {
-	return fmt.Errorf("failed to create HTTP request: %w", err)
+	return &Error{
+		Code: download.ErrorCodeClientError,
+		Err:  fmt.Errorf("failed to create HTTP request: %w", err),
+	}
 }
 
 func ErrHTTPRequest(err error) error {
-	return fmt.Errorf("HTTP request failed: %w", err)
+	return &Error{
+		Code: download.ErrorCodeNetwork,
+		Err:  fmt.Errorf("HTTP request failed: %w", err),
+	}
+}
+
+func ErrRedirectRefused(err error) error {
+	return &Error{
+		Code: download.ErrorCodeClientError,
+		Err:  fmt.Errorf("redirect refused: %w", err),
+	}
+}
+
+func ErrAddressBlocked(err error) error {
+	return &Error{
+		Code: download.ErrorCodeBlocked,
+		Err:  fmt.Errorf("request blocked: %w", err),
+	}
+}
+
+func ErrCrawlDisallowed(err error) error {
+	return &Error{
+		Code: download.ErrorCodeDisallowed,
+		Err:  fmt.Errorf("request disallowed: %w", err),
+	}
 }
 
 func ErrUnexpectedStatus(statusCode int) error {
-	return fmt.Errorf("unexpected HTTP status code: %d", statusCode)
+	return &Error{
+		Code: statusErrorCode(statusCode),
+		Err:  fmt.Errorf("unexpected HTTP status code: %d", statusCode),
+	}
 }
 
 func ErrReadResponse(err error) error {
-	return fmt.Errorf("failed to read response: %w", err)
+	return &Error{
+		Code: download.ErrorCodeNetwork,
+		Err:  fmt.Errorf("failed to read response: %w", err),
+	}
+}
+
+func ErrInvalidContent(err error) error {
+	return &Error{
+		Code: download.ErrorCodeInvalidContent,
+		Err:  fmt.Errorf("invalid content: %w", err),
+	}
+}
+
+func ErrOpenArchive(err error) error {
+	return &Error{
+		Code: download.ErrorCodeInvalidContent,
+		Err:  fmt.Errorf("failed to read archive: %w", err),
+	}
+}
+
+func ErrStoreEntry(entryPath string, err error) error {
+	return fmt.Errorf("failed to store archive entry %s: %w", entryPath, err)
+}
+
+func ErrUnknownResolveRule(rule ResolveRule) error {
+	return fmt.Errorf("unknown resolve rule: %s", rule)
+}
+
+// Classify returns the category of a download error. Errors raised outside
+// of this package, such as storage failures, are internal and retryable.
+func Classify(err error) download.ErrorCode {
+	var classified *Error
+	var limited *RateLimitError
+	switch {
+	case errors.As(err, &limited):
+		return download.ErrorCodeRateLimited
+	case errors.As(err, &classified):
+		return classified.Code
+	case errors.Is(err, ErrFileTooLarge):
+		return download.ErrorCodeTooLarge
+	case errors.Is(err, downloadresult.ErrEmptyContent),
+		errors.Is(err, ErrUnsafeArchivePath),
+		errors.Is(err, ErrArchiveBomb),
+		errors.Is(err, ErrTooManyEntries):
+		return download.ErrorCodeInvalidContent
+	case errors.Is(err, context.DeadlineExceeded):
+		return download.ErrorCodeNetwork
+	default:
+		return download.ErrorCodeInternal
+	}
+}
+
+// statusErrorCode tells server side statuses, worth a retry, from the other
+// client errors. 429 is handled apart, see RateLimitError.
+func statusErrorCode(statusCode int) download.ErrorCode {
+	switch {
+	case statusCode >= http.StatusInternalServerError,
+		statusCode == http.StatusRequestTimeout:
+		return download.ErrorCodeServerError
+	case statusCode >= http.StatusBadRequest:
+		return download.ErrorCodeClientError
+	default:
+		// Redirects left unfollowed and other unexpected successes
+		return download.ErrorCodeInvalidContent
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header, given either in seconds or as
+// an HTTP date. It returns zero when the header is missing, invalid or past.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
 }
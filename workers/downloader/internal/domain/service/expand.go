@@ -0,0 +1,319 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"downloader/internal/domain/entity/downloadresult"
+)
+
+// ArchiveFormat is a container the expander can open
+type ArchiveFormat string
+
+const (
+	ArchiveFormatZip   ArchiveFormat = "zip"
+	ArchiveFormatTar   ArchiveFormat = "tar"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+)
+
+// expandedExtensions are the entries worth storing out of an archive
+var expandedExtensions = map[string]bool{
+	".pdf":      true,
+	".md":       true,
+	".markdown": true,
+}
+
+// tarMagic sits at offset 257 of the first header of a POSIX tarball
+var tarMagic = []byte("ustar")
+
+// ArchiveFormatOf returns the format of a downloaded file from its detected
+// MIME type and extension. Office documents are zip files too, only .zip
+// files are expanded.
+func ArchiveFormatOf(mimeType, extension string) (ArchiveFormat, bool) {
+	extension = strings.ToLower(extension)
+	switch {
+	case mimeType == downloadresult.MIMETypeZIP && extension == ".zip":
+		return ArchiveFormatZip, true
+	case mimeType == downloadresult.MIMETypeGzip:
+		return ArchiveFormatTarGz, true
+	case extension == ".tar":
+		return ArchiveFormatTar, true
+	default:
+		return "", false
+	}
+}
+
+// ExpandLimits bound the work an archive can cause, against archive bombs
+type ExpandLimits struct {
+	// MaxEntries counts every entry, stored or not
+	MaxEntries int
+	// MaxEntrySize caps the uncompressed size of one entry
+	MaxEntrySize int64
+	// MaxTotalSize caps the uncompressed size of the whole archive
+	MaxTotalSize int64
+	// MaxRatio caps the compression ratio of a zip entry
+	MaxRatio uint64
+}
+
+func DefaultExpandLimits() ExpandLimits {
+	return ExpandLimits{
+		MaxEntries:   1000,
+		MaxEntrySize: 50 * 1024 * 1024,
+		MaxTotalSize: 200 * 1024 * 1024,
+		MaxRatio:     100,
+	}
+}
+
+// ManifestEntry describes one file stored out of an archive
+type ManifestEntry struct {
+	Path        string `json:"path"` // inside the archive
+	StoragePath string `json:"storage_path"`
+	Size        int64  `json:"size"`
+	Hash        string `json:"hash"`
+}
+
+// Manifest lists the files stored out of an archive
+type Manifest struct {
+	Format  ArchiveFormat   `json:"format"`
+	Entries []ManifestEntry `json:"entries"`
+	// Skipped counts directories, links, duplicates and files of other types
+	Skipped int `json:"skipped"`
+}
+
+// StoreEntryFunc stores an entry read from body and returns its storage path
+type StoreEntryFunc func(entryPath string, body io.Reader) (string, error)
+
+type ArchiveExpander struct {
+	limits ExpandLimits
+}
+
+func NewArchiveExpander(limits ExpandLimits) *ArchiveExpander {
+	return &ArchiveExpander{limits: limits}
+}
+
+// Expand hands the PDF and markdown entries of the archive to store, one at
+// a time, and returns the manifest of what was stored. Paths escaping the
+// archive and archives beyond the limits fail the whole expansion. A gzip
+// file that holds no tarball is not an archive, and yields a nil manifest.
+func (e *ArchiveExpander) Expand(ctx context.Context, format ArchiveFormat, r io.ReaderAt, size int64, store StoreEntryFunc) (*Manifest, error) {
+	expansion := &expansion{
+		limits:   e.limits,
+		store:    store,
+		budget:   e.limits.MaxTotalSize,
+		seen:     make(map[string]bool),
+		manifest: &Manifest{Format: format},
+	}
+
+	switch format {
+	case ArchiveFormatZip:
+		return expansion.manifest, expansion.zip(ctx, r, size)
+	case ArchiveFormatTar:
+		return expansion.manifest, expansion.tar(ctx, io.NewSectionReader(r, 0, size))
+	case ArchiveFormatTarGz:
+		decompressed, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, ErrOpenArchive(err)
+		}
+		defer decompressed.Close()
+
+		// Everything decompressed counts, skipped entries included
+		body := bufio.NewReader(&boundedReader{r: decompressed, remaining: e.limits.MaxTotalSize})
+		head, err := body.Peek(257 + len(tarMagic))
+		if errors.Is(err, ErrArchiveBomb) {
+			return nil, err
+		}
+		if len(head) < 257+len(tarMagic) || !bytes.Equal(head[257:], tarMagic) {
+			return nil, nil
+		}
+		return expansion.manifest, expansion.tar(ctx, body)
+	default:
+		return nil, ErrOpenArchive(fmt.Errorf("unknown format %q", format))
+	}
+}
+
+// expansion is the state of one Expand call
+type expansion struct {
+	limits   ExpandLimits
+	store    StoreEntryFunc
+	budget   int64 // uncompressed bytes left to the archive
+	entries  int
+	seen     map[string]bool
+	manifest *Manifest
+}
+
+func (x *expansion) zip(ctx context.Context, r io.ReaderAt, size int64) error {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return ErrOpenArchive(err)
+	}
+	if len(archive.File) > x.limits.MaxEntries {
+		return ErrTooManyEntries
+	}
+
+	for _, file := range archive.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entryPath, err := safeEntryPath(file.Name)
+		if err != nil {
+			return err
+		}
+		if !file.Mode().IsRegular() || !x.wanted(entryPath) {
+			x.manifest.Skipped++
+			continue
+		}
+
+		// Declared sizes can lie, they only reject the obvious bombs early;
+		// the bounded reader enforces the limits on what is really inflated
+		if file.UncompressedSize64 > uint64(x.limits.MaxEntrySize) {
+			return ErrArchiveBomb
+		}
+		if file.CompressedSize64 > 0 && file.UncompressedSize64/file.CompressedSize64 > x.limits.MaxRatio {
+			return ErrArchiveBomb
+		}
+
+		body, err := file.Open()
+		if err != nil {
+			return ErrOpenArchive(err)
+		}
+		err = x.extract(entryPath, body)
+		body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *expansion) tar(ctx context.Context, r io.Reader) error {
+	archive := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, ErrArchiveBomb) {
+			return err
+		}
+		if err != nil {
+			return ErrOpenArchive(err)
+		}
+
+		x.entries++
+		if x.entries > x.limits.MaxEntries {
+			return ErrTooManyEntries
+		}
+
+		entryPath, err := safeEntryPath(header.Name)
+		if err != nil {
+			return err
+		}
+		// Links are skipped, they could point outside of the archive
+		if header.Typeflag != tar.TypeReg || !x.wanted(entryPath) {
+			x.manifest.Skipped++
+			continue
+		}
+		if header.Size > x.limits.MaxEntrySize {
+			return ErrArchiveBomb
+		}
+
+		if err := x.extract(entryPath, archive); err != nil {
+			return err
+		}
+	}
+}
+
+// wanted checks if an entry is stored, skipping duplicates which would
+// overwrite the first one
+func (x *expansion) wanted(entryPath string) bool {
+	if !expandedExtensions[strings.ToLower(path.Ext(entryPath))] || x.seen[entryPath] {
+		return false
+	}
+	x.seen[entryPath] = true
+	return true
+}
+
+// extract stores one entry, hashing and counting it on the way
+func (x *expansion) extract(entryPath string, body io.Reader) error {
+	bounded := &boundedReader{r: body, remaining: min(x.limits.MaxEntrySize, x.budget)}
+	hasher := sha256.New()
+
+	storagePath, err := x.store(entryPath, io.TeeReader(bounded, hasher))
+	if bounded.err != nil {
+		return bounded.err
+	}
+	if err != nil {
+		return ErrStoreEntry(entryPath, err)
+	}
+
+	size := bounded.read
+	x.budget -= size
+	x.manifest.Entries = append(x.manifest.Entries, ManifestEntry{
+		Path:        entryPath,
+		StoragePath: storagePath,
+		Size:        size,
+		Hash:        hex.EncodeToString(hasher.Sum(nil)),
+	})
+	return nil
+}
+
+// safeEntryPath cleans the name of an entry, and rejects the names that
+// would be stored outside of the archive folder (zip slip)
+func safeEntryPath(name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") || strings.Contains(name, ":") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeArchivePath, name)
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeArchivePath, name)
+	}
+	return cleaned, nil
+}
+
+// boundedReader fails with ErrArchiveBomb once more than remaining bytes
+// are read from r
+type boundedReader struct {
+	r         io.Reader
+	remaining int64
+	read      int64
+	err       error
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.r.Read(p)
+	if errors.Is(err, ErrArchiveBomb) {
+		// An inner bound was hit, e.g. the one on the decompressed tarball
+		b.err = err
+	}
+	b.read += int64(n)
+	if b.read > b.remaining {
+		b.err = ErrArchiveBomb
+		return 0, b.err
+	}
+	return n, err
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zipArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+// memoryStore collects the stored entries
+func memoryStore(stored map[string]string) StoreEntryFunc {
+	return func(entryPath string, body io.Reader) (string, error) {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		stored[entryPath] = string(content)
+		return "reports/" + entryPath, nil
+	}
+}
+
+func TestExpand_StoresReportsWithManifest(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"report.pdf":          "%PDF-1.7",
+		"findings/README.md":  "# Findings",
+		"contracts/Vault.sol": "contract Vault {}",
+	})
+
+	stored := map[string]string{}
+	manifest, err := NewArchiveExpander(DefaultExpandLimits()).
+		Expand(context.Background(), ArchiveFormatZip, archive, archive.Size(), memoryStore(stored))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"report.pdf": "%PDF-1.7", "findings/README.md": "# Findings"}, stored)
+	assert.Len(t, manifest.Entries, 2)
+	assert.Equal(t, 1, manifest.Skipped)
+	for _, entry := range manifest.Entries {
+		assert.Equal(t, "reports/"+entry.Path, entry.StoragePath)
+		assert.Equal(t, int64(len(stored[entry.Path])), entry.Size)
+		assert.Len(t, entry.Hash, 64)
+	}
+}
+
+func TestExpand_TarGz(t *testing.T) {
+	archive := tarGzArchive(t, map[string]string{"audit/report.md": "# Report"})
+
+	stored := map[string]string{}
+	manifest, err := NewArchiveExpander(DefaultExpandLimits()).
+		Expand(context.Background(), ArchiveFormatTarGz, archive, archive.Size(), memoryStore(stored))
+	require.NoError(t, err)
+	require.Len(t, manifest.Entries, 1)
+	assert.Equal(t, "audit/report.md", manifest.Entries[0].Path)
+}
+
+func TestExpand_PlainGzipIsNoArchive(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte("%PDF-1.7 not a tarball"))
+	require.NoError(t, gz.Close())
+
+	manifest, err := NewArchiveExpander(DefaultExpandLimits()).
+		Expand(context.Background(), ArchiveFormatTarGz, bytes.NewReader(buf.Bytes()), int64(buf.Len()), memoryStore(map[string]string{}))
+	require.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestExpand_RejectsZipSlip(t *testing.T) {
+	for _, name := range []string{"../../etc/report.pdf", "/abs/report.pdf", `..\windows\report.pdf`, "C:/report.pdf"} {
+		archive := zipArchive(t, map[string]string{name: "%PDF-1.7"})
+
+		_, err := NewArchiveExpander(DefaultExpandLimits()).
+			Expand(context.Background(), ArchiveFormatZip, archive, archive.Size(), memoryStore(map[string]string{}))
+		assert.True(t, errors.Is(err, ErrUnsafeArchivePath), name)
+	}
+}
+
+func TestExpand_Limits(t *testing.T) {
+	limits := DefaultExpandLimits()
+	limits.MaxEntries = 2
+
+	archive := zipArchive(t, map[string]string{"a.pdf": "a", "b.pdf": "b", "c.pdf": "c"})
+	_, err := NewArchiveExpander(limits).
+		Expand(context.Background(), ArchiveFormatZip, archive, archive.Size(), memoryStore(map[string]string{}))
+	assert.True(t, errors.Is(err, ErrTooManyEntries))
+
+	// Highly compressible content trips the ratio check
+	archive = zipArchive(t, map[string]string{"bomb.pdf": strings.Repeat("0", 1<<20)})
+	_, err = NewArchiveExpander(DefaultExpandLimits()).
+		Expand(context.Background(), ArchiveFormatZip, archive, archive.Size(), memoryStore(map[string]string{}))
+	assert.True(t, errors.Is(err, ErrArchiveBomb))
+
+	// Sizes are enforced on the inflated bytes, whatever the headers declare
+	limits = DefaultExpandLimits()
+	limits.MaxTotalSize = 1000
+	archive = tarGzArchive(t, map[string]string{"report.md": strings.Repeat("#", 4096)})
+	_, err = NewArchiveExpander(limits).
+		Expand(context.Background(), ArchiveFormatTarGz, archive, archive.Size(), memoryStore(map[string]string{}))
+	assert.True(t, errors.Is(err, ErrArchiveBomb))
+	assert.Equal(t, "invalid_content", string(Classify(err)))
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ResolveRule names how the artifact behind a provider's report page is found
+type ResolveRule string
+
+const (
+	// ResolvePDFLink takes the first PDF linked from the page
+	ResolvePDFLink ResolveRule = "pdf_link"
+	// ResolveGitHubMarkdown takes the report markdown of the findings
+	// repository linked from the page
+	ResolveGitHubMarkdown ResolveRule = "github_markdown"
+)
+
+// findingsReportFile is the markdown report at the root of a findings repository
+const findingsReportFile = "report.md"
+
+var (
+	pdfLinkPattern      = regexp.MustCompile(`(?i)href\s*=\s*["']([^"'#]+?\.pdf(?:\?[^"'#]*)?)["']`)
+	findingsRepoPattern = regexp.MustCompile(`https?://github\.com/([\w.-]+)/([\w.-]+-findings)\b`)
+)
+
+// ArtifactResolver finds the canonical artifact of a report page, for the
+// providers whose download URL points at an HTML page rather than the file
+type ArtifactResolver struct {
+	rules map[string]ResolveRule
+}
+
+func NewArtifactResolver(rules map[string]ResolveRule) *ArtifactResolver {
+	return &ArtifactResolver{rules: rules}
+}
+
+// Rule returns the resolve rule of a provider, false when its download URLs
+// are used as they are
+func (r *ArtifactResolver) Rule(providerSlug string) (ResolveRule, bool) {
+	rule, ok := r.rules[providerSlug]
+	return rule, ok
+}
+
+// Resolve returns the URL of the artifact the page fetched from pageURL links
+// to, or ErrArtifactNotFound
+func (r *ArtifactResolver) Resolve(rule ResolveRule, pageURL string, page []byte) (string, error) {
+	switch rule {
+	case ResolvePDFLink:
+		return resolvePDFLink(pageURL, page)
+	case ResolveGitHubMarkdown:
+		return resolveGitHubMarkdown(page)
+	default:
+		return "", ErrUnknownResolveRule(rule)
+	}
+}
+
+func resolvePDFLink(pageURL string, page []byte) (string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", ErrRequestCreation(err)
+	}
+
+	for _, match := range pdfLinkPattern.FindAllSubmatch(page, -1) {
+		link, err := url.Parse(strings.TrimSpace(string(match[1])))
+		if err != nil {
+			continue
+		}
+		resolved := base.ResolveReference(link)
+		if resolved.Scheme == "http" || resolved.Scheme == "https" {
+			return resolved.String(), nil
+		}
+	}
+	return "", ErrArtifactNotFound
+}
+
+func resolveGitHubMarkdown(page []byte) (string, error) {
+	match := findingsRepoPattern.FindSubmatch(page)
+	if match == nil {
+		return "", ErrArtifactNotFound
+	}
+
+	owner := string(match[1])
+	repository := strings.TrimSuffix(string(match[2]), ".git")
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/HEAD/%s", owner, repository, findingsReportFile), nil
+}
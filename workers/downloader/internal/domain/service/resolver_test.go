@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve_PDFLink(t *testing.T) {
+	resolver := NewArtifactResolver(map[string]ResolveRule{"sherlock": ResolvePDFLink})
+	page := []byte(`<html><body>
+		<a href="/audits/contest.html">Contest</a>
+		<a class="download" href="/reports/2025-06-vault.pdf?dl=1">Download report</a>
+	</body></html>`)
+
+	rule, ok := resolver.Rule("sherlock")
+	require.True(t, ok)
+
+	artifactURL, err := resolver.Resolve(rule, "https://audits.example.com/contests/42", page)
+	require.NoError(t, err)
+	assert.Equal(t, "https://audits.example.com/reports/2025-06-vault.pdf?dl=1", artifactURL)
+}
+
+func TestResolve_GitHubMarkdown(t *testing.T) {
+	resolver := NewArtifactResolver(map[string]ResolveRule{"code4rena": ResolveGitHubMarkdown})
+	page := []byte(`<a href="https://github.com/code-423n4/2025-06-panoptic-hypovault">Code</a>
+		<a href="https://github.com/code-423n4/2025-06-panoptic-hypovault-findings/issues">Findings</a>`)
+
+	artifactURL, err := resolver.Resolve(ResolveGitHubMarkdown, "https://code4rena.com/reports/2025-06-panoptic-hypovault", page)
+	require.NoError(t, err)
+	assert.Equal(t, "https://raw.githubusercontent.com/code-423n4/2025-06-panoptic-hypovault-findings/HEAD/report.md", artifactURL)
+}
+
+func TestResolve_NothingLinked(t *testing.T) {
+	resolver := NewArtifactResolver(nil)
+
+	_, ok := resolver.Rule("cantina")
+	assert.False(t, ok)
+
+	_, err := resolver.Resolve(ResolvePDFLink, "https://example.com/report", []byte(`<a href="mailto:x@example.com">Contact</a>`))
+	assert.True(t, errors.Is(err, ErrArtifactNotFound))
+}
@@ -0,0 +1,77 @@
+// Package httpclient builds the HTTP client of the downloader from its
+// configuration: timeouts of each phase of a request, proxy, and the
+// redirects it may follow.
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/service"
+	"shared/infrastructure/config"
+)
+
+// New returns a client whose redirects stay within the allowed schemes and
+// the redirect budget, see service.ErrSchemeNotAllowed and service.ErrTooManyRedirects,
+// and pass the crawl policy check of their request, see ports.CheckRedirect.
+// Unless private networks are allowed, it refuses to connect to internal
+// addresses, see service.ErrBlockedAddress.
+func New(cfg config.DownloaderConfig) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	proxies := environmentProxies()
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+		proxies = []*url.URL{proxyURL}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.DialContext = dialer.DialContext
+	var g *guard
+	if !cfg.AllowPrivateNetworks {
+		g = newGuard(dialer, proxy, proxies)
+		transport.Proxy = g.Proxy
+		transport.DialContext = g.DialContext
+	}
+	transport.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       cfg.Timeout,
+		CheckRedirect: checkRedirect(cfg, g),
+	}, nil
+}
+
+// checkRedirect vets each redirect before it is followed; g is nil when
+// private networks are allowed
+func checkRedirect(cfg config.DownloaderConfig, g *guard) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > cfg.MaxRedirects {
+			return fmt.Errorf("%w: more than %d", service.ErrTooManyRedirects, cfg.MaxRedirects)
+		}
+		if !slices.Contains(cfg.AllowedSchemes, req.URL.Scheme) {
+			return fmt.Errorf("%w: redirect to %s", service.ErrSchemeNotAllowed, req.URL.Scheme)
+		}
+		if g != nil {
+			if _, err := g.Proxy(req); err != nil {
+				return err
+			}
+		}
+		return ports.CheckRedirect(req, via)
+	}
+}
@@ -0,0 +1,188 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"downloader/internal/domain/service"
+	"shared/infrastructure/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() config.DownloaderConfig {
+	return config.DownloaderConfig{
+		Timeout:               5 * time.Second,
+		ConnectTimeout:        time.Second,
+		TLSHandshakeTimeout:   time.Second,
+		ResponseHeaderTimeout: time.Second,
+		MaxRedirects:          5,
+		AllowedSchemes:        []string{"http", "https"},
+	}
+}
+
+// newTarget serves a report on loopback, an address downloads must not reach
+func newTarget(t *testing.T) *httptest.Server {
+	t.Helper()
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "internal")
+	}))
+	t.Cleanup(target.Close)
+	return target
+}
+
+func TestNew_RefusesLoopback(t *testing.T) {
+	target := newTarget(t)
+
+	client, err := New(testConfig())
+	require.NoError(t, err)
+
+	_, err = client.Get(target.URL)
+	assert.ErrorIs(t, err, service.ErrBlockedAddress)
+}
+
+func TestNew_AllowPrivateNetworks(t *testing.T) {
+	target := newTarget(t)
+
+	cfg := testConfig()
+	cfg.AllowPrivateNetworks = true
+	client, err := New(cfg)
+	require.NoError(t, err)
+
+	resp, err := client.Get(target.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// newProxy serves as the configured proxy, redirecting public.example to
+// location and counting the requests it forwards
+func newProxy(t *testing.T, location string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		if r.URL.Host != "public.example" {
+			http.Error(w, "unexpected host", http.StatusBadGateway)
+			return
+		}
+		if location == "" {
+			fmt.Fprint(w, "report")
+			return
+		}
+		http.Redirect(w, r, location, http.StatusFound)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, &proxied
+}
+
+func TestNew_Proxied(t *testing.T) {
+	withHosts(t, map[string]string{"public.example": "93.184.215.14"})
+	proxy, proxied := newProxy(t, "")
+
+	cfg := testConfig()
+	cfg.ProxyURL = proxy.URL
+	client, err := New(cfg)
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://public.example/report")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 1, proxied.Load(), "the configured proxy is trusted")
+}
+
+func TestNew_RefusesProxiedLinkLocal(t *testing.T) {
+	withHosts(t, map[string]string{"metadata.example": "169.254.169.254"})
+	proxy, proxied := newProxy(t, "")
+
+	cfg := testConfig()
+	cfg.ProxyURL = proxy.URL
+	client, err := New(cfg)
+	require.NoError(t, err)
+
+	for _, target := range []string{"http://169.254.169.254/latest/meta-data", "http://metadata.example/latest/meta-data"} {
+		_, err = client.Get(target)
+		assert.ErrorIs(t, err, service.ErrBlockedAddress, target)
+	}
+	assert.Zero(t, proxied.Load(), "nothing reached the proxy")
+}
+
+func TestNew_RefusesProxyAsTarget(t *testing.T) {
+	proxy, proxied := newProxy(t, "")
+
+	cfg := testConfig()
+	cfg.ProxyURL = proxy.URL
+	client, err := New(cfg)
+	require.NoError(t, err)
+
+	_, err = client.Get(proxy.URL + "/admin")
+	assert.ErrorIs(t, err, service.ErrBlockedAddress)
+	assert.Zero(t, proxied.Load())
+}
+
+// TestNew_RefusesLoopbackAfterRedirect reaches a public host through the
+// configured proxy and refuses its redirect to loopback
+func TestNew_RefusesLoopbackAfterRedirect(t *testing.T) {
+	withHosts(t, map[string]string{"public.example": "93.184.215.14"})
+	target := newTarget(t)
+	proxy, proxied := newProxy(t, target.URL)
+
+	cfg := testConfig()
+	cfg.ProxyURL = proxy.URL
+	client, err := New(cfg)
+	require.NoError(t, err)
+
+	_, err = client.Get("http://public.example/report")
+	assert.ErrorIs(t, err, service.ErrBlockedAddress)
+	assert.EqualValues(t, 1, proxied.Load(), "the redirect is not followed")
+}
+
+func TestNew_TrustsOnlyConfiguredProxy(t *testing.T) {
+	target := newTarget(t)
+
+	client, err := New(testConfig())
+	require.NoError(t, err)
+
+	// A proxy picked at request time is dialed like any other host
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	client.Transport.(*http.Transport).Proxy = http.ProxyURL(targetURL)
+
+	_, err = client.Get("http://public.example/report")
+	assert.ErrorIs(t, err, service.ErrBlockedAddress)
+}
+
+func TestNew_Redirects(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		err      error
+	}{
+		{name: "scheme not allowed", location: "ftp://public.example/report", err: service.ErrSchemeNotAllowed},
+		{name: "too many redirects", location: "/loop", err: service.ErrTooManyRedirects},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, tt.location, http.StatusFound)
+			}))
+			t.Cleanup(server.Close)
+
+			cfg := testConfig()
+			cfg.AllowPrivateNetworks = true
+			client, err := New(cfg)
+			require.NoError(t, err)
+
+			_, err = client.Get(server.URL + "/start")
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"syscall"
+
+	"downloader/internal/domain/service"
+)
+
+// blockedPrefixes are the ranges not covered by the netip.Addr predicates
+// that a download must not reach
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast included
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, may embed any IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4, may embed any IPv4
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // IPv4-translated
+}
+
+// lookupNetIP resolves the hosts of proxied requests
+var lookupNetIP = net.DefaultResolver.LookupNetIP
+
+// guard dials the remote hosts of downloads, refusing private, loopback and
+// link-local addresses. The address is checked once resolved, right before
+// connecting, so neither DNS rebinding nor redirects can get around it.
+// The proxies of the configuration are dialed unchecked: they are known when
+// the client is built, not picked from a scraped URL. The targets sent
+// through them are resolved and checked by Proxy instead, as are those sent
+// straight to a proxy address.
+type guard struct {
+	guarded *net.Dialer
+	plain   *net.Dialer
+	proxy   func(*http.Request) (*url.URL, error)
+	proxies map[string]bool // by address, read-only once built
+}
+
+func newGuard(dialer *net.Dialer, proxy func(*http.Request) (*url.URL, error), proxies []*url.URL) *guard {
+	guarded := *dialer
+	guarded.Control = checkAddress
+
+	trusted := make(map[string]bool, len(proxies))
+	for _, proxyURL := range proxies {
+		trusted[urlAddress(proxyURL)] = true
+	}
+	return &guard{
+		guarded: &guarded,
+		plain:   dialer,
+		proxy:   proxy,
+		proxies: trusted,
+	}
+}
+
+// DialContext connects to address, through the guarded dialer unless it
+// is a proxy
+func (g *guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if g.proxies[address] {
+		return g.plain.DialContext(ctx, network, address)
+	}
+	return g.guarded.DialContext(ctx, network, address)
+}
+
+// Proxy picks the proxy of req, refusing targets the dialer does not check:
+// those sent through a proxy, which connects to them itself, and those whose
+// address is a proxy address
+func (g *guard) Proxy(req *http.Request) (*url.URL, error) {
+	proxyURL, err := g.proxy(req)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL != nil || g.proxies[urlAddress(req.URL)] {
+		if err := checkHost(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return proxyURL, nil
+}
+
+// environmentProxies returns the proxies http.ProxyFromEnvironment may pick,
+// parsed the way it does
+func environmentProxies() []*url.URL {
+	var proxies []*url.URL
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(value)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5") {
+			if proxyURL, err = url.Parse("http://" + value); err != nil {
+				continue
+			}
+		}
+		proxies = append(proxies, proxyURL)
+	}
+	return proxies
+}
+
+// urlAddress is the host and port u connects to, the default port of its
+// scheme when it has none
+func urlAddress(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkHost resolves host and refuses it when any of its addresses is
+// blocked. A lookup failure is returned as is, it may be temporary.
+func checkHost(ctx context.Context, host string) error {
+	addrs, err := lookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if isBlocked(addr) {
+			return fmt.Errorf("%w: %s", service.ErrBlockedAddress, addr)
+		}
+	}
+	return nil
+}
+
+// checkAddress runs once the address is resolved, before each connection
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", service.ErrBlockedAddress, address)
+	}
+
+	addr := addrPort.Addr()
+	if isBlocked(addr) {
+		return fmt.Errorf("%w: %s", service.ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// isBlocked tells if addr must not be reached; IPv4-mapped IPv6 addresses
+// are checked as the IPv4 address they map
+func isBlocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
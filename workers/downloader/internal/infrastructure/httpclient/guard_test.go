@@ -0,0 +1,181 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"downloader/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBlocked(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"169.254.169.254", true}, // cloud metadata, link-local
+		{"127.0.0.1", true},
+		{"127.8.8.8", true},
+		{"::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"10.0.0.1", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"fc00::1", true},
+		{"fd12:3456::1", true},
+		{"fe80::1", true},
+		{"64:ff9b::a00:1", true}, // NAT64 of 10.0.0.1
+		{"2002:a00:1::", true},   // 6to4 of 10.0.0.1
+		{"100.64.0.1", true},
+		{"198.18.0.1", true},
+		{"224.0.0.1", true},
+		{"255.255.255.255", true},
+		{"93.184.215.14", false},
+		{"::ffff:93.184.215.14", false},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, isBlocked(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	assert.ErrorIs(t, checkAddress("tcp4", "127.0.0.1:80", nil), service.ErrBlockedAddress)
+	assert.ErrorIs(t, checkAddress("tcp6", "[::ffff:169.254.169.254]:80", nil), service.ErrBlockedAddress)
+	assert.ErrorIs(t, checkAddress("tcp", "not-an-address", nil), service.ErrBlockedAddress)
+	assert.NoError(t, checkAddress("tcp4", "93.184.215.14:443", nil))
+}
+
+func TestEnvironmentProxies(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HTTP_PROXY", "proxy.internal:3128")
+	t.Setenv("https_proxy", "https://secure-proxy.internal")
+
+	var addresses []string
+	for _, proxyURL := range environmentProxies() {
+		addresses = append(addresses, urlAddress(proxyURL))
+	}
+	assert.Equal(t, []string{"proxy.internal:3128", "secure-proxy.internal:443"}, addresses)
+}
+
+func TestURLAddress(t *testing.T) {
+	tests := []struct {
+		proxy   string
+		address string
+	}{
+		{"http://proxy.internal", "proxy.internal:80"},
+		{"https://proxy.internal", "proxy.internal:443"},
+		{"socks5://proxy.internal", "proxy.internal:1080"},
+		{"http://proxy.internal:3128", "proxy.internal:3128"},
+		{"http://[fd00::1]:3128", "[fd00::1]:3128"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.proxy, func(t *testing.T) {
+			proxyURL, err := url.Parse(tt.proxy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, urlAddress(proxyURL))
+		})
+	}
+}
+
+// withHosts resolves the hosts of proxied requests from hosts for the test
+func withHosts(t *testing.T, hosts map[string]string) {
+	t.Helper()
+	lookup := lookupNetIP
+	t.Cleanup(func() { lookupNetIP = lookup })
+	lookupNetIP = func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		if addr, err := netip.ParseAddr(host); err == nil {
+			return []netip.Addr{addr}, nil
+		}
+		if addr, ok := hosts[host]; ok {
+			return []netip.Addr{netip.MustParseAddr(addr)}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+}
+
+func TestGuard_Proxy(t *testing.T) {
+	withHosts(t, map[string]string{
+		"public.example":   "93.184.215.14",
+		"metadata.example": "169.254.169.254",
+	})
+	proxyURL, err := url.Parse("http://proxy.internal:3128")
+	require.NoError(t, err)
+	failed := errors.New("no proxy for you")
+
+	tests := []struct {
+		name    string
+		target  string
+		proxy   func(*http.Request) (*url.URL, error)
+		proxied bool
+		err     error
+	}{
+		{name: "proxied public host", target: "http://public.example/report", proxy: http.ProxyURL(proxyURL), proxied: true},
+		{name: "proxied link-local address", target: "http://169.254.169.254/latest/meta-data", proxy: http.ProxyURL(proxyURL), err: service.ErrBlockedAddress},
+		{name: "proxied host resolving to link-local", target: "http://metadata.example/", proxy: http.ProxyURL(proxyURL), err: service.ErrBlockedAddress},
+		{name: "proxied loopback", target: "https://[::1]/", proxy: http.ProxyURL(proxyURL), err: service.ErrBlockedAddress},
+		{name: "proxied host not resolving", target: "http://unknown.example/", proxy: http.ProxyURL(proxyURL), err: &net.DNSError{}},
+		{name: "direct host left to the dialer", target: "http://10.0.0.1/", proxy: http.ProxyURL(nil)},
+		{name: "direct to the proxy address", target: "http://proxy.internal:3128/admin", proxy: http.ProxyURL(nil), err: &net.DNSError{}},
+		{name: "proxy selection failing", target: "http://public.example/", proxy: func(*http.Request) (*url.URL, error) { return nil, failed }, err: failed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGuard(&net.Dialer{}, tt.proxy, []*url.URL{proxyURL})
+			req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+			require.NoError(t, err)
+
+			picked, err := g.Proxy(req)
+			switch want := tt.err.(type) {
+			case nil:
+				require.NoError(t, err)
+			case *net.DNSError:
+				assert.ErrorAs(t, err, &want)
+				assert.NotErrorIs(t, err, service.ErrBlockedAddress, "lookup failures may be temporary")
+			default:
+				assert.ErrorIs(t, err, want)
+			}
+			if tt.proxied {
+				assert.Equal(t, proxyURL, picked)
+			} else {
+				assert.Nil(t, picked)
+			}
+		})
+	}
+}
+
+func TestGuard_ProxyAddressResolvingToLoopback(t *testing.T) {
+	withHosts(t, map[string]string{"proxy.internal": "127.0.0.1"})
+	proxyURL, err := url.Parse("http://proxy.internal:3128")
+	require.NoError(t, err)
+	g := newGuard(&net.Dialer{}, http.ProxyURL(nil), []*url.URL{proxyURL})
+
+	// The dialer trusts the proxy address, the target check does not
+	req, err := http.NewRequest(http.MethodGet, "http://proxy.internal:3128/admin", nil)
+	require.NoError(t, err)
+	_, err = g.Proxy(req)
+	assert.ErrorIs(t, err, service.ErrBlockedAddress)
+
+	// Another port of the host is dialed, and checked, as any host
+	req, err = http.NewRequest(http.MethodGet, "http://proxy.internal:8080/", nil)
+	require.NoError(t, err)
+	_, err = g.Proxy(req)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/service"
+	"shared/infrastructure/config"
+
+	"golang.org/x/time/rate"
+)
+
+// Client is a ports.HTTPClient throttling requests with a token bucket per
+// host. A host answering 429 gets no request until its Retry-After is over.
+type Client struct {
+	next   ports.HTTPClient
+	config config.RateLimitConfig
+
+	mu          sync.Mutex
+	limiters    map[string]*rate.Limiter
+	pausedUntil map[string]time.Time
+}
+
+func NewClient(next ports.HTTPClient, cfg config.RateLimitConfig) *Client {
+	return &Client{
+		next:        next,
+		config:      cfg,
+		limiters:    make(map[string]*rate.Limiter),
+		pausedUntil: make(map[string]time.Time),
+	}
+}
+
+// Do waits for a request slot of the host, up to MaxWait, then sends req.
+// It returns a service.RateLimitError instead of waiting any longer.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if err := c.wait(req.Context(), host); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.pause(host, service.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	}
+	return resp, nil
+}
+
+func (c *Client) wait(ctx context.Context, host string) error {
+	c.mu.Lock()
+	if until, ok := c.pausedUntil[host]; ok {
+		if remaining := time.Until(until); remaining > 0 {
+			c.mu.Unlock()
+			return service.ErrRateLimited(remaining)
+		}
+		delete(c.pausedUntil, host)
+	}
+	limiter := c.limiter(host, ports.ProviderSlug(ctx))
+	c.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if delay > c.config.MaxWait {
+		reservation.Cancel()
+		return service.ErrRateLimited(delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+// limiter returns the bucket of host, created with the rate of the provider
+// that first requested it. Must be called with mu held.
+func (c *Client) limiter(host, providerSlug string) *rate.Limiter {
+	if limiter, ok := c.limiters[host]; ok {
+		return limiter
+	}
+
+	limit, ok := c.config.Providers[providerSlug]
+	if !ok {
+		limit = c.config.Default
+	}
+	limiter := rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)
+	c.limiters[host] = limiter
+	return limiter
+}
+
+// pause stops requests to host for delay; without a delay the bucket alone
+// slows them down
+func (c *Client) pause(host string, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pausedUntil[host] = time.Now().Add(delay)
+}
@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"downloader/internal/application/ports"
+	"downloader/internal/domain/service"
+	"shared/infrastructure/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient answers every request with status and headers, counting them
+// by host
+type fakeClient struct {
+	status  map[string]int
+	headers map[string]http.Header
+	sent    map[string]int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		status:  make(map[string]int),
+		headers: make(map[string]http.Header),
+		sent:    make(map[string]int),
+	}
+}
+
+func (f *fakeClient) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	f.sent[host]++
+
+	status, ok := f.status[host]
+	if !ok {
+		status = http.StatusOK
+	}
+	header, ok := f.headers[host]
+	if !ok {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func get(t *testing.T, client *Client, ctx context.Context, url string) error {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// slowConfig allows one request per 10 seconds and a burst of one
+func slowConfig(maxWait time.Duration) config.RateLimitConfig {
+	return config.RateLimitConfig{
+		Default: config.RateLimit{RequestsPerSecond: 0.1, Burst: 1},
+		MaxWait: maxWait,
+	}
+}
+
+func TestClient_RateLimitedPastMaxWait(t *testing.T) {
+	next := newFakeClient()
+	client := NewClient(next, slowConfig(time.Second))
+	ctx := context.Background()
+
+	require.NoError(t, get(t, client, ctx, "https://audits.example.com/1"))
+
+	err := get(t, client, ctx, "https://audits.example.com/2")
+	var rateLimited *service.RateLimitError
+	require.ErrorAs(t, err, &rateLimited)
+	assert.Greater(t, rateLimited.RetryAfter, time.Second)
+	assert.LessOrEqual(t, rateLimited.RetryAfter, 10*time.Second)
+	assert.Equal(t, 1, next.sent["audits.example.com"])
+
+	// The refused request gave its slot back: the next one waits as long
+	err = get(t, client, ctx, "https://audits.example.com/3")
+	require.ErrorAs(t, err, &rateLimited)
+	assert.LessOrEqual(t, rateLimited.RetryAfter, 10*time.Second)
+
+	// Other hosts have their own bucket
+	require.NoError(t, get(t, client, ctx, "https://other.example.com/1"))
+}
+
+func TestClient_WaitsWithinMaxWait(t *testing.T) {
+	next := newFakeClient()
+	client := NewClient(next, config.RateLimitConfig{
+		Default: config.RateLimit{RequestsPerSecond: 20, Burst: 1},
+		MaxWait: time.Second,
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, get(t, client, ctx, "https://audits.example.com/report"))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, 3, next.sent["audits.example.com"])
+}
+
+func TestClient_CancelledWhileWaiting(t *testing.T) {
+	next := newFakeClient()
+	client := NewClient(next, slowConfig(time.Minute))
+
+	require.NoError(t, get(t, client, context.Background(), "https://audits.example.com/1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, get(t, client, ctx, "https://audits.example.com/2"), context.DeadlineExceeded)
+	assert.Equal(t, 1, next.sent["audits.example.com"])
+}
+
+func TestClient_RetryAfterPausesHost(t *testing.T) {
+	next := newFakeClient()
+	next.status["busy.example.com"] = http.StatusTooManyRequests
+	next.headers["busy.example.com"] = http.Header{"Retry-After": []string{"120"}}
+	client := NewClient(next, config.RateLimitConfig{
+		Default: config.RateLimit{RequestsPerSecond: 100, Burst: 10},
+		MaxWait: time.Second,
+	})
+	ctx := context.Background()
+
+	// The 429 itself is handed over
+	require.NoError(t, get(t, client, ctx, "https://busy.example.com/1"))
+
+	err := get(t, client, ctx, "https://busy.example.com/2")
+	var rateLimited *service.RateLimitError
+	require.ErrorAs(t, err, &rateLimited)
+	assert.Greater(t, rateLimited.RetryAfter, 119*time.Second)
+	assert.LessOrEqual(t, rateLimited.RetryAfter, 120*time.Second)
+	assert.Equal(t, 1, next.sent["busy.example.com"])
+
+	// Only that host is paused
+	require.NoError(t, get(t, client, ctx, "https://other.example.com/1"))
+	assert.Equal(t, 1, next.sent["other.example.com"])
+}
+
+func TestClient_PauseEnds(t *testing.T) {
+	next := newFakeClient()
+	client := NewClient(next, slowConfig(time.Second))
+	client.pause("audits.example.com", 10*time.Millisecond)
+	ctx := context.Background()
+
+	var rateLimited *service.RateLimitError
+	require.ErrorAs(t, get(t, client, ctx, "https://audits.example.com/1"), &rateLimited)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, get(t, client, ctx, "https://audits.example.com/1"))
+	assert.NotContains(t, client.pausedUntil, "audits.example.com")
+}
+
+func TestClient_TooManyRequestsWithoutRetryAfter(t *testing.T) {
+	next := newFakeClient()
+	next.status["busy.example.com"] = http.StatusTooManyRequests
+	client := NewClient(next, config.RateLimitConfig{
+		Default: config.RateLimit{RequestsPerSecond: 100, Burst: 10},
+		MaxWait: time.Second,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, get(t, client, ctx, "https://busy.example.com/1"))
+	require.NoError(t, get(t, client, ctx, "https://busy.example.com/2"), "the bucket alone slows the host down")
+	assert.Empty(t, client.pausedUntil)
+}
+
+func TestClient_ProviderRate(t *testing.T) {
+	next := newFakeClient()
+	cfg := slowConfig(time.Second)
+	cfg.Providers = map[string]config.RateLimit{
+		"fast": {RequestsPerSecond: 100, Burst: 5},
+	}
+	client := NewClient(next, cfg)
+
+	fast := ports.WithProviderSlug(context.Background(), "fast")
+	for range 5 {
+		require.NoError(t, get(t, client, fast, "https://fast.example.com/report"))
+	}
+	assert.Equal(t, 5, next.sent["fast.example.com"])
+
+	// Providers without their own rate get the default one
+	other := ports.WithProviderSlug(context.Background(), "other")
+	require.NoError(t, get(t, client, other, "https://slow.example.com/1"))
+	var rateLimited *service.RateLimitError
+	assert.ErrorAs(t, get(t, client, other, "https://slow.example.com/2"), &rateLimited)
+
+	// The bucket of a host keeps the rate it was created with
+	require.ErrorAs(t, get(t, client, fast, "https://slow.example.com/3"), &rateLimited)
+}
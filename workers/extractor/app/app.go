@@ -0,0 +1,89 @@
+// Package app assembles the handler of the extractor, for its own binary and
+// for the local binary running several workers in one process.
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/application/handler"
+	"extractor/internal/application/ports"
+	"extractor/internal/application/usecase"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/domain/service"
+	"extractor/internal/infrastructure/scrapers/cantina"
+	"extractor/internal/infrastructure/scrapers/code4rena"
+	"extractor/internal/infrastructure/scrapers/codehawks"
+	"extractor/internal/infrastructure/scrapers/sherlock"
+	"shared/infrastructure/config"
+	"shared/infrastructure/crawlpolicy"
+	"shared/infrastructure/outbox"
+)
+
+// EventTypes are the types of the requests the extractor handles, one per
+// extraction stage
+var EventTypes = []string{
+	dto.ExtractStageRoot,
+	dto.ExtractStageIntermediate,
+	dto.ExtractStageLeaf,
+}
+
+// Dependencies holds the infrastructure the extractor is built on
+type Dependencies struct {
+	Database     ports.Database
+	Repositories ports.Repositories
+	Queue        ports.Queue
+}
+
+// NewHandler builds the extractor handler, with its HTTP client within the
+// crawl policy of each site
+func NewHandler(cfg *config.Config, deps Dependencies, obs ports.Observability) (ports.Handler, error) {
+	httpClient, err := crawlpolicy.NewClient(
+		&http.Client{Timeout: 30 * time.Second, CheckRedirect: ports.CheckRedirect},
+		deps.Repositories.AuditProvider(),
+		cfg.CrawlPolicy,
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("crawl policy client creation: %w", err)
+	}
+
+	fetchService := service.NewFetchService(
+		httpClient,
+		10*1024*1024, // index and report pages are small
+	)
+
+	relay, err := outbox.NewRelay(deps.Repositories.Outbox(), deps.Queue, obs)
+	if err != nil {
+		return nil, fmt.Errorf("outbox relay creation: %w", err)
+	}
+
+	extractReports, err := usecase.NewExtractReports(
+		fetchService,
+		registerScrapers(),
+		deps.Database,
+		relay,
+		deps.Queue,
+		cfg.Queue.Queues,
+		deps.Repositories,
+		cfg.Retry.MaxAttempts,
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("usecase creation: %w", err)
+	}
+
+	return handler.NewExtractHandler(extractReports, obs), nil
+}
+
+// registerScrapers maps sources.scraper_type values to their implementations
+func registerScrapers() *scraper.Registry {
+	registry := scraper.NewRegistry()
+	registry.Register(code4rena.Type, code4rena.New())
+	registry.Register(sherlock.Type, sherlock.New())
+	registry.Register(cantina.Type, cantina.New())
+	registry.Register(codehawks.Type, codehawks.New())
+	return registry
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	// Application layer
+	"extractor/app"
+	"extractor/internal/application/ports"
+
+	// Infrastructure layer
+	"shared/infrastructure/api"
+	"shared/infrastructure/config"
+	"shared/infrastructure/database"
+	"shared/infrastructure/observability"
+	"shared/infrastructure/queue"
+	"shared/infrastructure/repository"
+	"shared/infrastructure/runtime"
+)
+
+func main() {
+	cfg := loadConfiguration()
+	obs := initializeObservability(cfg)
+	deps := initializeDependencies(cfg, obs)
+	initializeApplication(cfg, deps, obs)
+}
+
+// Dependencies holds all initialized infrastructure components
+type Dependencies struct {
+	database     ports.Database
+	repositories ports.Repositories
+	queue        ports.Queue
+}
+
+// loadConfiguration loads and validates the application configuration
+func loadConfiguration() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("configuration: %v", err)
+	}
+	return cfg
+}
+
+// initializeDependencies sets up all infrastructure dependencies
+func initializeDependencies(cfg *config.Config, obs ports.Observability) *Dependencies {
+	// Database initialization - component handles its own logging
+	db, err := database.CreateDB(cfg, obs)
+	if err != nil {
+		log.Fatalf("Failed to create database: %v", err)
+	}
+
+	// Test database connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.Ping(ctx); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Repositories
+	repositories, err := repository.NewRepositories(db, obs)
+	if err != nil {
+		log.Fatalf("Failed to create repositories: %v", err)
+	}
+
+	// Queue initialization - required, the extractor fans out through it
+	publisher, err := queue.CreateQueue(cfg, obs)
+	if err != nil {
+		log.Fatalf("Failed to create queue: %v", err)
+	}
+
+	return &Dependencies{
+		database:     db,
+		repositories: repositories,
+		queue:        publisher,
+	}
+}
+
+// initializeObservability sets up logging and metrics infrastructure
+func initializeObservability(cfg *config.Config) ports.Observability {
+	obs, err := observability.CreateObservability(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize observability: %v", err)
+	}
+	return obs
+}
+
+// buildApplication assembles the application layers
+func buildApplication(cfg *config.Config, deps *Dependencies, obs ports.Observability) (ports.Runtime, error) {
+	handler, err := app.NewHandler(cfg, app.Dependencies{
+		Database:     deps.database,
+		Repositories: deps.repositories,
+		Queue:        deps.queue,
+	}, obs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Route the events of the worker; requests of any other type are
+	// rejected by the router
+	router, err := runtime.NewRouter(obs)
+	if err != nil {
+		return nil, fmt.Errorf("router creation: %w", err)
+	}
+	router.Route(handler, app.EventTypes...)
+
+	// Status and search queries, served by the HTTP runtime
+	statusAPI, err := api.NewStatusAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("status api creation: %w", err)
+	}
+	searchAPI, err := api.NewSearchAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("search api creation: %w", err)
+	}
+
+	// Create runtime
+	runtime, err := runtime.Create(cfg, router, obs, statusAPI, searchAPI)
+	if err != nil {
+		return nil, fmt.Errorf("runtime creation: %w", err)
+	}
+
+	return runtime, nil
+}
+
+func initializeApplication(cfg *config.Config, deps *Dependencies, obs ports.Observability) {
+	app, err := buildApplication(cfg, deps, obs)
+	if err != nil {
+		log.Fatalf("error building the application: %v", err)
+	}
+	app.Start()
+}
@@ -0,0 +1,18 @@
+package dto
+
+import shared "shared/application/dto"
+
+type (
+	ExtractRequest  = shared.ExtractRequest
+	DownloadRequest = shared.DownloadRequest
+)
+
+const (
+	ExtractStageRoot         = shared.ExtractStageRoot
+	ExtractStageIntermediate = shared.ExtractStageIntermediate
+	ExtractStageLeaf         = shared.ExtractStageLeaf
+
+	MetadataSourceID = shared.MetadataSourceID
+
+	DownloadEventRequested = shared.DownloadEventRequested
+)
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"extractor/internal/application/dto"
+	"extractor/internal/application/ports"
+	"extractor/internal/application/usecase"
+)
+
+type ExtractHandler struct {
+	usecase *usecase.ExtractReports
+	logger  ports.Logger
+}
+
+func NewExtractHandler(extractReports *usecase.ExtractReports, obs ports.Observability) *ExtractHandler {
+	logger, _ := obs.LoggerScoped("handler.extract")
+	return &ExtractHandler{
+		usecase: extractReports,
+		logger:  logger,
+	}
+}
+
+func (h *ExtractHandler) Handle(ctx context.Context, request ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	// Parse request
+	extractReq, err := h.parseRequest(request)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	// Validate
+	if err := extractReq.Validate(); err != nil {
+		return h.handleError(ErrHandlerInvalidPayload(err))
+	}
+
+	// Process
+	if err := h.usecase.Extract(ctx, extractReq); err != nil {
+		return h.handleExtractError(extractReq, err)
+	}
+
+	return h.handleExtractSuccess()
+}
+
+func (h *ExtractHandler) parseRequest(request ports.RuntimeRequest) (*dto.ExtractRequest, error) {
+	var extractReq dto.ExtractRequest
+	if err := request.Unmarshal(&extractReq); err != nil {
+		return nil, ErrHandlerUnmarshal(err)
+	}
+	return &extractReq, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/source"
+	"shared/application/ports"
+)
+
+func successResponse() ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: true,
+	}
+}
+
+func errorResponse(message string) ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   message,
+	}
+}
+
+func (h *ExtractHandler) handleExtractSuccess() (ports.RuntimeResponse, error) {
+	h.logger.Info("Extraction successfully completed!")
+	return successResponse(), nil
+}
+
+func (h *ExtractHandler) handleExtractError(req *dto.ExtractRequest, err error) (ports.RuntimeResponse, error) {
+	switch {
+	case errors.Is(err, source.ErrInactive):
+		// Sources can be disabled while their events are still queued
+		h.logger.Info("Source is inactive, skipping",
+			"source_id", req.GetMetadataInt64(dto.MetadataSourceID))
+		return successResponse(), nil
+
+	default:
+		h.logger.Error("Extraction failed",
+			"source_id", req.GetMetadataInt64(dto.MetadataSourceID),
+			"stage", req.EventType,
+			"url", req.URL,
+			"error", err.Error())
+		return errorResponse(err.Error()), nil
+	}
+}
+
+func (h *ExtractHandler) handleError(err error) (ports.RuntimeResponse, error) {
+	h.logger.Error("Extraction failed", "error", err.Error())
+	return errorResponse(err.Error()), nil
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	shared "shared/application/ports"
+)
+
+// WithProviderSlug tags ctx with the provider whose page is requested, so the
+// HTTPClient can apply the crawl policy configured for it
+var WithProviderSlug = shared.WithProviderSlug
+
+// CheckRedirect runs the check of the crawl policy on a redirect, see
+// shared.WithRedirectCheck
+var CheckRedirect = shared.CheckRedirect
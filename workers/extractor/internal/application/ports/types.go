@@ -0,0 +1,24 @@
+package ports
+
+import (
+	shared "shared/application/ports"
+)
+
+type (
+	Queue           = shared.Queue
+	QueueMessage    = shared.QueueMessage
+	Database        = shared.Database
+	Transaction     = shared.Transaction
+	Runtime         = shared.Runtime
+	Handler         = shared.Handler
+	Repositories    = shared.Repositories
+	Logger          = shared.Logger
+	Metrics         = shared.Metrics
+	HTTPClient      = shared.HTTPClient
+	Observability   = shared.Observability
+	RuntimeRequest  = shared.RuntimeRequest
+	RuntimeResponse = shared.RuntimeResponse
+)
+
+// ErrAlreadyExists is returned by Create for rows stored before
+var ErrAlreadyExists = shared.ErrAlreadyExists
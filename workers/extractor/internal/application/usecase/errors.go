@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"fmt"
+)
+
+func ErrSourceNotFound(err error) error {
+	return fmt.Errorf("failed to get source: %w", err)
+}
+
+func ErrSourceUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update source: %w", err)
+}
+
+func ErrProviderNotFound(err error) error {
+	return fmt.Errorf("failed to get provider: %w", err)
+}
+
+func ErrFetchFailed(err error) error {
+	return fmt.Errorf("failed to fetch page: %w", err)
+}
+
+func ErrScrapeFailed(err error) error {
+	return fmt.Errorf("failed to scrape page: %w", err)
+}
+
+func ErrReportLookupFailed(err error) error {
+	return fmt.Errorf("failed to look up audit report: %w", err)
+}
+
+func ErrReportCreationFailed(err error) error {
+	return fmt.Errorf("failed to create audit report: %w", err)
+}
+
+func ErrDownloadCreationFailed(err error) error {
+	return fmt.Errorf("failed to create download: %w", err)
+}
+
+func ErrOutboxMessageFailed(err error) error {
+	return fmt.Errorf("failed to store download event: %w", err)
+}
+
+func ErrPublishExtractEvents(err error) error {
+	return fmt.Errorf("failed to publish extract events: %w", err)
+}
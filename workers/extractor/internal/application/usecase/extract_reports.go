@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/application/ports"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/entity/download"
+	outboxPkg "extractor/internal/domain/entity/outbox"
+	sourcePkg "extractor/internal/domain/entity/source"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/domain/service"
+	"shared/infrastructure/config"
+	"shared/infrastructure/outbox"
+)
+
+type ExtractReports struct {
+	fetchService *service.FetchService
+	scrapers     *scraper.Registry
+	database     ports.Database
+	relay        *outbox.Relay
+	queue        ports.Queue
+	queueNames   config.QueueNames
+	repositories ports.Repositories
+	maxAttempts  int
+	logger       ports.Logger
+	metrics      ports.Metrics
+}
+
+func NewExtractReports(
+	fetchService *service.FetchService,
+	scrapers *scraper.Registry,
+	database ports.Database,
+	relay *outbox.Relay,
+	queue ports.Queue,
+	queueNames config.QueueNames,
+	repositories ports.Repositories,
+	maxAttempts int,
+	obs ports.Observability,
+) (*ExtractReports, error) {
+	logger, metrics, _ := obs.ComponentsScoped("usecase.extract_reports")
+	return &ExtractReports{
+		fetchService: fetchService,
+		scrapers:     scrapers,
+		database:     database,
+		relay:        relay,
+		queue:        queue,
+		queueNames:   queueNames,
+		repositories: repositories,
+		maxAttempts:  maxAttempts,
+		logger:       logger,
+		metrics:      metrics,
+	}, nil
+}
+
+func (e *ExtractReports) Extract(ctx context.Context, req *dto.ExtractRequest) error {
+	// 1. Query the source being crawled
+	source, err := e.repositories.Source().Get(ctx, req.GetMetadataInt64(dto.MetadataSourceID))
+	if err != nil {
+		return ErrSourceNotFound(err)
+	}
+
+	if !source.IsActive {
+		return sourcePkg.ErrInactive
+	}
+
+	// 2. Pick the scraper for this source
+	if source.Scraper() == "" {
+		return sourcePkg.ErrNoScraperType
+	}
+	s, err := e.scrapers.Get(source.Scraper())
+	if err != nil {
+		return err
+	}
+
+	// 3. Fetch and parse the page, within the crawl policy of the provider
+	provider, err := e.repositories.AuditProvider().Get(ctx, source.ProviderID)
+	if err != nil {
+		return ErrProviderNotFound(err)
+	}
+
+	page, err := e.fetchService.Fetch(ports.WithProviderSlug(ctx, provider.Slug), req.URL)
+	if err != nil {
+		return ErrFetchFailed(err)
+	}
+	page.Stage = req.EventType
+	page.Metadata = req.Metadata
+
+	result, err := s.Scrape(ctx, page)
+	if err != nil {
+		return ErrScrapeFailed(err)
+	}
+
+	// 4. Persist the reports found on the page
+	created := 0
+	for i := range result.Reports {
+		ok, err := e.createReport(ctx, source, &result.Reports[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			created++
+		}
+	}
+
+	// 5. Fan out to the next stage
+	if err := e.publishTasks(ctx, req, result.Tasks); err != nil {
+		return err
+	}
+
+	// 6. Record the visit of the index page
+	if req.EventType == dto.ExtractStageRoot {
+		if source.HasStructureChanged(result.Fingerprint) {
+			e.logger.Error("Index page structure changed, scraper may need an update",
+				"source_id", source.ID,
+				"scraper_type", source.Scraper())
+			e.metrics.IncrementCounter("extract.structure_changed", map[string]string{"scraper": source.Scraper()})
+		}
+
+		source.MarkVisited(len(result.Tasks)+len(result.Reports), result.Fingerprint)
+		if err := e.repositories.Source().Update(ctx, source); err != nil {
+			return ErrSourceUpdateFailed(err)
+		}
+	}
+
+	e.logger.Info("Page extracted",
+		"source_id", source.ID,
+		"stage", req.EventType,
+		"url", req.URL,
+		"tasks", len(result.Tasks),
+		"reports_found", len(result.Reports),
+		"reports_created", created)
+
+	return nil
+}
+
+// createReport stores a report, the pending downloads of its artifacts and
+// their download.requested events in one transaction, skipping reports seen
+// before, then publishes the events.
+// It returns whether a new report was created.
+func (e *ExtractReports) createReport(ctx context.Context, source *sourcePkg.Source, found *scraper.Report) (bool, error) {
+	if err := found.Validate(); err != nil {
+		e.logger.Info("Skipping invalid report",
+			"source_id", source.ID,
+			"details_page_url", found.DetailsPageURL,
+			"error", err.Error())
+		return false, nil
+	}
+
+	// Cheap check first; the insert settles a race with another extractor
+	exists, err := e.repositories.AuditReport().ExistsByURL(ctx, source.ID, found.DetailsPageURL)
+	if err != nil {
+		return false, ErrReportLookupFailed(err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	report, err := newAuditReport(source, found)
+	if err != nil {
+		return false, err
+	}
+
+	var messages []*outboxPkg.Message
+	err = e.database.Transaction(ctx, func(tx ports.Transaction) error {
+		repositories := e.repositories.WithTx(tx)
+
+		if err := repositories.AuditReport().Create(ctx, report); err != nil {
+			return ErrReportCreationFailed(err)
+		}
+
+		downloads := []*download.Download{download.NewDownload(report.ID, e.maxAttempts)}
+		for _, artifact := range found.Artifacts {
+			dl, err := download.NewArtifactDownload(report.ID, artifact.Kind, artifact.URL, e.maxAttempts)
+			if err != nil {
+				return ErrDownloadCreationFailed(err)
+			}
+			downloads = append(downloads, dl)
+		}
+
+		// Each download is recorded with its event, so none is left without one
+		messages = make([]*outboxPkg.Message, 0, len(downloads))
+		for _, dl := range downloads {
+			if err := repositories.Download().Create(ctx, dl); err != nil {
+				return ErrDownloadCreationFailed(err)
+			}
+
+			msg, err := e.downloadMessage(dl)
+			if err != nil {
+				return ErrOutboxMessageFailed(err)
+			}
+			if err := repositories.Outbox().Create(ctx, msg); err != nil {
+				return ErrOutboxMessageFailed(err)
+			}
+			messages = append(messages, msg)
+		}
+		return nil
+	})
+	if errors.Is(err, ports.ErrAlreadyExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Publish the events right away; those that fail stay in the outbox and
+	// the orchestrator relays them later
+	for _, msg := range messages {
+		if err := e.relay.Send(ctx, msg); err != nil {
+			e.logger.Error("Failed to publish download event, left in outbox",
+				"report_id", report.ID,
+				"outbox_id", msg.ID,
+				"error", err.Error())
+		}
+	}
+
+	return true, nil
+}
+
+// downloadMessage is the download.requested event of dl, for the outbox
+func (e *ExtractReports) downloadMessage(dl *download.Download) (*outboxPkg.Message, error) {
+	event := &dto.DownloadRequest{
+		EventID:    fmt.Sprintf("download-%d-%d", dl.ID, time.Now().UnixNano()),
+		EventType:  dto.DownloadEventRequested,
+		DownloadID: dl.ID,
+		Kind:       string(dl.Kind),
+		Timestamp:  time.Now(),
+	}
+
+	return outboxPkg.NewMessage(e.queueNames.Downloader, event.EventType, event)
+}
+
+func (e *ExtractReports) publishTasks(ctx context.Context, req *dto.ExtractRequest, tasks []scraper.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	messages := make([]*ports.QueueMessage, 0, len(tasks))
+	for i, task := range tasks {
+		// Children inherit the parent metadata (source_id at least)
+		metadata := make(map[string]interface{}, len(req.Metadata)+len(task.Metadata))
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range task.Metadata {
+			metadata[k] = v
+		}
+
+		messages = append(messages, &ports.QueueMessage{
+			Target: e.queueNames.Extractor,
+			Body: &dto.ExtractRequest{
+				EventID:   fmt.Sprintf("extract-%s-%d-%d", task.Stage, now.UnixNano(), i),
+				EventType: task.Stage,
+				URL:       task.URL,
+				Metadata:  metadata,
+				Timestamp: now,
+			},
+		})
+	}
+
+	if err := e.queue.PublishBatch(ctx, messages); err != nil {
+		return ErrPublishExtractEvents(err)
+	}
+	return nil
+}
+
+func newAuditReport(source *sourcePkg.Source, found *scraper.Report) (*auditreport.AuditReport, error) {
+	engagementType := found.EngagementType
+	if engagementType == "" && source.EngagementType != nil {
+		engagementType = auditreport.EngagementType(*source.EngagementType)
+	}
+
+	report := auditreport.NewAuditReport(
+		source.ID,
+		source.ProviderID,
+		found.Title,
+		engagementType,
+		found.DetailsPageURL,
+		found.DownloadURL,
+	)
+
+	if found.ClientCompany != "" {
+		report.ClientCompany = &found.ClientCompany
+	}
+	if found.RepositoryURL != "" {
+		report.RepositoryURL = &found.RepositoryURL
+	}
+	if found.RepositoryRef != "" {
+		report.RepositoryRef = &found.RepositoryRef
+	}
+	if found.Summary != "" {
+		report.Summary = &found.Summary
+	}
+	report.AuditStartDate = found.StartDate
+	report.AuditEndDate = found.EndDate
+
+	if found.Findings != nil {
+		if err := report.SetFindingsSummary(*found.Findings); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
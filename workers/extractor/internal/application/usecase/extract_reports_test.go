@@ -0,0 +1,470 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/download"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/domain/scraper/scrapertest"
+	"extractor/internal/domain/service"
+	"extractor/internal/infrastructure/scrapers/code4rena"
+	sharedPorts "shared/application/ports"
+	"shared/domain/entity"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+	"shared/infrastructure/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSourceID   = 7
+	testProviderID = 3
+)
+
+var errDatabase = errors.New("database unavailable")
+
+// memoryStore holds the committed rows of the fake repositories
+type memoryStore struct {
+	nextID    int64
+	source    *entity.Source
+	reports   []*entity.AuditReport
+	downloads []*entity.Download
+	outbox    []*entity.OutboxMessage
+
+	failDownloads bool
+
+	// lookedUp runs after a report lookup, to let another extractor store it first
+	lookedUp func()
+}
+
+func (s *memoryStore) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *memoryStore) committed(downloadID int64) bool {
+	for _, dl := range s.downloads {
+		if dl.ID == downloadID {
+			return true
+		}
+	}
+	return false
+}
+
+// stage is a transaction of the fake database, its rows committed on success
+type stage struct {
+	sharedPorts.Transaction
+	reports   []*entity.AuditReport
+	downloads []*entity.Download
+	outbox    []*entity.OutboxMessage
+}
+
+type fakeDatabase struct {
+	sharedPorts.Database
+	store *memoryStore
+}
+
+func (d *fakeDatabase) Transaction(ctx context.Context, fn func(tx sharedPorts.Transaction) error) error {
+	tx := &stage{}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	d.store.reports = append(d.store.reports, tx.reports...)
+	d.store.downloads = append(d.store.downloads, tx.downloads...)
+	d.store.outbox = append(d.store.outbox, tx.outbox...)
+	return nil
+}
+
+// fakeRepositories writes to the store, or to tx when running inside one
+type fakeRepositories struct {
+	sharedPorts.Repositories
+	store *memoryStore
+	tx    *stage
+}
+
+func (r *fakeRepositories) WithTx(tx sharedPorts.Transaction) sharedPorts.Repositories {
+	return &fakeRepositories{store: r.store, tx: tx.(*stage)}
+}
+
+func (r *fakeRepositories) Source() sharedPorts.SourceRepository {
+	return &fakeSources{store: r.store}
+}
+
+func (r *fakeRepositories) AuditProvider() sharedPorts.AuditProviderRepository {
+	return &fakeProviders{}
+}
+
+func (r *fakeRepositories) AuditReport() sharedPorts.AuditReportRepository {
+	return &fakeReports{repositories: r}
+}
+
+func (r *fakeRepositories) Download() sharedPorts.DownloadRepository {
+	return &fakeDownloads{repositories: r}
+}
+
+func (r *fakeRepositories) Outbox() sharedPorts.OutboxRepository {
+	return &fakeOutbox{repositories: r}
+}
+
+type fakeSources struct {
+	sharedPorts.SourceRepository
+	store *memoryStore
+}
+
+func (s *fakeSources) Get(ctx context.Context, id int64) (*entity.Source, error) {
+	if s.store.source == nil || s.store.source.ID != id {
+		return nil, sharedPorts.ErrNotFound
+	}
+	return s.store.source, nil
+}
+
+func (s *fakeSources) Update(ctx context.Context, source *entity.Source) error {
+	s.store.source = source
+	return nil
+}
+
+type fakeProviders struct {
+	sharedPorts.AuditProviderRepository
+}
+
+func (p *fakeProviders) Get(ctx context.Context, id int64) (*entity.AuditProvider, error) {
+	return &entity.AuditProvider{ID: id, Slug: "code4rena"}, nil
+}
+
+type fakeReports struct {
+	sharedPorts.AuditReportRepository
+	repositories *fakeRepositories
+}
+
+func (r *fakeReports) ExistsByURL(ctx context.Context, sourceID int64, detailsURL string) (bool, error) {
+	if lookedUp := r.repositories.store.lookedUp; lookedUp != nil {
+		defer lookedUp()
+	}
+	return r.stored(sourceID, detailsURL), nil
+}
+
+func (r *fakeReports) stored(sourceID int64, detailsURL string) bool {
+	for _, report := range r.repositories.store.reports {
+		if report.SourceID == sourceID && report.DetailsPageURL == detailsURL {
+			return true
+		}
+	}
+	return false
+}
+
+// Create skips stored reports the way the unique key does
+func (r *fakeReports) Create(ctx context.Context, report *entity.AuditReport) error {
+	if r.stored(report.SourceID, report.DetailsPageURL) {
+		return sharedPorts.ErrAlreadyExists
+	}
+	report.ID = r.repositories.store.id()
+	if tx := r.repositories.tx; tx != nil {
+		tx.reports = append(tx.reports, report)
+		return nil
+	}
+	r.repositories.store.reports = append(r.repositories.store.reports, report)
+	return nil
+}
+
+type fakeDownloads struct {
+	sharedPorts.DownloadRepository
+	repositories *fakeRepositories
+}
+
+func (d *fakeDownloads) Create(ctx context.Context, dl *entity.Download) error {
+	if d.repositories.store.failDownloads {
+		return errDatabase
+	}
+	dl.ID = d.repositories.store.id()
+	if tx := d.repositories.tx; tx != nil {
+		tx.downloads = append(tx.downloads, dl)
+		return nil
+	}
+	d.repositories.store.downloads = append(d.repositories.store.downloads, dl)
+	return nil
+}
+
+type fakeOutbox struct {
+	sharedPorts.OutboxRepository
+	repositories *fakeRepositories
+}
+
+func (o *fakeOutbox) Create(ctx context.Context, msg *entity.OutboxMessage) error {
+	msg.ID = o.repositories.store.id()
+	if tx := o.repositories.tx; tx != nil {
+		tx.outbox = append(tx.outbox, msg)
+		return nil
+	}
+	o.repositories.store.outbox = append(o.repositories.store.outbox, msg)
+	return nil
+}
+
+func (o *fakeOutbox) Update(ctx context.Context, msg *entity.OutboxMessage) error {
+	return nil
+}
+
+// fakeQueue records what is published, and whether the download of each
+// download event was committed by then
+type fakeQueue struct {
+	store      *memoryStore
+	fail       bool
+	downloads  []dto.DownloadRequest
+	committed  []bool
+	extractors []*sharedPorts.QueueMessage
+}
+
+func (q *fakeQueue) Publish(ctx context.Context, message *sharedPorts.QueueMessage) error {
+	if q.fail {
+		return errDatabase
+	}
+	var event dto.DownloadRequest
+	if err := json.Unmarshal(message.Body.(json.RawMessage), &event); err != nil {
+		return err
+	}
+	q.downloads = append(q.downloads, event)
+	q.committed = append(q.committed, q.store.committed(event.DownloadID))
+	return nil
+}
+
+func (q *fakeQueue) PublishBatch(ctx context.Context, messages []*sharedPorts.QueueMessage) error {
+	q.extractors = append(q.extractors, messages...)
+	return nil
+}
+
+// stubScraper returns the same result for every page
+type stubScraper struct {
+	result *scraper.Result
+}
+
+func (s *stubScraper) Scrape(ctx context.Context, page *scraper.Page) (*scraper.Result, error) {
+	return s.result, nil
+}
+
+type extractFixture struct {
+	store   *memoryStore
+	queue   *fakeQueue
+	extract *ExtractReports
+	baseURL string
+}
+
+// newExtractFixture builds the use case over fake repositories, fetching the
+// code4rena fixtures from a test server; stub, when not nil, replaces the
+// code4rena scraper
+func newExtractFixture(t *testing.T, stub scraper.Scraper) *extractFixture {
+	t.Helper()
+	server := scrapertest.ServeFixtures(t, "../../infrastructure/scrapers/code4rena/testdata")
+
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	scraperType := code4rena.Type
+	store := &memoryStore{
+		source: &entity.Source{
+			ID:          testSourceID,
+			ProviderID:  testProviderID,
+			ScraperType: &scraperType,
+			IsActive:    true,
+		},
+	}
+	repositories := &fakeRepositories{store: store}
+	queue := &fakeQueue{store: store}
+
+	relay, err := outbox.NewRelay(repositories.Outbox(), queue, obs)
+	require.NoError(t, err)
+
+	registry := scraper.NewRegistry()
+	registry.Register(code4rena.Type, code4rena.New())
+	if stub != nil {
+		registry.Register(code4rena.Type, stub)
+	}
+
+	extract, err := NewExtractReports(
+		service.NewFetchService(server.Client(), 10*1024*1024),
+		registry,
+		&fakeDatabase{store: store},
+		relay,
+		queue,
+		config.QueueNames{Downloader: "downloader", Extractor: "extractor"},
+		repositories,
+		3,
+		obs,
+	)
+	require.NoError(t, err)
+
+	return &extractFixture{store: store, queue: queue, extract: extract, baseURL: server.URL}
+}
+
+func (f *extractFixture) request(stage, path string) *dto.ExtractRequest {
+	return &dto.ExtractRequest{
+		EventID:   "extract-test",
+		EventType: stage,
+		URL:       f.baseURL + path,
+		Metadata:  map[string]interface{}{dto.MetadataSourceID: float64(testSourceID)},
+	}
+}
+
+func TestExtract_RootFansOut(t *testing.T) {
+	f := newExtractFixture(t, nil)
+
+	err := f.extract.Extract(t.Context(), f.request(dto.ExtractStageRoot, "/reports-index.html"))
+	require.NoError(t, err)
+
+	// Leaf tasks for the reports, intermediate tasks for the other index pages
+	require.Len(t, f.queue.extractors, 4)
+	stages := make([]string, 0, len(f.queue.extractors))
+	for _, message := range f.queue.extractors {
+		assert.Equal(t, "extractor", message.Target)
+		task := message.Body.(*dto.ExtractRequest)
+		assert.Equal(t, int64(testSourceID), task.GetMetadataInt64(dto.MetadataSourceID), "children inherit the source")
+		stages = append(stages, task.EventType)
+	}
+	assert.Equal(t, []string{
+		dto.ExtractStageLeaf,
+		dto.ExtractStageLeaf,
+		dto.ExtractStageIntermediate,
+		dto.ExtractStageIntermediate,
+	}, stages)
+	assert.Equal(t, "2025-06-panoptic-hypovault", f.queue.extractors[0].Body.(*dto.ExtractRequest).GetMetadataString("slug"))
+
+	// The index page holds no report, its visit is recorded
+	assert.Empty(t, f.store.reports)
+	assert.Empty(t, f.queue.downloads)
+	require.NotNil(t, f.store.source.LastVisitedAt)
+	require.NotNil(t, f.store.source.LastReportsCount)
+	assert.Equal(t, 4, *f.store.source.LastReportsCount)
+}
+
+func TestExtract_IntermediateFansOutToLeaves(t *testing.T) {
+	f := newExtractFixture(t, nil)
+
+	err := f.extract.Extract(t.Context(), f.request(dto.ExtractStageIntermediate, "/reports-index.html?page=2"))
+	require.NoError(t, err)
+
+	require.Len(t, f.queue.extractors, 2)
+	for _, message := range f.queue.extractors {
+		assert.Equal(t, dto.ExtractStageLeaf, message.Body.(*dto.ExtractRequest).EventType)
+	}
+	assert.Nil(t, f.store.source.LastVisitedAt, "only the root stage records a visit")
+}
+
+func TestExtract_LeafCreatesReportOnce(t *testing.T) {
+	f := newExtractFixture(t, nil)
+	req := f.request(dto.ExtractStageLeaf, "/reports/2025-06-panoptic-hypovault")
+
+	require.NoError(t, f.extract.Extract(t.Context(), req))
+
+	require.Len(t, f.store.reports, 1)
+	report := f.store.reports[0]
+	assert.Equal(t, int64(testSourceID), report.SourceID)
+	assert.Equal(t, int64(testProviderID), report.ProviderID)
+	assert.Equal(t, f.baseURL+"/audits/2025-06-panoptic-hypovault", report.DetailsPageURL)
+
+	require.Len(t, f.store.downloads, 1)
+	assert.Equal(t, report.ID, f.store.downloads[0].ReportID)
+	assert.Equal(t, download.ArtifactKindReport, f.store.downloads[0].Kind)
+
+	require.Len(t, f.queue.downloads, 1)
+	assert.Equal(t, dto.DownloadEventRequested, f.queue.downloads[0].EventType)
+	assert.Equal(t, f.store.downloads[0].ID, f.queue.downloads[0].DownloadID)
+	assert.Equal(t, []bool{true}, f.queue.committed, "events are published after commit")
+	require.Len(t, f.store.outbox, 1)
+	assert.True(t, f.store.outbox[0].IsPublished())
+
+	// The same page again creates nothing
+	require.NoError(t, f.extract.Extract(t.Context(), req))
+	assert.Len(t, f.store.reports, 1)
+	assert.Len(t, f.store.downloads, 1)
+	assert.Len(t, f.queue.downloads, 1)
+}
+
+func TestExtract_LeafSkipsReportStoredConcurrently(t *testing.T) {
+	f := newExtractFixture(t, nil)
+
+	// Another extractor stores the report between the lookup and the insert
+	f.store.lookedUp = func() {
+		f.store.lookedUp = nil
+		f.store.reports = append(f.store.reports, &entity.AuditReport{
+			ID:             f.store.id(),
+			SourceID:       testSourceID,
+			DetailsPageURL: f.baseURL + "/audits/2025-06-panoptic-hypovault",
+		})
+	}
+
+	require.NoError(t, f.extract.Extract(t.Context(), f.request(dto.ExtractStageLeaf, "/reports/2025-06-panoptic-hypovault")))
+
+	assert.Len(t, f.store.reports, 1, "the other extractor keeps it")
+	assert.Empty(t, f.store.downloads)
+	assert.Empty(t, f.store.outbox)
+	assert.Empty(t, f.queue.downloads)
+}
+
+func TestExtract_ReportWithArtifacts(t *testing.T) {
+	f := newExtractFixture(t, &stubScraper{result: &scraper.Result{
+		Reports: []scraper.Report{{
+			Title:          "Vault",
+			DetailsPageURL: "https://audits.example.com/vault",
+			DownloadURL:    "https://audits.example.com/vault.pdf",
+			Artifacts: []scraper.Artifact{
+				{Kind: download.ArtifactKindFindings, URL: "https://audits.example.com/vault-findings.md"},
+				{Kind: download.ArtifactKindFixReview, URL: "https://audits.example.com/vault-fix-review.pdf"},
+			},
+		}},
+	}})
+
+	require.NoError(t, f.extract.Extract(t.Context(), f.request(dto.ExtractStageLeaf, "/reports/2025-06-panoptic-hypovault")))
+
+	require.Len(t, f.store.reports, 1)
+	require.Len(t, f.store.downloads, 3)
+	kinds := make([]download.ArtifactKind, 0, len(f.store.downloads))
+	for _, dl := range f.store.downloads {
+		assert.Equal(t, f.store.reports[0].ID, dl.ReportID)
+		kinds = append(kinds, dl.Kind)
+	}
+	assert.Equal(t, []download.ArtifactKind{
+		download.ArtifactKindReport,
+		download.ArtifactKindFindings,
+		download.ArtifactKindFixReview,
+	}, kinds)
+	require.NotNil(t, f.store.downloads[1].SourceURL)
+	assert.Equal(t, "https://audits.example.com/vault-findings.md", *f.store.downloads[1].SourceURL)
+
+	require.Len(t, f.queue.downloads, 3)
+	for i, event := range f.queue.downloads {
+		assert.Equal(t, f.store.downloads[i].ID, event.DownloadID)
+		assert.Equal(t, string(f.store.downloads[i].Kind), event.Kind)
+	}
+	assert.Equal(t, []bool{true, true, true}, f.queue.committed)
+}
+
+func TestExtract_FailedDownloadRollsBackReport(t *testing.T) {
+	f := newExtractFixture(t, nil)
+	f.store.failDownloads = true
+
+	err := f.extract.Extract(t.Context(), f.request(dto.ExtractStageLeaf, "/reports/2025-06-panoptic-hypovault"))
+	require.ErrorIs(t, err, errDatabase)
+
+	assert.Empty(t, f.store.reports, "the report is not committed without its downloads")
+	assert.Empty(t, f.store.outbox)
+	assert.Empty(t, f.queue.downloads)
+}
+
+func TestExtract_FailedPublishLeavesEventInOutbox(t *testing.T) {
+	f := newExtractFixture(t, nil)
+	f.queue.fail = true
+
+	require.NoError(t, f.extract.Extract(t.Context(), f.request(dto.ExtractStageLeaf, "/reports/2025-06-panoptic-hypovault")))
+
+	require.Len(t, f.store.downloads, 1)
+	require.Len(t, f.store.outbox, 1)
+	assert.False(t, f.store.outbox[0].IsPublished(), "the orchestrator relays it later")
+	assert.Equal(t, 1, f.store.outbox[0].AttemptCount)
+}
@@ -0,0 +1,20 @@
+package auditreport
+
+import "shared/domain/entity/auditreport"
+
+type (
+	AuditReport     = auditreport.AuditReport
+	EngagementType  = auditreport.EngagementType
+	FindingsSummary = auditreport.FindingsSummary
+)
+
+const (
+	EngagementTypeCompetition = auditreport.EngagementTypeCompetition
+	EngagementTypePrivate     = auditreport.EngagementTypePrivate
+	EngagementTypeBugBounty   = auditreport.EngagementTypeBugBounty
+	EngagementTypeSolo        = auditreport.EngagementTypeSolo
+)
+
+func NewAuditReport(sourceID, providerID int64, title string, engagementType EngagementType, detailsPageURL, sourceDownloadURL string) *AuditReport {
+	return auditreport.NewAuditReport(sourceID, providerID, title, engagementType, detailsPageURL, sourceDownloadURL)
+}
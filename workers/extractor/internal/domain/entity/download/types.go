@@ -0,0 +1,22 @@
+package download
+
+import "shared/domain/entity/download"
+
+type (
+	Download     = download.Download
+	ArtifactKind = download.ArtifactKind
+)
+
+const (
+	ArtifactKindReport    = download.ArtifactKindReport
+	ArtifactKindFindings  = download.ArtifactKindFindings
+	ArtifactKindFixReview = download.ArtifactKindFixReview
+)
+
+func NewDownload(reportID int64, maxAttempts int) *Download {
+	return download.NewDownload(reportID, maxAttempts)
+}
+
+func NewArtifactDownload(reportID int64, kind ArtifactKind, sourceURL string, maxAttempts int) (*Download, error) {
+	return download.NewArtifactDownload(reportID, kind, sourceURL, maxAttempts)
+}
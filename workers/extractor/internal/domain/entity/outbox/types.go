@@ -0,0 +1,13 @@
+package outbox
+
+import (
+	"shared/domain/entity/outbox"
+)
+
+type (
+	Message = outbox.Message
+)
+
+func NewMessage(target, eventType string, body interface{}) (*Message, error) {
+	return outbox.NewMessage(target, eventType, body)
+}
@@ -0,0 +1,12 @@
+package source
+
+import "shared/domain/entity/source"
+
+type (
+	Source = source.Source
+)
+
+var (
+	ErrInactive      = source.ErrInactive
+	ErrNoScraperType = source.ErrNoScraperType
+)
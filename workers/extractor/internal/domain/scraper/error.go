@@ -0,0 +1,32 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+
+	"extractor/internal/domain/entity/download"
+)
+
+var (
+	ErrMissingTitle       = errors.New("report title cannot be empty")
+	ErrMissingDetailsURL  = errors.New("report details page URL cannot be empty")
+	ErrMissingDownloadURL = errors.New("report download URL cannot be empty")
+	ErrMissingArtifactURL = errors.New("artifact URL cannot be empty")
+)
+
+// ErrInvalidArtifactKind is returned for an unknown kind, or one the report already has
+func ErrInvalidArtifactKind(kind download.ArtifactKind) error {
+	return fmt.Errorf("invalid or duplicate artifact kind %q", kind)
+}
+
+func ErrUnknownScraper(scraperType string) error {
+	return fmt.Errorf("no scraper registered for type %q", scraperType)
+}
+
+func ErrUnexpectedStage(stage string) error {
+	return fmt.Errorf("unexpected extraction stage: %s", stage)
+}
+
+func ErrParsePage(err error) error {
+	return fmt.Errorf("failed to parse page: %w", err)
+}
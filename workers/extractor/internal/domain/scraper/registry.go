@@ -0,0 +1,26 @@
+package scraper
+
+// Registry maps sources.scraper_type values to scraper implementations
+type Registry struct {
+	scrapers map[string]Scraper
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		scrapers: make(map[string]Scraper),
+	}
+}
+
+// Register adds a scraper for the given type, replacing any previous one
+func (r *Registry) Register(scraperType string, scraper Scraper) {
+	r.scrapers[scraperType] = scraper
+}
+
+// Get returns the scraper registered for the given type
+func (r *Registry) Get(scraperType string) (Scraper, error) {
+	scraper, ok := r.scrapers[scraperType]
+	if !ok {
+		return nil, ErrUnknownScraper(scraperType)
+	}
+	return scraper, nil
+}
@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/entity/download"
+)
+
+// Page is a fetched document handed to a scraper
+type Page struct {
+	// Stage is the extraction stage: root, intermediate or leaf
+	Stage string
+	// URL is the final URL of the document, after redirects
+	URL         string
+	ContentType string
+	Body        []byte
+	// Metadata carries data extracted in earlier stages
+	Metadata map[string]interface{}
+}
+
+// Task is a follow-up page the scraper wants crawled
+type Task struct {
+	Stage    string
+	URL      string
+	Metadata map[string]interface{}
+}
+
+// Report is an audit report found on a page
+type Report struct {
+	Title          string
+	DetailsPageURL string
+	DownloadURL    string
+
+	// Optional fields
+	EngagementType auditreport.EngagementType // defaults to the source's engagement type
+	ClientCompany  string
+	RepositoryURL  string
+	RepositoryRef  string // commit or tag of RepositoryURL the audit covered
+	Summary        string
+	StartDate      *time.Time
+	EndDate        *time.Time
+	Findings       *auditreport.FindingsSummary
+	// Artifacts are the files published next to the report at DownloadURL
+	Artifacts []Artifact
+}
+
+// Artifact is a secondary file of a report, such as findings published apart
+// or a fix review
+type Artifact struct {
+	Kind download.ArtifactKind
+	URL  string
+}
+
+// Result is everything a scraper found on a page
+type Result struct {
+	Tasks   []Task
+	Reports []Report
+
+	// Fingerprint identifies the structure of an index page, so layout
+	// changes that break a scraper can be detected between visits
+	Fingerprint string
+}
+
+// Scraper parses pages of one provider. Implementations must not do their
+// own fetching; follow-up pages are returned as tasks.
+type Scraper interface {
+	Scrape(ctx context.Context, page *Page) (*Result, error)
+}
+
+// Validate checks the fields every report needs
+func (r *Report) Validate() error {
+	if r.Title == "" {
+		return ErrMissingTitle
+	}
+	if r.DetailsPageURL == "" {
+		return ErrMissingDetailsURL
+	}
+	if r.DownloadURL == "" {
+		return ErrMissingDownloadURL
+	}
+
+	seen := map[download.ArtifactKind]bool{download.ArtifactKindReport: true}
+	for _, artifact := range r.Artifacts {
+		if !artifact.Kind.IsValid() || seen[artifact.Kind] {
+			return ErrInvalidArtifactKind(artifact.Kind)
+		}
+		if artifact.URL == "" {
+			return ErrMissingArtifactURL
+		}
+		seen[artifact.Kind] = true
+	}
+	return nil
+}
+
+// Fingerprint hashes a fragment of a page, typically the markup skeleton of its main container
+func Fingerprint(fragment string) string {
+	sum := sha256.Sum256([]byte(fragment))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,34 @@
+// Package scrapertest serves recorded pages over HTTP so scrapers can be
+// exercised offline through the same fetch path the worker uses.
+package scrapertest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"extractor/internal/domain/scraper"
+	"extractor/internal/domain/service"
+)
+
+// ServeFixtures serves the files of dir (usually "testdata") at their relative paths
+func ServeFixtures(t *testing.T, dir string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Fetch loads path from the fixture server and prepares it for the given stage
+func Fetch(t *testing.T, server *httptest.Server, path, stage string, metadata map[string]interface{}) *scraper.Page {
+	t.Helper()
+	svc := service.NewFetchService(server.Client(), 10*1024*1024)
+
+	page, err := svc.Fetch(t.Context(), server.URL+path)
+	if err != nil {
+		t.Fatalf("fetch fixture %s: %v", path, err)
+	}
+	page.Stage = stage
+	page.Metadata = metadata
+	return page
+}
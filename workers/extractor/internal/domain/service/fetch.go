@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"extractor/internal/application/ports"
+	"extractor/internal/domain/scraper"
+)
+
+type FetchService struct {
+	httpClient  ports.HTTPClient
+	maxPageSize int64
+	userAgent   string
+}
+
+func NewFetchService(httpClient ports.HTTPClient, maxPageSize int64) *FetchService {
+	return &FetchService{
+		httpClient:  httpClient,
+		maxPageSize: maxPageSize,
+		userAgent:   "AuditReportExtractor/1.0",
+	}
+}
+
+// Fetch downloads a page to be handed to a scraper. Pages are small, so unlike
+// the downloader the whole body is kept in memory.
+func (s *FetchService) Fetch(ctx context.Context, url string) (*scraper.Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, ErrRequestCreation(err)
+	}
+
+	req.Header.Set("User-Agent", s.userAgent)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, ErrHTTPRequest(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrUnexpectedStatus(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxPageSize+1))
+	if err != nil {
+		return nil, ErrReadResponse(err)
+	}
+	if int64(len(body)) > s.maxPageSize {
+		return nil, ErrPageTooLarge
+	}
+
+	// Relative links on the page resolve against the URL we ended up at
+	finalURL := url
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+
+	return &scraper.Page{
+		URL:         finalURL,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch_ServesFixture(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	svc := NewFetchService(server.Client(), 1024*1024)
+	page, err := svc.Fetch(t.Context(), server.URL+"/reports.html")
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/reports.html", page.URL)
+	assert.Contains(t, page.ContentType, "text/html")
+	assert.Contains(t, string(page.Body), "/reports/2025-06-example")
+}
+
+func TestFetch_FollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "moved")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewFetchService(server.Client(), 1024)
+	page, err := svc.Fetch(t.Context(), server.URL+"/old")
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/new", page.URL)
+}
+
+func TestFetch_RejectsUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	svc := NewFetchService(server.Client(), 1024)
+	_, err := svc.Fetch(t.Context(), server.URL)
+	assert.Error(t, err)
+}
+
+func TestFetch_RejectsOversizedPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("a", 64))
+	}))
+	defer server.Close()
+
+	svc := NewFetchService(server.Client(), 16)
+	_, err := svc.Fetch(t.Context(), server.URL)
+	assert.True(t, errors.Is(err, ErrPageTooLarge))
+}
@@ -0,0 +1,147 @@
+// Package cantina scrapes the Cantina portfolio.
+//
+// Every portfolio section lists its reviews with the link to the published
+// PDF on the card itself, so the root stage yields the reports directly. The
+// section query parameter tells which kind of engagement the page lists.
+package cantina
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/infrastructure/scrapers/markup"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Type is the sources.scraper_type value handled by this scraper
+const Type = "cantina"
+
+// maxCardDepth bounds how far above a portfolio link its card is looked for
+const maxCardDepth = 4
+
+var portfolioPathRe = regexp.MustCompile(`^/portfolio/([A-Za-z0-9-]+)$`)
+
+// sectionEngagements maps portfolio sections to engagement types; the
+// unfiltered portfolio lists the private reviews
+var sectionEngagements = map[string]auditreport.EngagementType{
+	"":                     auditreport.EngagementTypePrivate,
+	"spearbit-guild":       auditreport.EngagementTypePrivate,
+	"cantina-competitions": auditreport.EngagementTypeCompetition,
+	"cantina-solo":         auditreport.EngagementTypeSolo,
+	"cantina-bug-bounties": auditreport.EngagementTypeBugBounty,
+}
+
+type Scraper struct{}
+
+func New() *Scraper {
+	return &Scraper{}
+}
+
+func (s *Scraper) Scrape(ctx context.Context, page *scraper.Page) (*scraper.Result, error) {
+	if page.Stage != dto.ExtractStageRoot {
+		return nil, scraper.ErrUnexpectedStage(page.Stage)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	base, err := url.Parse(page.URL)
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	section := base.Query().Get("section")
+	engagementType, ok := sectionEngagements[section]
+	if !ok {
+		return nil, scraper.ErrParsePage(errUnknownSection(section))
+	}
+
+	result := &scraper.Result{
+		Fingerprint: markup.Fingerprint(doc, "main"),
+	}
+
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		link, err := base.Parse(href)
+		if err != nil || link.Host != base.Host || !portfolioPathRe.MatchString(link.Path) {
+			return
+		}
+
+		detailsURL := markup.Resolve(base, link.Path)
+		if seen[detailsURL] {
+			return
+		}
+
+		card, downloadURL := reportCard(a, base)
+		if card == nil {
+			return
+		}
+		seen[detailsURL] = true
+
+		title := markup.Text(card.Find("h2, h3").First())
+		if title == "" {
+			title = markup.Text(a)
+		}
+
+		result.Reports = append(result.Reports, scraper.Report{
+			Title:          title,
+			DetailsPageURL: detailsURL,
+			DownloadURL:    downloadURL,
+			EngagementType: engagementType,
+			// Reviews are listed under the name of the client
+			ClientCompany: title,
+			Summary:       markup.Text(card.Find("p").First()),
+		})
+	})
+
+	return result, nil
+}
+
+// Private helper functions
+
+// reportCard finds the closest container of link that also holds a PDF link.
+// Reviews whose report is not published yet have no PDF and are skipped,
+// without borrowing the PDF of a neighbouring card.
+func reportCard(link *goquery.Selection, base *url.URL) (*goquery.Selection, string) {
+	card := link
+	for depth := 0; depth < maxCardDepth; depth++ {
+		card = card.Parent()
+		if card.Length() == 0 || portfolioLinks(card) > 1 {
+			return nil, ""
+		}
+
+		pdf := card.Find(`a[href$=".pdf"]`).First()
+		if pdf.Length() == 0 {
+			continue
+		}
+
+		href, _ := pdf.Attr("href")
+		downloadURL, err := base.Parse(href)
+		if err != nil {
+			return nil, ""
+		}
+		return card, downloadURL.String()
+	}
+	return nil, ""
+}
+
+// portfolioLinks counts the distinct portfolio entries linked from selection
+func portfolioLinks(selection *goquery.Selection) int {
+	hrefs := make(map[string]bool)
+	selection.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		if link, err := url.Parse(href); err == nil && portfolioPathRe.MatchString(link.Path) {
+			hrefs[link.Path] = true
+		}
+	})
+	return len(hrefs)
+}
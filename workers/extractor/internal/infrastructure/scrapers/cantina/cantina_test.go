@@ -0,0 +1,63 @@
+package cantina
+
+import (
+	"testing"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper/scrapertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCantina_ScrapePortfolio(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/portfolio.html", dto.ExtractStageRoot, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	// The review without a published PDF is skipped
+	require.Len(t, result.Reports, 2)
+	assert.Empty(t, result.Tasks)
+	assert.NotEmpty(t, result.Fingerprint)
+
+	report := result.Reports[0]
+	require.NoError(t, report.Validate())
+	assert.Equal(t, "OpenTrade", report.Title)
+	assert.Equal(t, "OpenTrade", report.ClientCompany)
+	assert.Equal(t, server.URL+"/portfolio/8f1c2b7e-4a5d-4c3e-9b1a-0d2e6f7a8b9c", report.DetailsPageURL)
+	assert.Equal(t, "https://cdn.cantina.xyz/reports/cantina_opentrade_aug2025.pdf", report.DownloadURL)
+	assert.Equal(t, "Security review of the OpenTrade tokenized vaults.", report.Summary)
+	assert.Equal(t, auditreport.EngagementTypePrivate, report.EngagementType)
+
+	assert.Equal(t, "Uniswap Foundation", result.Reports[1].Title)
+	assert.Equal(t, "https://cdn.cantina.xyz/reports/cantina_uniswap_jul2025.pdf", result.Reports[1].DownloadURL)
+}
+
+func TestCantina_SectionSelectsEngagementType(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+
+	cases := map[string]auditreport.EngagementType{
+		"cantina-competitions": auditreport.EngagementTypeCompetition,
+		"cantina-solo":         auditreport.EngagementTypeSolo,
+		"spearbit-guild":       auditreport.EngagementTypePrivate,
+	}
+	for section, expected := range cases {
+		page := scrapertest.Fetch(t, server, "/portfolio.html?section="+section, dto.ExtractStageRoot, nil)
+
+		result, err := New().Scrape(t.Context(), page)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Reports)
+		assert.Equal(t, expected, result.Reports[0].EngagementType, section)
+	}
+}
+
+func TestCantina_UnknownSection(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/portfolio.html?section=unknown", dto.ExtractStageRoot, nil)
+
+	_, err := New().Scrape(t.Context(), page)
+	assert.Error(t, err)
+}
@@ -0,0 +1,7 @@
+package cantina
+
+import "fmt"
+
+func errUnknownSection(section string) error {
+	return fmt.Errorf("unknown Cantina portfolio section: %q", section)
+}
@@ -0,0 +1,227 @@
+// Package code4rena scrapes the published reports of Code4rena contests.
+//
+// The root stage parses the reports index and emits one leaf task per report,
+// plus intermediate tasks for further index pages. The leaf stage parses the
+// report itself, which is also the artifact handed to the downloader.
+package code4rena
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/infrastructure/scrapers/markup"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Type is the sources.scraper_type value handled by this scraper
+const Type = "code4rena"
+
+var (
+	reportPathRe = regexp.MustCompile(`^/reports/(\d{4}-\d{2}-[a-z0-9-]+)$`)
+	repositoryRe = regexp.MustCompile(`^https://github\.com/code-423n4/[A-Za-z0-9_.-]+$`)
+	clientRe     = regexp.MustCompile(`analysis of the (.+?) smart contract system`)
+	periodRe     = regexp.MustCompile(`took place (?:from|between) ([A-Z][a-z]+ \d{1,2})(?:, (\d{4}))? (?:to|through|and|-|–|—) ([A-Z][a-z]+ \d{1,2}),? (\d{4})`)
+	totalsRe     = regexp.MustCompile(`(\d+) received a risk rating in the category of HIGH severity and (\d+) received a risk rating in the category of MEDIUM severity`)
+	lowRe        = regexp.MustCompile(`(\d+) reports? detailing issues with a risk rating of LOW severity`)
+	findingIDRe  = regexp.MustCompile(`\[([HML])-(\d+)\]`)
+)
+
+type Scraper struct{}
+
+func New() *Scraper {
+	return &Scraper{}
+}
+
+func (s *Scraper) Scrape(ctx context.Context, page *scraper.Page) (*scraper.Result, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	base, err := url.Parse(page.URL)
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	switch page.Stage {
+	case dto.ExtractStageRoot, dto.ExtractStageIntermediate:
+		return s.scrapeIndex(doc, base, page.Stage == dto.ExtractStageRoot), nil
+	case dto.ExtractStageLeaf:
+		return s.scrapeReport(doc, base)
+	default:
+		return nil, scraper.ErrUnexpectedStage(page.Stage)
+	}
+}
+
+// scrapeIndex collects report links and, from the first page only, the other index pages
+func (s *Scraper) scrapeIndex(doc *goquery.Document, base *url.URL, followPages bool) *scraper.Result {
+	result := &scraper.Result{
+		Fingerprint: markup.Fingerprint(doc, "main"),
+	}
+
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		link, err := base.Parse(href)
+		if err != nil || link.Host != base.Host {
+			return
+		}
+
+		if match := reportPathRe.FindStringSubmatch(link.Path); match != nil {
+			reportURL := markup.Resolve(base, link.Path)
+			if seen[reportURL] {
+				return
+			}
+			seen[reportURL] = true
+			result.Tasks = append(result.Tasks, scraper.Task{
+				Stage:    dto.ExtractStageLeaf,
+				URL:      reportURL,
+				Metadata: map[string]interface{}{"slug": match[1]},
+			})
+			return
+		}
+
+		// Pagination links of the index itself
+		if followPages && link.Path == base.Path && link.Query().Get("page") != "" {
+			pageURL := link.String()
+			if seen[pageURL] {
+				return
+			}
+			seen[pageURL] = true
+			result.Tasks = append(result.Tasks, scraper.Task{
+				Stage: dto.ExtractStageIntermediate,
+				URL:   pageURL,
+			})
+		}
+	})
+
+	return result
+}
+
+func (s *Scraper) scrapeReport(doc *goquery.Document, base *url.URL) (*scraper.Result, error) {
+	match := reportPathRe.FindStringSubmatch(base.Path)
+	if match == nil {
+		return nil, scraper.ErrParsePage(errUnexpectedReportURL(base.String()))
+	}
+	slug := match[1]
+
+	text := markup.NormalizeSpace(doc.Find("body").Text())
+
+	report := scraper.Report{
+		Title:          title(doc),
+		DetailsPageURL: markup.Resolve(base, "/audits/"+slug),
+		DownloadURL:    markup.Resolve(base, base.Path),
+		EngagementType: auditreport.EngagementTypeCompetition,
+		RepositoryURL:  repository(doc),
+		Summary:        summary(doc),
+	}
+
+	if m := clientRe.FindStringSubmatch(text); m != nil {
+		report.ClientCompany = m[1]
+	}
+	report.StartDate, report.EndDate = auditPeriod(text)
+
+	findings := findingsSummary(doc, text)
+	report.Findings = &findings
+
+	return &scraper.Result{Reports: []scraper.Report{report}}, nil
+}
+
+// Private helper functions
+
+func title(doc *goquery.Document) string {
+	heading := markup.NormalizeSpace(doc.Find("h1").First().Text())
+	heading = strings.TrimSuffix(heading, "Findings & Analysis Report")
+	return strings.TrimSpace(heading)
+}
+
+// repository returns the contest repository, never the findings one
+func repository(doc *goquery.Document) string {
+	var repo string
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		href = strings.TrimSuffix(href, "/")
+		if repositoryRe.MatchString(href) && !strings.HasSuffix(href, "-findings") {
+			repo = href
+			return false
+		}
+		return true
+	})
+	return repo
+}
+
+// summary is the paragraph announcing the vulnerability totals
+func summary(doc *goquery.Document) string {
+	var text string
+	doc.Find("p").EachWithBreak(func(_ int, p *goquery.Selection) bool {
+		paragraph := markup.NormalizeSpace(p.Text())
+		if strings.Contains(paragraph, "aggregated total of") {
+			text = paragraph
+			return false
+		}
+		return true
+	})
+	return text
+}
+
+// auditPeriod parses "took place from June 27 to July 07, 2025"
+func auditPeriod(text string) (*time.Time, *time.Time) {
+	m := periodRe.FindStringSubmatch(text)
+	if m == nil {
+		return nil, nil
+	}
+
+	end, err := time.Parse("January 2 2006", m[3]+" "+m[4])
+	if err != nil {
+		return nil, nil
+	}
+
+	startYear := m[2]
+	if startYear == "" {
+		startYear = m[4]
+	}
+	start, err := time.Parse("January 2 2006", m[1]+" "+startYear)
+	if err != nil {
+		return nil, &end
+	}
+	// "December 28 to January 9, 2025" spans the new year
+	if start.After(end) {
+		start = start.AddDate(-1, 0, 0)
+	}
+
+	return &start, &end
+}
+
+// findingsSummary reads the totals from the summary, falling back to counting finding headings
+func findingsSummary(doc *goquery.Document, text string) auditreport.FindingsSummary {
+	var summary auditreport.FindingsSummary
+
+	if m := totalsRe.FindStringSubmatch(text); m != nil {
+		summary.High, _ = strconv.Atoi(m[1])
+		summary.Medium, _ = strconv.Atoi(m[2])
+	} else {
+		ids := map[string]map[string]bool{"H": {}, "M": {}}
+		doc.Find("h2, h3").Each(func(_ int, h *goquery.Selection) {
+			if m := findingIDRe.FindStringSubmatch(h.Text()); m != nil && ids[m[1]] != nil {
+				ids[m[1]][m[2]] = true
+			}
+		})
+		summary.High = len(ids["H"])
+		summary.Medium = len(ids["M"])
+	}
+
+	if m := lowRe.FindStringSubmatch(text); m != nil {
+		summary.Low, _ = strconv.Atoi(m[1])
+	}
+
+	return summary
+}
@@ -0,0 +1,105 @@
+package code4rena
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper/scrapertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode4rena_ScrapeIndex(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/reports-index.html", dto.ExtractStageRoot, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	require.Len(t, result.Tasks, 4)
+	assert.Equal(t, dto.ExtractStageLeaf, result.Tasks[0].Stage)
+	assert.Equal(t, server.URL+"/reports/2025-06-panoptic-hypovault", result.Tasks[0].URL)
+	assert.Equal(t, "2025-06-panoptic-hypovault", result.Tasks[0].Metadata["slug"])
+	assert.Equal(t, server.URL+"/reports/2025-04-virtuals-protocol", result.Tasks[1].URL)
+	assert.Equal(t, dto.ExtractStageIntermediate, result.Tasks[2].Stage)
+	assert.Equal(t, server.URL+"/reports-index.html?page=2", result.Tasks[2].URL)
+	assert.Equal(t, server.URL+"/reports-index.html?page=3", result.Tasks[3].URL)
+	assert.Empty(t, result.Reports)
+	assert.NotEmpty(t, result.Fingerprint)
+}
+
+func TestCode4rena_IntermediatePagesDoNotFollowPagination(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/reports-index.html", dto.ExtractStageIntermediate, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	require.Len(t, result.Tasks, 2)
+	for _, task := range result.Tasks {
+		assert.Equal(t, dto.ExtractStageLeaf, task.Stage)
+	}
+}
+
+func TestCode4rena_ScrapeReport(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/reports/2025-06-panoptic-hypovault", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	report := result.Reports[0]
+	require.NoError(t, report.Validate())
+	assert.Equal(t, "Panoptic Hypovault", report.Title)
+	assert.Equal(t, "Panoptic", report.ClientCompany)
+	assert.Equal(t, server.URL+"/audits/2025-06-panoptic-hypovault", report.DetailsPageURL)
+	assert.Equal(t, server.URL+"/reports/2025-06-panoptic-hypovault", report.DownloadURL)
+	assert.Equal(t, "https://github.com/code-423n4/2025-06-panoptic", report.RepositoryURL)
+	assert.Equal(t, auditreport.EngagementTypeCompetition, report.EngagementType)
+	assert.Contains(t, report.Summary, "aggregated total of 2 unique vulnerabilities")
+
+	require.NotNil(t, report.StartDate)
+	require.NotNil(t, report.EndDate)
+	assert.Equal(t, time.Date(2025, time.June, 27, 0, 0, 0, 0, time.UTC), *report.StartDate)
+	assert.Equal(t, time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC), *report.EndDate)
+
+	assert.Equal(t, &auditreport.FindingsSummary{High: 2, Medium: 0, Low: 2}, report.Findings)
+}
+
+func TestCode4rena_ScrapeReportFallsBackToFindingHeadings(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/reports/2024-12-legacy-vault", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	report := result.Reports[0]
+	assert.Equal(t, "Legacy Vault", report.Title)
+	assert.Equal(t, "https://github.com/code-423n4/2024-12-legacy-vault", report.RepositoryURL)
+	assert.Equal(t, time.Date(2024, time.December, 28, 0, 0, 0, 0, time.UTC), *report.StartDate)
+	assert.Equal(t, time.Date(2025, time.January, 9, 0, 0, 0, 0, time.UTC), *report.EndDate)
+	assert.Equal(t, &auditreport.FindingsSummary{High: 1, Medium: 2}, report.Findings)
+}
+
+func TestCode4rena_FingerprintIgnoresNewReports(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/reports-index.html", dto.ExtractStageRoot, nil)
+
+	before, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	extra := `<li class="report-card"><a class="report-card__link" href="/reports/2025-07-new"><span class="report-card__title">New</span></a></li>`
+	page.Body = []byte(strings.Replace(string(page.Body), `<ul class="report-list">`, `<ul class="report-list">`+extra, 1))
+
+	after, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	assert.Equal(t, before.Fingerprint, after.Fingerprint)
+	assert.Len(t, after.Tasks, len(before.Tasks)+1)
+}
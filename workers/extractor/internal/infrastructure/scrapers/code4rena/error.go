@@ -0,0 +1,7 @@
+package code4rena
+
+import "fmt"
+
+func errUnexpectedReportURL(url string) error {
+	return fmt.Errorf("not a Code4rena report URL: %s", url)
+}
@@ -0,0 +1,196 @@
+// Package codehawks scrapes the contests of CodeHawks.
+//
+// The root stage parses the contests index and emits one leaf task per
+// contest. The leaf stage parses the contest page, which links to the
+// findings report once the results are published.
+package codehawks
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/infrastructure/scrapers/markup"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Type is the sources.scraper_type value handled by this scraper
+const Type = "codehawks"
+
+// contestTypePrivate labels audits run for a single client with invited auditors
+const contestTypePrivate = "private audit"
+
+var (
+	contestPathRe = regexp.MustCompile(`^/c/(\d{4}-\d{2}-[a-z0-9-]+)$`)
+	repositoryRe  = regexp.MustCompile(`^https://github\.com/(?:Cyfrin|CodeHawks-Contests)/[A-Za-z0-9_.-]+$`)
+)
+
+type Scraper struct{}
+
+func New() *Scraper {
+	return &Scraper{}
+}
+
+func (s *Scraper) Scrape(ctx context.Context, page *scraper.Page) (*scraper.Result, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	base, err := url.Parse(page.URL)
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	switch page.Stage {
+	case dto.ExtractStageRoot:
+		return s.scrapeIndex(doc, base), nil
+	case dto.ExtractStageLeaf:
+		return s.scrapeContest(doc, base)
+	default:
+		return nil, scraper.ErrUnexpectedStage(page.Stage)
+	}
+}
+
+// scrapeIndex collects the contest links; contests still running are
+// filtered out at the leaf stage, where the report link is missing
+func (s *Scraper) scrapeIndex(doc *goquery.Document, base *url.URL) *scraper.Result {
+	result := &scraper.Result{
+		Fingerprint: markup.Fingerprint(doc, "main"),
+	}
+
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		link, err := base.Parse(href)
+		if err != nil || link.Host != base.Host {
+			return
+		}
+
+		match := contestPathRe.FindStringSubmatch(link.Path)
+		if match == nil {
+			return
+		}
+
+		contestURL := markup.Resolve(base, link.Path)
+		if seen[contestURL] {
+			return
+		}
+		seen[contestURL] = true
+		result.Tasks = append(result.Tasks, scraper.Task{
+			Stage:    dto.ExtractStageLeaf,
+			URL:      contestURL,
+			Metadata: map[string]interface{}{"slug": match[1]},
+		})
+	})
+
+	return result
+}
+
+func (s *Scraper) scrapeContest(doc *goquery.Document, base *url.URL) (*scraper.Result, error) {
+	match := contestPathRe.FindStringSubmatch(base.Path)
+	if match == nil {
+		return nil, scraper.ErrParsePage(errUnexpectedContestURL(base.String()))
+	}
+
+	downloadURL := reportURL(doc, base, match[1])
+	if downloadURL == "" {
+		return &scraper.Result{}, nil
+	}
+
+	report := scraper.Report{
+		Title:          markup.Text(doc.Find("h1").First()),
+		DetailsPageURL: markup.Resolve(base, base.Path),
+		DownloadURL:    downloadURL,
+		EngagementType: auditreport.EngagementTypeCompetition,
+		ClientCompany:  definition(doc, "Sponsor"),
+		RepositoryURL:  repository(doc),
+		Summary:        markup.Text(doc.Find(".contest-description p").First()),
+		StartDate:      date(definition(doc, "Start date")),
+		EndDate:        date(definition(doc, "End date")),
+		Findings:       findingsSummary(doc),
+	}
+
+	// First flights are small public contests and stay competitions
+	if strings.EqualFold(markup.Text(doc.Find(".contest-type").First()), contestTypePrivate) {
+		report.EngagementType = auditreport.EngagementTypePrivate
+	}
+
+	return &scraper.Result{Reports: []scraper.Report{report}}, nil
+}
+
+// Private helper functions
+
+// reportURL returns the link to the published results of the contest
+func reportURL(doc *goquery.Document, base *url.URL, slug string) string {
+	resultsPath := "/c/" + slug + "/results"
+
+	var report string
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		link, err := base.Parse(href)
+		if err != nil || link.Host != base.Host || link.Path != resultsPath {
+			return true
+		}
+		report = link.String()
+		return false
+	})
+	return report
+}
+
+func repository(doc *goquery.Document) string {
+	var repo string
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		href = strings.TrimSuffix(href, "/")
+		if repositoryRe.MatchString(href) {
+			repo = href
+			return false
+		}
+		return true
+	})
+	return repo
+}
+
+// definition returns the value listed under label in the contest details
+func definition(doc *goquery.Document, label string) string {
+	var value string
+	doc.Find("dt").EachWithBreak(func(_ int, dt *goquery.Selection) bool {
+		if !strings.EqualFold(markup.Text(dt), label) {
+			return true
+		}
+		value = markup.Text(dt.NextFiltered("dd"))
+		return false
+	})
+	return value
+}
+
+// date parses "Jul 17, 2024"
+func date(text string) *time.Time {
+	t, err := time.Parse("Jan 2, 2006", text)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// findingsSummary reads the severity counts of the published results
+func findingsSummary(doc *goquery.Document) *auditreport.FindingsSummary {
+	high, highErr := strconv.Atoi(definition(doc, "High"))
+	medium, mediumErr := strconv.Atoi(definition(doc, "Medium"))
+	if highErr != nil || mediumErr != nil {
+		return nil
+	}
+
+	summary := &auditreport.FindingsSummary{High: high, Medium: medium}
+	summary.Low, _ = strconv.Atoi(definition(doc, "Low"))
+	return summary
+}
@@ -0,0 +1,80 @@
+package codehawks
+
+import (
+	"testing"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper/scrapertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeHawks_ScrapeIndex(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/contests.html", dto.ExtractStageRoot, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	// Links to other hosts are ignored, the results link is not a contest page
+	require.Len(t, result.Tasks, 2)
+	assert.Equal(t, dto.ExtractStageLeaf, result.Tasks[0].Stage)
+	assert.Equal(t, server.URL+"/c/2025-09-aave-umbrella", result.Tasks[0].URL)
+	assert.Equal(t, "2025-09-aave-umbrella", result.Tasks[0].Metadata["slug"])
+	assert.Equal(t, server.URL+"/c/2024-07-zaros", result.Tasks[1].URL)
+	assert.Empty(t, result.Reports)
+	assert.NotEmpty(t, result.Fingerprint)
+}
+
+func TestCodeHawks_ScrapeContest(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/c/2024-07-zaros", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	report := result.Reports[0]
+	require.NoError(t, report.Validate())
+	assert.Equal(t, "Zaros Part 1", report.Title)
+	assert.Equal(t, "Zaros", report.ClientCompany)
+	assert.Equal(t, server.URL+"/c/2024-07-zaros", report.DetailsPageURL)
+	assert.Equal(t, server.URL+"/c/2024-07-zaros/results?t=report", report.DownloadURL)
+	assert.Equal(t, auditreport.EngagementTypeCompetition, report.EngagementType)
+	assert.Equal(t, "https://github.com/Cyfrin/2024-07-zaros", report.RepositoryURL)
+	assert.Equal(t, "Zaros is a perpetuals DEX powered by Boosted (Re)Staking Vaults.", report.Summary)
+
+	require.NotNil(t, report.StartDate)
+	require.NotNil(t, report.EndDate)
+	assert.Equal(t, time.Date(2024, 7, 17, 0, 0, 0, 0, time.UTC), *report.StartDate)
+	assert.Equal(t, time.Date(2024, 8, 7, 0, 0, 0, 0, time.UTC), *report.EndDate)
+
+	require.NotNil(t, report.Findings)
+	assert.Equal(t, auditreport.FindingsSummary{High: 7, Medium: 11, Low: 24}, *report.Findings)
+}
+
+func TestCodeHawks_PrivateAudit(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/c/2024-05-private-vault", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	report := result.Reports[0]
+	assert.Equal(t, auditreport.EngagementTypePrivate, report.EngagementType)
+	assert.Equal(t, "Vault Labs", report.ClientCompany)
+	assert.Nil(t, report.Findings)
+}
+
+func TestCodeHawks_RunningContestHasNoReport(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/c/2025-09-aave-umbrella", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	assert.Empty(t, result.Reports)
+}
@@ -0,0 +1,7 @@
+package codehawks
+
+import "fmt"
+
+func errUnexpectedContestURL(url string) error {
+	return fmt.Errorf("not a CodeHawks contest URL: %s", url)
+}
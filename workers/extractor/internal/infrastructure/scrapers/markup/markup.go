@@ -0,0 +1,51 @@
+// Package markup holds the HTML helpers shared by the scraper implementations.
+package markup
+
+import (
+	"net/url"
+	"strings"
+
+	"extractor/internal/domain/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Fingerprint hashes the distinct tag signatures under the first element
+// matching selector (falling back to body), so new list entries do not change
+// it but a redesign does
+func Fingerprint(doc *goquery.Document, selector string) string {
+	root := doc.Find(selector).First()
+	if root.Length() == 0 {
+		root = doc.Find("body")
+	}
+
+	var skeleton strings.Builder
+	seen := make(map[string]bool)
+	root.Find("*").Each(func(_ int, node *goquery.Selection) {
+		signature := goquery.NodeName(node)
+		if class, ok := node.Attr("class"); ok {
+			signature += "." + class
+		}
+		if !seen[signature] {
+			seen[signature] = true
+			skeleton.WriteString(signature + ";")
+		}
+	})
+
+	return scraper.Fingerprint(skeleton.String())
+}
+
+// Resolve builds an absolute URL for path on the host of base
+func Resolve(base *url.URL, path string) string {
+	return (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: path}).String()
+}
+
+// NormalizeSpace collapses every run of whitespace into a single space
+func NormalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Text returns the normalized text of a selection
+func Text(selection *goquery.Selection) string {
+	return NormalizeSpace(selection.Text())
+}
@@ -0,0 +1,162 @@
+// Package sherlock scrapes the finished contests of the Sherlock audit API.
+//
+// The source points at the contests listing of the JSON API. The root stage
+// emits one leaf task per finished contest, and the leaf stage reads the
+// contest details, whose report field links to the published PDF.
+package sherlock
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper"
+	"extractor/internal/infrastructure/scrapers/markup"
+)
+
+// Type is the sources.scraper_type value handled by this scraper
+const Type = "sherlock"
+
+// statusFinished marks contests whose judging is over and whose report is out
+const statusFinished = "FINISHED"
+
+// contestList is the payload of /api/contests
+type contestList struct {
+	Items []json.RawMessage `json:"items"`
+}
+
+// contest holds the fields of /api/contests/{id} used by the scraper
+type contest struct {
+	ID               int64  `json:"id"`
+	Title            string `json:"title"`
+	ShortDescription string `json:"short_description"`
+	Status           string `json:"status"`
+	// Private marks collaborative audits run for a single client
+	Private          bool   `json:"private"`
+	StartsAt         int64  `json:"starts_at"`
+	EndsAt           int64  `json:"ends_at"`
+	Report           string `json:"report"`
+	TemplateRepoName string `json:"template_repo_name"`
+}
+
+type Scraper struct{}
+
+func New() *Scraper {
+	return &Scraper{}
+}
+
+func (s *Scraper) Scrape(ctx context.Context, page *scraper.Page) (*scraper.Result, error) {
+	base, err := url.Parse(page.URL)
+	if err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	switch page.Stage {
+	case dto.ExtractStageRoot:
+		return s.scrapeContests(page.Body, base)
+	case dto.ExtractStageLeaf:
+		return s.scrapeContest(page.Body, base)
+	default:
+		return nil, scraper.ErrUnexpectedStage(page.Stage)
+	}
+}
+
+// scrapeContests emits a leaf task for every finished contest of the listing
+func (s *Scraper) scrapeContests(body []byte, base *url.URL) (*scraper.Result, error) {
+	var list contestList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	result := &scraper.Result{}
+	for i, raw := range list.Items {
+		if i == 0 {
+			fingerprint, err := fingerprint(raw)
+			if err != nil {
+				return nil, scraper.ErrParsePage(err)
+			}
+			result.Fingerprint = fingerprint
+		}
+
+		var c contest
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return nil, scraper.ErrParsePage(err)
+		}
+		if c.Status != statusFinished {
+			continue
+		}
+
+		result.Tasks = append(result.Tasks, scraper.Task{
+			Stage:    dto.ExtractStageLeaf,
+			URL:      markup.Resolve(base, "/api/contests/"+strconv.FormatInt(c.ID, 10)),
+			Metadata: map[string]interface{}{"contest_id": c.ID},
+		})
+	}
+
+	return result, nil
+}
+
+// scrapeContest turns the details of a finished contest into a report
+func (s *Scraper) scrapeContest(body []byte, base *url.URL) (*scraper.Result, error) {
+	var c contest
+	if err := json.Unmarshal(body, &c); err != nil {
+		return nil, scraper.ErrParsePage(err)
+	}
+
+	// The report is published some time after the contest is finished
+	if c.Status != statusFinished || c.Report == "" {
+		return &scraper.Result{}, nil
+	}
+
+	report := scraper.Report{
+		Title:          markup.NormalizeSpace(c.Title),
+		DetailsPageURL: markup.Resolve(base, "/contests/"+strconv.FormatInt(c.ID, 10)),
+		DownloadURL:    c.Report,
+		EngagementType: auditreport.EngagementTypeCompetition,
+		// Contests are named after the protocol under audit
+		ClientCompany: markup.NormalizeSpace(c.Title),
+		Summary:       markup.NormalizeSpace(c.ShortDescription),
+		StartDate:     unixTime(c.StartsAt),
+		EndDate:       unixTime(c.EndsAt),
+	}
+	if c.Private {
+		report.EngagementType = auditreport.EngagementTypePrivate
+	}
+	if c.TemplateRepoName != "" {
+		report.RepositoryURL = "https://github.com/sherlock-audit/" + c.TemplateRepoName
+	}
+
+	return &scraper.Result{Reports: []scraper.Report{report}}, nil
+}
+
+// Private helper functions
+
+// fingerprint hashes the field names of a listing item, so an API change is detected
+func fingerprint(item json.RawMessage) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(item, &fields); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return scraper.Fingerprint(strings.Join(names, ";")), nil
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds <= 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
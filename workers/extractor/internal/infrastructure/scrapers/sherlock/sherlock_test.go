@@ -0,0 +1,75 @@
+package sherlock
+
+import (
+	"testing"
+	"time"
+
+	"extractor/internal/application/dto"
+	"extractor/internal/domain/entity/auditreport"
+	"extractor/internal/domain/scraper/scrapertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSherlock_ScrapeContests(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/contests.json", dto.ExtractStageRoot, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+
+	// Running and judging contests have no report yet
+	require.Len(t, result.Tasks, 2)
+	assert.Equal(t, dto.ExtractStageLeaf, result.Tasks[0].Stage)
+	assert.Equal(t, server.URL+"/api/contests/742", result.Tasks[0].URL)
+	assert.Equal(t, int64(742), result.Tasks[0].Metadata["contest_id"])
+	assert.Equal(t, server.URL+"/api/contests/760", result.Tasks[1].URL)
+	assert.Empty(t, result.Reports)
+	assert.NotEmpty(t, result.Fingerprint)
+}
+
+func TestSherlock_ScrapeContest(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/api/contests/742", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	report := result.Reports[0]
+	require.NoError(t, report.Validate())
+	assert.Equal(t, "Morpho Blue", report.Title)
+	assert.Equal(t, "Morpho Blue", report.ClientCompany)
+	assert.Equal(t, server.URL+"/contests/742", report.DetailsPageURL)
+	assert.Equal(t, "https://github.com/sherlock-protocol/sherlock-reports/raw/main/audits/2024.01.12%20-%20Final%20-%20Morpho%20Blue%20Audit%20Report.pdf", report.DownloadURL)
+	assert.Equal(t, auditreport.EngagementTypeCompetition, report.EngagementType)
+	assert.Equal(t, "https://github.com/sherlock-audit/2024-01-morpho-blue", report.RepositoryURL)
+	assert.Equal(t, "Noncustodial lending protocol with isolated markets.", report.Summary)
+
+	require.NotNil(t, report.StartDate)
+	require.NotNil(t, report.EndDate)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), *report.StartDate)
+	assert.Equal(t, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), *report.EndDate)
+}
+
+func TestSherlock_PrivateContestIsPrivateEngagement(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/api/contests/760", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	require.Len(t, result.Reports, 1)
+
+	assert.Equal(t, auditreport.EngagementTypePrivate, result.Reports[0].EngagementType)
+	assert.Empty(t, result.Reports[0].RepositoryURL)
+}
+
+func TestSherlock_ContestWithoutReportIsSkipped(t *testing.T) {
+	server := scrapertest.ServeFixtures(t, "testdata")
+	page := scrapertest.Fetch(t, server, "/api/contests/780", dto.ExtractStageLeaf, nil)
+
+	result, err := New().Scrape(t.Context(), page)
+	require.NoError(t, err)
+	assert.Empty(t, result.Reports)
+}
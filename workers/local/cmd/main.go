@@ -0,0 +1,219 @@
+// Command local runs several workers in one process, on one router: each
+// request goes to the handler of its type. The workers run are the roles of
+// the configuration (WORKER_ROLES), all of them by default.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	// Application layer
+	downloader "downloader/app"
+	extractor "extractor/app"
+	processor "processor/app"
+	"shared/application/ports"
+
+	// Infrastructure layer
+	"shared/infrastructure/api"
+	"shared/infrastructure/config"
+	"shared/infrastructure/database"
+	"shared/infrastructure/observability"
+	"shared/infrastructure/queue"
+	"shared/infrastructure/repository"
+	"shared/infrastructure/runtime"
+	"shared/infrastructure/storage"
+)
+
+func main() {
+	cfg := loadConfiguration()
+	obs := initializeObservability(cfg)
+	deps := initializeDependencies(cfg, obs)
+	initializeApplication(cfg, deps, obs)
+}
+
+// Dependencies holds all initialized infrastructure components
+type Dependencies struct {
+	storage      ports.Storage
+	database     ports.Database
+	repositories ports.Repositories
+	queue        ports.Queue
+}
+
+// role is a worker the local binary can run
+type role struct {
+	queue      func(config.QueueNames) string
+	eventTypes []string
+	newHandler func(*config.Config, *Dependencies, ports.Observability) (ports.Handler, error)
+}
+
+// roles are the workers of the local binary, by name in WORKER_ROLES
+var roles = map[string]role{
+	"download": {
+		queue:      func(queues config.QueueNames) string { return queues.Downloader },
+		eventTypes: downloader.EventTypes,
+		newHandler: func(cfg *config.Config, deps *Dependencies, obs ports.Observability) (ports.Handler, error) {
+			return downloader.NewHandler(cfg, downloader.Dependencies{
+				Storage:      deps.storage,
+				Database:     deps.database,
+				Repositories: deps.repositories,
+				Queue:        deps.queue,
+			}, obs)
+		},
+	},
+	"process": {
+		queue:      func(queues config.QueueNames) string { return queues.Processor },
+		eventTypes: processor.EventTypes,
+		newHandler: func(cfg *config.Config, deps *Dependencies, obs ports.Observability) (ports.Handler, error) {
+			return processor.NewHandler(cfg, processor.Dependencies{
+				Storage:      deps.storage,
+				Repositories: deps.repositories,
+			}, obs)
+		},
+	},
+	"extract": {
+		queue:      func(queues config.QueueNames) string { return queues.Extractor },
+		eventTypes: extractor.EventTypes,
+		newHandler: func(cfg *config.Config, deps *Dependencies, obs ports.Observability) (ports.Handler, error) {
+			return extractor.NewHandler(cfg, extractor.Dependencies{
+				Database:     deps.database,
+				Repositories: deps.repositories,
+				Queue:        deps.queue,
+			}, obs)
+		},
+	},
+}
+
+// loadConfiguration loads and validates the application configuration
+func loadConfiguration() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("configuration: %v", err)
+	}
+	return cfg
+}
+
+// initializeDependencies sets up all infrastructure dependencies
+func initializeDependencies(cfg *config.Config, obs ports.Observability) *Dependencies {
+	// Database initialization - component handles its own logging
+	db, err := database.CreateDB(cfg, obs)
+	if err != nil {
+		log.Fatalf("Failed to create database: %v", err)
+	}
+
+	// Test database connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.Ping(ctx); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Storage initialization
+	storageClient, err := storage.CreateStorage(cfg, obs)
+	if err != nil {
+		log.Fatalf("Failed to create storage: %v", err)
+	}
+
+	// Repositories
+	repositories, err := repository.NewRepositories(db, obs)
+	if err != nil {
+		log.Fatalf("Failed to create repositories: %v", err)
+	}
+
+	// Queue initialization - optional component
+	var publisher ports.Queue
+	if cfg.Adapters.Queue != "" {
+		publisher, err = queue.CreateQueue(cfg, obs)
+		if err != nil {
+			log.Fatalf("Failed to create queue: %v", err)
+		}
+	}
+
+	return &Dependencies{
+		storage:      storageClient,
+		database:     db,
+		repositories: repositories,
+		queue:        publisher,
+	}
+}
+
+// initializeObservability sets up logging and metrics infrastructure
+func initializeObservability(cfg *config.Config) ports.Observability {
+	obs, err := observability.CreateObservability(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize observability: %v", err)
+	}
+	return obs
+}
+
+// buildApplication registers the handler of every configured role on one
+// router, and creates the runtimes serving it
+func buildApplication(cfg *config.Config, deps *Dependencies, obs ports.Observability) ([]ports.Runtime, error) {
+	router, err := runtime.NewRouter(obs)
+	if err != nil {
+		return nil, fmt.Errorf("router creation: %w", err)
+	}
+
+	var queues []string
+	for _, name := range cfg.Roles {
+		role, ok := roles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown worker role: %s", name)
+		}
+		handler, err := role.newHandler(cfg, deps, obs)
+		if err != nil {
+			return nil, fmt.Errorf("%s handler creation: %w", name, err)
+		}
+		router.Route(handler, role.eventTypes...)
+		queues = append(queues, role.queue(cfg.Queue.Queues))
+	}
+
+	// Status and search queries, served by the HTTP runtime
+	statusAPI, err := api.NewStatusAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("status api creation: %w", err)
+	}
+	searchAPI, err := api.NewSearchAPI(deps.repositories, obs)
+	if err != nil {
+		return nil, fmt.Errorf("search api creation: %w", err)
+	}
+
+	// A RabbitMQ runtime consumes one queue: consume the queue of every role
+	if cfg.Adapters.Runtime != "rabbitmq" {
+		runtime, err := runtime.Create(cfg, router, obs, statusAPI, searchAPI)
+		if err != nil {
+			return nil, fmt.Errorf("runtime creation: %w", err)
+		}
+		return []ports.Runtime{runtime}, nil
+	}
+
+	runtimes := make([]ports.Runtime, 0, len(queues))
+	for _, queue := range queues {
+		queueCfg := *cfg
+		queueCfg.Queue.RuntimeQueueName = queue
+		runtime, err := runtime.Create(&queueCfg, router, obs)
+		if err != nil {
+			return nil, fmt.Errorf("runtime creation for %s: %w", queue, err)
+		}
+		runtimes = append(runtimes, runtime)
+	}
+	return runtimes, nil
+}
+
+func initializeApplication(cfg *config.Config, deps *Dependencies, obs ports.Observability) {
+	runtimes, err := buildApplication(cfg, deps, obs)
+	if err != nil {
+		log.Fatalf("error building the application: %v", err)
+	}
+
+	errs := make(chan error, len(runtimes))
+	for _, runtime := range runtimes {
+		go func() {
+			errs <- runtime.Start()
+		}()
+	}
+	if err := <-errs; err != nil {
+		log.Fatalf("runtime stopped: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Package app assembles the handler of the orchestrator, for its own binary.
+package app
+
+import (
+	"fmt"
+
+	"orchestrator/internal/application/handler"
+	"orchestrator/internal/application/ports"
+	"orchestrator/internal/application/usecase"
+	"shared/infrastructure/config"
+	"shared/infrastructure/outbox"
+	"shared/infrastructure/runtime"
+)
+
+// EventTypes are the types of the requests the orchestrator handles
+var EventTypes = []string{
+	runtime.ScheduleTickType,
+}
+
+// Dependencies holds the infrastructure the orchestrator is built on
+type Dependencies struct {
+	Database     ports.Database
+	Repositories ports.Repositories
+	Queue        ports.Queue
+}
+
+// NewHandler builds the orchestrator handler
+func NewHandler(cfg *config.Config, deps Dependencies, obs ports.Observability) (ports.Handler, error) {
+	relay, err := outbox.NewRelay(deps.Repositories.Outbox(), deps.Queue, obs)
+	if err != nil {
+		return nil, fmt.Errorf("outbox relay creation: %w", err)
+	}
+
+	scheduleWork, err := usecase.NewScheduleWork(
+		schedulePolicy(cfg.Schedule),
+		deps.Database,
+		deps.Queue,
+		relay,
+		cfg.Queue.Queues,
+		deps.Repositories,
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("usecase creation: %w", err)
+	}
+
+	return handler.NewTickHandler(scheduleWork, obs), nil
+}
+
+// schedulePolicy maps the schedule configuration onto the delays of the usecase
+func schedulePolicy(cfg config.ScheduleConfig) usecase.SchedulePolicy {
+	return usecase.SchedulePolicy{
+		CrawlInterval:      cfg.CrawlInterval,
+		CrawlLease:         cfg.CrawlLease,
+		RecheckInterval:    cfg.RecheckInterval,
+		PendingGracePeriod: cfg.PendingGracePeriod,
+		StuckTimeout:       cfg.StuckTimeout,
+		OutboxDelay:        cfg.OutboxDelay,
+		BatchSize:          cfg.BatchSize,
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+import shared "shared/application/dto"
+
+type (
+	ExtractRequest  = shared.ExtractRequest
+	DownloadRequest = shared.DownloadRequest
+	ProcessRequest  = shared.ProcessRequest
+)
+
+const (
+	ExtractStageRoot = shared.ExtractStageRoot
+
+	DownloadEventRequested = shared.DownloadEventRequested
+	DownloadEventRetry     = shared.DownloadEventRetry
+	DownloadEventRecheck   = shared.DownloadEventRecheck
+
+	ProcessEventRequested = shared.ProcessEventRequested
+
+	MetadataSourceID = shared.MetadataSourceID
+)
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"orchestrator/internal/application/ports"
+)
+
+func successResponse() ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: true,
+	}
+}
+
+func errorResponse(message string) ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   message,
+	}
+}
+
+func (h *TickHandler) handleTickSuccess(request ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	h.logger.Info("Scheduling pass completed", "request_id", request.ID)
+	return successResponse(), nil
+}
+
+func (h *TickHandler) handleTickError(request ports.RuntimeRequest, err error) (ports.RuntimeResponse, error) {
+	h.logger.Error("Scheduling pass failed",
+		"request_id", request.ID,
+		"error", err.Error())
+	return errorResponse(err.Error()), nil
+}
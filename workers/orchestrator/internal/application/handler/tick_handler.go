@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"orchestrator/internal/application/ports"
+	"orchestrator/internal/application/usecase"
+)
+
+// TickHandler runs the scheduling pass. Ticks carry no payload: they come
+// from the schedule runtime locally and from a scheduled rule on Lambda.
+type TickHandler struct {
+	usecase *usecase.ScheduleWork
+	logger  ports.Logger
+}
+
+func NewTickHandler(scheduleWork *usecase.ScheduleWork, obs ports.Observability) *TickHandler {
+	logger, _ := obs.LoggerScoped("handler.tick")
+	return &TickHandler{
+		usecase: scheduleWork,
+		logger:  logger,
+	}
+}
+
+func (h *TickHandler) Handle(ctx context.Context, request ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	if err := h.usecase.Tick(ctx); err != nil {
+		return h.handleTickError(request, err)
+	}
+
+	return h.handleTickSuccess(request)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	shared "shared/application/ports"
+)
+
+type (
+	Queue           = shared.Queue
+	QueueMessage    = shared.QueueMessage
+	Database        = shared.Database
+	Transaction     = shared.Transaction
+	Runtime         = shared.Runtime
+	Handler         = shared.Handler
+	Repositories    = shared.Repositories
+	Logger          = shared.Logger
+	Metrics         = shared.Metrics
+	Observability   = shared.Observability
+	RuntimeRequest  = shared.RuntimeRequest
+	RuntimeResponse = shared.RuntimeResponse
+)
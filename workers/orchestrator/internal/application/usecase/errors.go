@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"fmt"
+)
+
+func ErrListSourcesFailed(err error) error {
+	return fmt.Errorf("failed to list sources due for a visit: %w", err)
+}
+
+func ErrSourceUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update source: %w", err)
+}
+
+func ErrOutboxMessageFailed(err error) error {
+	return fmt.Errorf("failed to store extract event: %w", err)
+}
+
+func ErrListDownloadsFailed(err error) error {
+	return fmt.Errorf("failed to list downloads: %w", err)
+}
+
+func ErrDownloadUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update download: %w", err)
+}
+
+func ErrPublishDownloadEvent(err error) error {
+	return fmt.Errorf("failed to publish download event: %w", err)
+}
+
+func ErrListProcessesFailed(err error) error {
+	return fmt.Errorf("failed to list processes: %w", err)
+}
+
+func ErrProcessUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update process: %w", err)
+}
+
+func ErrPublishProcessEvent(err error) error {
+	return fmt.Errorf("failed to publish process event: %w", err)
+}
+
+func ErrRelayOutboxFailed(err error) error {
+	return fmt.Errorf("failed to relay outbox: %w", err)
+}
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchestrator/internal/application/dto"
+	"orchestrator/internal/application/ports"
+	"orchestrator/internal/domain/entity/download"
+	outboxPkg "orchestrator/internal/domain/entity/outbox"
+	"orchestrator/internal/domain/entity/process"
+	sourcePkg "orchestrator/internal/domain/entity/source"
+	"shared/infrastructure/config"
+	"shared/infrastructure/outbox"
+)
+
+// SchedulePolicy holds the delays that decide when work is due again, see
+// config.ScheduleConfig
+type SchedulePolicy struct {
+	// CrawlInterval is the time between two visits of a source index
+	CrawlInterval time.Duration
+	// CrawlLease is how long a scheduled crawl may take to visit the index
+	// before it is scheduled again
+	CrawlLease time.Duration
+	// RecheckInterval is the time between two revalidations of a downloaded file
+	RecheckInterval time.Duration
+	// PendingGracePeriod is how long a pending row may wait for its event
+	// before the event is assumed lost and published again
+	PendingGracePeriod time.Duration
+	// StuckTimeout is how long a download or a process may stay in progress
+	// before its worker is assumed dead; the configuration keeps it above the downloader
+	// timeout
+	StuckTimeout time.Duration
+	// OutboxDelay leaves a fresh outbox message to the worker that wrote it
+	// before relaying it from here
+	OutboxDelay time.Duration
+	// BatchSize caps the rows handled per step and tick
+	BatchSize int
+}
+
+type ScheduleWork struct {
+	policy       SchedulePolicy
+	database     ports.Database
+	queue        ports.Queue
+	relay        *outbox.Relay
+	queueNames   config.QueueNames
+	repositories ports.Repositories
+	logger       ports.Logger
+	metrics      ports.Metrics
+}
+
+func NewScheduleWork(
+	policy SchedulePolicy,
+	database ports.Database,
+	queue ports.Queue,
+	relay *outbox.Relay,
+	queueNames config.QueueNames,
+	repositories ports.Repositories,
+	obs ports.Observability,
+) (*ScheduleWork, error) {
+	logger, metrics, _ := obs.ComponentsScoped("usecase.schedule_work")
+	return &ScheduleWork{
+		policy:       policy,
+		database:     database,
+		queue:        queue,
+		relay:        relay,
+		queueNames:   queueNames,
+		repositories: repositories,
+		logger:       logger,
+		metrics:      metrics,
+	}, nil
+}
+
+// Tick runs every step once. Steps are independent, so a failing one does
+// not prevent the others from running.
+func (s *ScheduleWork) Tick(ctx context.Context) error {
+	now := time.Now()
+
+	// 1. Release downloads and processes whose worker died after claiming them
+	resetErr := errors.Join(s.resetStuckDownloads(ctx, now), s.resetStuckProcesses(ctx, now))
+
+	// 2. Crawl the sources not visited recently
+	crawlErr := s.scheduleCrawls(ctx, now)
+
+	// 3. Publish again the events of rows left pending, and of retries lost
+	// or delayed beyond what the queue supports
+	downloadsErr := s.requeuePendingDownloads(ctx, now)
+	retriesErr := s.requeueDueRetries(ctx, now)
+	processesErr := s.requeuePendingProcesses(ctx, now)
+
+	// 4. Relay the outbox messages their worker failed to publish
+	outboxErr := s.relayOutbox(ctx, now)
+
+	// 5. Ask whether the files downloaded long ago were revised
+	recheckErr := s.scheduleRechecks(ctx, now)
+
+	return errors.Join(resetErr, crawlErr, downloadsErr, retriesErr, processesErr, outboxErr, recheckErr)
+}
+
+func (s *ScheduleWork) resetStuckDownloads(ctx context.Context, now time.Time) error {
+	downloads, err := s.repositories.Download().GetStuckDownloads(ctx, now.Add(-s.policy.StuckTimeout), s.policy.BatchSize)
+	if err != nil {
+		return ErrListDownloadsFailed(err)
+	}
+
+	reset := 0
+	for _, dl := range downloads {
+		if err := dl.Abandon(fmt.Sprintf("no progress for %s, worker presumed dead", s.policy.StuckTimeout)); err != nil {
+			continue
+		}
+
+		// The worker may have finished since the row was read
+		updated, err := s.repositories.Download().UpdateFromStatus(ctx, dl, download.StatusInProgress)
+		if err != nil {
+			return ErrDownloadUpdateFailed(err)
+		}
+		if !updated {
+			continue
+		}
+		reset++
+
+		s.logger.Info("Reset stuck download",
+			"download_id", dl.ID,
+			"attempt_count", dl.AttemptCount,
+			"status", string(dl.Status))
+
+		if dl.Status != download.StatusPending {
+			continue
+		}
+		// A lost event is picked up again once the grace period is over
+		if err := s.publishDownload(ctx, dl, dto.DownloadEventRetry); err != nil {
+			s.logPublishError("downloader", dl.ID, err)
+		}
+	}
+
+	s.metrics.RecordGauge("orchestrator.stuck_downloads_reset", float64(reset), nil)
+	return nil
+}
+
+func (s *ScheduleWork) resetStuckProcesses(ctx context.Context, now time.Time) error {
+	processes, err := s.repositories.Process().GetStuckProcesses(ctx, now.Add(-s.policy.StuckTimeout), s.policy.BatchSize)
+	if err != nil {
+		return ErrListProcessesFailed(err)
+	}
+
+	reset := 0
+	for _, proc := range processes {
+		if err := proc.Abandon(fmt.Sprintf("no progress for %s, worker presumed dead", s.policy.StuckTimeout)); err != nil {
+			continue
+		}
+
+		// The worker may have finished since the row was read
+		updated, err := s.repositories.Process().UpdateFromStatus(ctx, proc, process.StatusInProgress)
+		if err != nil {
+			return ErrProcessUpdateFailed(err)
+		}
+		if !updated {
+			continue
+		}
+		reset++
+
+		s.logger.Info("Reset stuck process",
+			"process_id", proc.ID,
+			"attempt_count", proc.AttemptCount,
+			"status", string(proc.Status))
+
+		if proc.Status != process.StatusPending {
+			continue
+		}
+		// A lost event is picked up again once the grace period is over
+		if err := s.publishProcess(ctx, proc.ID); err != nil {
+			s.logPublishError("processor", proc.ID, err)
+		}
+	}
+
+	s.metrics.RecordGauge("orchestrator.stuck_processes_reset", float64(reset), nil)
+	return nil
+}
+
+// scheduleCrawls requests a crawl of the sources not visited recently. The
+// request is recorded on the source, so a crawl failing or still running is
+// not requested again before its lease is over.
+func (s *ScheduleWork) scheduleCrawls(ctx context.Context, now time.Time) error {
+	scheduledBefore := now.Add(-s.policy.CrawlLease)
+	sources, err := s.repositories.Source().ListDueForVisit(ctx, now.Add(-s.policy.CrawlInterval), scheduledBefore, s.policy.BatchSize)
+	if err != nil {
+		return ErrListSourcesFailed(err)
+	}
+
+	scheduled := 0
+	for _, source := range sources {
+		message, err := s.commitCrawl(ctx, source, now, scheduledBefore)
+		if err != nil {
+			return err
+		}
+		// Another tick scheduled it since it was listed
+		if message == nil {
+			continue
+		}
+		scheduled++
+
+		// Publish the event right away; on failure it stays in the outbox and
+		// is relayed on a later tick
+		if err := s.relay.Send(ctx, message); err != nil {
+			s.logPublishError("extractor", source.ID, err)
+		}
+	}
+
+	if scheduled > 0 {
+		s.logger.Info("Scheduled source crawls", "sources", scheduled)
+	}
+	s.metrics.RecordGauge("orchestrator.crawls_scheduled", float64(scheduled), nil)
+	return nil
+}
+
+// commitCrawl records the crawl of source and its root extract event in one
+// transaction. The message is nil when a crawl was scheduled since
+// scheduledBefore.
+func (s *ScheduleWork) commitCrawl(
+	ctx context.Context,
+	source *sourcePkg.Source,
+	now, scheduledBefore time.Time,
+) (*outboxPkg.Message, error) {
+	event := &dto.ExtractRequest{
+		EventID:   fmt.Sprintf("extract-%s-%d-%d", dto.ExtractStageRoot, source.ID, now.Unix()),
+		EventType: dto.ExtractStageRoot,
+		URL:       source.IndexPageURL,
+		Metadata:  map[string]interface{}{dto.MetadataSourceID: source.ID},
+		Timestamp: now,
+	}
+	msg, err := outboxPkg.NewMessage(s.queueNames.Extractor, event.EventType, event)
+	if err != nil {
+		return nil, ErrOutboxMessageFailed(err)
+	}
+
+	var message *outboxPkg.Message
+	err = s.database.Transaction(ctx, func(tx ports.Transaction) error {
+		repositories := s.repositories.WithTx(tx)
+
+		source.ScheduleCrawl(now)
+		scheduled, err := repositories.Source().ScheduleCrawl(ctx, source, scheduledBefore)
+		if err != nil {
+			return ErrSourceUpdateFailed(err)
+		}
+		if !scheduled {
+			return nil
+		}
+
+		if err := repositories.Outbox().Create(ctx, msg); err != nil {
+			return ErrOutboxMessageFailed(err)
+		}
+		message = msg
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (s *ScheduleWork) requeuePendingDownloads(ctx context.Context, now time.Time) error {
+	downloads, err := s.repositories.Download().GetPendingDownloads(ctx, now.Add(-s.policy.PendingGracePeriod), s.policy.BatchSize)
+	if err != nil {
+		return ErrListDownloadsFailed(err)
+	}
+
+	requeued, err := s.requeueDownloads(ctx, downloads, dto.DownloadEventRequested)
+	s.metrics.RecordGauge("orchestrator.downloads_requeued", float64(requeued), nil)
+	return err
+}
+
+// requeueDueRetries publishes the retries still not picked up a grace period
+// after they were due
+func (s *ScheduleWork) requeueDueRetries(ctx context.Context, now time.Time) error {
+	downloads, err := s.repositories.Download().GetDueRetries(ctx, now.Add(-s.policy.PendingGracePeriod), s.policy.BatchSize)
+	if err != nil {
+		return ErrListDownloadsFailed(err)
+	}
+
+	requeued, err := s.requeueDownloads(ctx, downloads, dto.DownloadEventRetry)
+	s.metrics.RecordGauge("orchestrator.retries_requeued", float64(requeued), nil)
+	return err
+}
+
+func (s *ScheduleWork) requeueDownloads(ctx context.Context, downloads []*download.Download, eventType string) (int, error) {
+	requeued := 0
+	for _, dl := range downloads {
+		if err := s.publishDownload(ctx, dl, eventType); err != nil {
+			s.logPublishError("downloader", dl.ID, err)
+			continue
+		}
+
+		// Restart the grace period; skipped when a worker took the row meanwhile
+		from := dl.Status
+		if err := dl.Requeue(); err != nil {
+			continue
+		}
+		if _, err := s.repositories.Download().UpdateFromStatus(ctx, dl, from); err != nil {
+			return requeued, ErrDownloadUpdateFailed(err)
+		}
+		requeued++
+	}
+	return requeued, nil
+}
+
+func (s *ScheduleWork) requeuePendingProcesses(ctx context.Context, now time.Time) error {
+	processes, err := s.repositories.Process().GetPendingProcesses(ctx, now.Add(-s.policy.PendingGracePeriod), s.policy.BatchSize)
+	if err != nil {
+		return ErrListProcessesFailed(err)
+	}
+
+	requeued := 0
+	for _, proc := range processes {
+		if err := s.publishProcess(ctx, proc.ID); err != nil {
+			s.logPublishError("processor", proc.ID, err)
+			continue
+		}
+
+		// Restart the grace period; skipped when a worker took the row meanwhile
+		if err := proc.Requeue(); err != nil {
+			continue
+		}
+		if _, err := s.repositories.Process().UpdateFromStatus(ctx, proc, process.StatusPending); err != nil {
+			return ErrProcessUpdateFailed(err)
+		}
+		requeued++
+	}
+
+	s.metrics.RecordGauge("orchestrator.processes_requeued", float64(requeued), nil)
+	return nil
+}
+
+// scheduleRechecks publishes a re-check for completed downloads not checked
+// recently. The downloader marks them checked, so a lost event is published
+// again on a later tick.
+func (s *ScheduleWork) scheduleRechecks(ctx context.Context, now time.Time) error {
+	downloads, err := s.repositories.Download().GetDueForRecheck(ctx, now.Add(-s.policy.RecheckInterval), s.policy.BatchSize)
+	if err != nil {
+		return ErrListDownloadsFailed(err)
+	}
+
+	scheduled := 0
+	for _, dl := range downloads {
+		if err := s.publishDownload(ctx, dl, dto.DownloadEventRecheck); err != nil {
+			s.logPublishError("downloader", dl.ID, err)
+			continue
+		}
+		scheduled++
+	}
+
+	s.metrics.RecordGauge("orchestrator.rechecks_scheduled", float64(scheduled), nil)
+	return nil
+}
+
+func (s *ScheduleWork) relayOutbox(ctx context.Context, now time.Time) error {
+	sent, err := s.relay.Drain(ctx, now.Add(-s.policy.OutboxDelay), s.policy.BatchSize)
+	s.metrics.RecordGauge("orchestrator.outbox_relayed", float64(sent), nil)
+	if err != nil {
+		return ErrRelayOutboxFailed(err)
+	}
+	return nil
+}
+
+func (s *ScheduleWork) publishDownload(ctx context.Context, dl *download.Download, eventType string) error {
+	event := &dto.DownloadRequest{
+		EventID:    fmt.Sprintf("download-%d-%d", dl.ID, time.Now().UnixNano()),
+		EventType:  eventType,
+		DownloadID: dl.ID,
+		Kind:       string(dl.Kind),
+		Timestamp:  time.Now(),
+	}
+
+	message := &ports.QueueMessage{
+		Target: s.queueNames.Downloader,
+		Body:   event,
+	}
+
+	if err := s.queue.Publish(ctx, message); err != nil {
+		return ErrPublishDownloadEvent(err)
+	}
+	return nil
+}
+
+func (s *ScheduleWork) publishProcess(ctx context.Context, processID int64) error {
+	event := &dto.ProcessRequest{
+		EventID:   fmt.Sprintf("process-%d-%d", processID, time.Now().UnixNano()),
+		EventType: dto.ProcessEventRequested,
+		ProcessID: processID,
+		Timestamp: time.Now(),
+	}
+
+	message := &ports.QueueMessage{
+		Target: s.queueNames.Processor,
+		Body:   event,
+	}
+
+	if err := s.queue.Publish(ctx, message); err != nil {
+		return ErrPublishProcessEvent(err)
+	}
+	return nil
+}
+
+func (s *ScheduleWork) logPublishError(target string, id int64, err error) {
+	s.logger.Error("Failed to publish event",
+		"target", target,
+		"id", id,
+		"error", err.Error())
+	s.metrics.IncrementCounter("orchestrator.publish_errors", map[string]string{"target": target})
+}
@@ -0,0 +1,501 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"orchestrator/internal/application/dto"
+	"orchestrator/internal/application/ports"
+	sharedPorts "shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/download"
+	"shared/domain/entity/process"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+	"shared/infrastructure/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDownloads selects downloads the way the queries of the repository do
+type fakeDownloads struct {
+	sharedPorts.DownloadRepository
+	rows map[int64]*entity.Download
+	// listed runs after a list, to change rows behind the back of the caller
+	listed func()
+}
+
+func (f *fakeDownloads) selectRows(match func(*entity.Download) bool, limit int) []*entity.Download {
+	var downloads []*entity.Download
+	for _, dl := range f.rows {
+		if match(dl) && len(downloads) < limit {
+			copied := *dl
+			downloads = append(downloads, &copied)
+		}
+	}
+	if f.listed != nil {
+		f.listed()
+	}
+	return downloads
+}
+
+func (f *fakeDownloads) GetStuckDownloads(ctx context.Context, startedBefore time.Time, limit int) ([]*entity.Download, error) {
+	return f.selectRows(func(dl *entity.Download) bool {
+		return dl.Status == download.StatusInProgress && dl.StartedAt.Before(startedBefore)
+	}, limit), nil
+}
+
+func (f *fakeDownloads) GetPendingDownloads(ctx context.Context, updatedBefore time.Time, limit int) ([]*entity.Download, error) {
+	return f.selectRows(func(dl *entity.Download) bool {
+		return dl.Status == download.StatusPending && dl.UpdatedAt.Before(updatedBefore)
+	}, limit), nil
+}
+
+func (f *fakeDownloads) GetDueRetries(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.Download, error) {
+	return f.selectRows(func(dl *entity.Download) bool {
+		return dl.CanRetry() && dl.NextAttemptAt != nil && dl.NextAttemptAt.Before(dueBefore)
+	}, limit), nil
+}
+
+func (f *fakeDownloads) GetDueForRecheck(ctx context.Context, checkedBefore time.Time, limit int) ([]*entity.Download, error) {
+	return f.selectRows(func(dl *entity.Download) bool {
+		return dl.Status == download.StatusCompleted && (dl.CheckedAt == nil || dl.CheckedAt.Before(checkedBefore))
+	}, limit), nil
+}
+
+func (f *fakeDownloads) UpdateFromStatus(ctx context.Context, dl *entity.Download, from download.Status) (bool, error) {
+	if f.rows[dl.ID].Status != from {
+		return false, nil
+	}
+	copied := *dl
+	f.rows[dl.ID] = &copied
+	return true, nil
+}
+
+type fakeProcesses struct {
+	sharedPorts.ProcessRepository
+	rows map[int64]*entity.Process
+	// listed runs after a list, to change rows behind the back of the caller
+	listed func()
+}
+
+func (f *fakeProcesses) GetPendingProcesses(ctx context.Context, updatedBefore time.Time, limit int) ([]*entity.Process, error) {
+	var processes []*entity.Process
+	for _, proc := range f.rows {
+		if proc.Status == process.StatusPending && proc.UpdatedAt.Before(updatedBefore) && len(processes) < limit {
+			copied := *proc
+			processes = append(processes, &copied)
+		}
+	}
+	return processes, nil
+}
+
+func (f *fakeProcesses) GetStuckProcesses(ctx context.Context, startedBefore time.Time, limit int) ([]*entity.Process, error) {
+	var processes []*entity.Process
+	for _, proc := range f.rows {
+		if proc.Status == process.StatusInProgress && proc.StartedAt != nil && proc.StartedAt.Before(startedBefore) && len(processes) < limit {
+			copied := *proc
+			processes = append(processes, &copied)
+		}
+	}
+	if f.listed != nil {
+		f.listed()
+	}
+	return processes, nil
+}
+
+func (f *fakeProcesses) UpdateFromStatus(ctx context.Context, proc *entity.Process, from process.ProcessStatus) (bool, error) {
+	if f.rows[proc.ID].Status != from {
+		return false, nil
+	}
+	copied := *proc
+	f.rows[proc.ID] = &copied
+	return true, nil
+}
+
+type fakeSources struct {
+	sharedPorts.SourceRepository
+	rows []*entity.Source
+}
+
+func notScheduledSince(source *entity.Source, scheduledBefore time.Time) bool {
+	return source.CrawlScheduledAt == nil || source.CrawlScheduledAt.Before(scheduledBefore)
+}
+
+func (f *fakeSources) ListDueForVisit(ctx context.Context, visitedBefore, scheduledBefore time.Time, limit int) ([]*entity.Source, error) {
+	var sources []*entity.Source
+	for _, source := range f.rows {
+		if source.IsActive && source.ScraperType != nil &&
+			(source.LastVisitedAt == nil || source.LastVisitedAt.Before(visitedBefore)) &&
+			notScheduledSince(source, scheduledBefore) && len(sources) < limit {
+			copied := *source
+			sources = append(sources, &copied)
+		}
+	}
+	return sources, nil
+}
+
+func (f *fakeSources) ScheduleCrawl(ctx context.Context, source *entity.Source, scheduledBefore time.Time) (bool, error) {
+	for i, row := range f.rows {
+		if row.ID == source.ID && notScheduledSince(row, scheduledBefore) {
+			copied := *source
+			f.rows[i] = &copied
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeOutbox stores the messages recorded by the transactions
+type fakeOutbox struct {
+	sharedPorts.OutboxRepository
+	messages []*entity.OutboxMessage
+}
+
+func (f *fakeOutbox) Create(ctx context.Context, message *entity.OutboxMessage) error {
+	message.ID = int64(len(f.messages) + 1)
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+func (f *fakeOutbox) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	return nil
+}
+
+func (f *fakeOutbox) GetUnpublished(ctx context.Context, createdBefore time.Time, limit int) ([]*entity.OutboxMessage, error) {
+	var messages []*entity.OutboxMessage
+	for _, message := range f.messages {
+		if !message.IsPublished() && message.CreatedAt.Before(createdBefore) && len(messages) < limit {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// fakeDatabase runs transactions straight on the fake repositories
+type fakeDatabase struct {
+	ports.Database
+}
+
+func (f *fakeDatabase) Transaction(ctx context.Context, fn func(tx ports.Transaction) error) error {
+	return fn(nil)
+}
+
+type fakeRepositories struct {
+	sharedPorts.Repositories
+	downloads *fakeDownloads
+	processes *fakeProcesses
+	sources   *fakeSources
+	outbox    *fakeOutbox
+}
+
+func (f *fakeRepositories) Download() sharedPorts.DownloadRepository { return f.downloads }
+func (f *fakeRepositories) Process() sharedPorts.ProcessRepository   { return f.processes }
+func (f *fakeRepositories) Source() sharedPorts.SourceRepository     { return f.sources }
+func (f *fakeRepositories) Outbox() sharedPorts.OutboxRepository     { return f.outbox }
+
+func (f *fakeRepositories) WithTx(tx sharedPorts.Transaction) sharedPorts.Repositories { return f }
+
+// fakeQueue records the messages published, by target
+type fakeQueue struct {
+	published map[string][]*ports.QueueMessage
+	failing   map[string]bool
+}
+
+func (f *fakeQueue) Publish(ctx context.Context, message *ports.QueueMessage) error {
+	if f.failing[message.Target] {
+		return errors.New("broker unavailable")
+	}
+	f.published[message.Target] = append(f.published[message.Target], message)
+	return nil
+}
+
+func (f *fakeQueue) PublishBatch(ctx context.Context, messages []*ports.QueueMessage) error {
+	for _, message := range messages {
+		if err := f.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var testPolicy = SchedulePolicy{
+	CrawlInterval:      24 * time.Hour,
+	CrawlLease:         time.Hour,
+	RecheckInterval:    7 * 24 * time.Hour,
+	PendingGracePeriod: 10 * time.Minute,
+	StuckTimeout:       15 * time.Minute,
+	OutboxDelay:        time.Minute,
+	BatchSize:          100,
+}
+
+var testQueues = config.QueueNames{
+	Downloader: "downloader",
+	Processor:  "processor",
+	Extractor:  "extractor",
+}
+
+type scheduleFixture struct {
+	usecase      *ScheduleWork
+	queue        *fakeQueue
+	repositories *fakeRepositories
+}
+
+func newScheduleFixture(t *testing.T) *scheduleFixture {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	queue := &fakeQueue{
+		published: make(map[string][]*ports.QueueMessage),
+		failing:   make(map[string]bool),
+	}
+	repositories := &fakeRepositories{
+		downloads: &fakeDownloads{rows: make(map[int64]*entity.Download)},
+		processes: &fakeProcesses{rows: make(map[int64]*entity.Process)},
+		sources:   &fakeSources{},
+		outbox:    &fakeOutbox{},
+	}
+	relay, err := outbox.NewRelay(repositories.outbox, queue, obs)
+	require.NoError(t, err)
+
+	usecase, err := NewScheduleWork(testPolicy, &fakeDatabase{}, queue, relay, testQueues, repositories, obs)
+	require.NoError(t, err)
+	return &scheduleFixture{usecase: usecase, queue: queue, repositories: repositories}
+}
+
+func (f *scheduleFixture) addDownload(dl *entity.Download) {
+	f.repositories.downloads.rows[dl.ID] = dl
+}
+
+func (f *scheduleFixture) addProcess(proc *entity.Process) {
+	f.repositories.processes.rows[proc.ID] = proc
+}
+
+// publishedDownloads returns the events published to the downloader, by download ID
+func (f *scheduleFixture) publishedDownloads(t *testing.T) map[int64]string {
+	t.Helper()
+	events := make(map[int64]string)
+	for _, message := range f.queue.published[testQueues.Downloader] {
+		event, ok := message.Body.(*dto.DownloadRequest)
+		require.True(t, ok)
+		events[event.DownloadID] = event.EventType
+	}
+	return events
+}
+
+// publishedCrawls returns the root extract events published, URL by source ID
+func (f *scheduleFixture) publishedCrawls(t *testing.T) map[int64]string {
+	t.Helper()
+	crawls := make(map[int64]string)
+	for _, message := range f.queue.published[testQueues.Extractor] {
+		var event dto.ExtractRequest
+		require.NoError(t, json.Unmarshal(message.Body.(json.RawMessage), &event))
+		assert.Equal(t, dto.ExtractStageRoot, event.EventType)
+		assert.NotEmpty(t, event.EventID)
+		sourceID, ok := event.Metadata[dto.MetadataSourceID].(float64)
+		require.True(t, ok)
+		crawls[int64(sourceID)] = event.URL
+	}
+	return crawls
+}
+
+func ago(d time.Duration) *time.Time {
+	at := time.Now().Add(-d)
+	return &at
+}
+
+func TestTick_ResetsStuckDownloads(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.addDownload(&entity.Download{ID: 1, Status: download.StatusInProgress, AttemptCount: 1, StartedAt: ago(20 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 2, Status: download.StatusInProgress, AttemptCount: 1, StartedAt: ago(5 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 3, Status: download.StatusInProgress, AttemptCount: 3, StartedAt: ago(20 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 4, Status: download.StatusInProgress, AttemptCount: 1, StartedAt: ago(20 * time.Minute)})
+
+	// Download 4 completes once listed, before it is reset
+	f.repositories.downloads.listed = func() {
+		if row := f.repositories.downloads.rows[4]; row.Status == download.StatusInProgress {
+			row.Status = download.StatusCompleted
+			row.CheckedAt = ago(0)
+		}
+	}
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	rows := f.repositories.downloads.rows
+	assert.Equal(t, download.StatusPending, rows[1].Status, "past the stuck timeout")
+	assert.NotNil(t, rows[1].ErrorMessage)
+	assert.Equal(t, download.StatusInProgress, rows[2].Status, "within the stuck timeout")
+	assert.Equal(t, download.StatusFailed, rows[3].Status, "no attempt left")
+	assert.Equal(t, download.StatusCompleted, rows[4].Status, "finished meanwhile")
+
+	assert.Equal(t, map[int64]string{1: dto.DownloadEventRetry}, f.publishedDownloads(t))
+}
+
+func TestTick_ResetsStuckProcesses(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.addProcess(&entity.Process{ID: 1, Status: process.StatusInProgress, AttemptCount: 1, StartedAt: ago(20 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 2, Status: process.StatusInProgress, AttemptCount: 1, StartedAt: ago(5 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 3, Status: process.StatusInProgress, AttemptCount: 3, StartedAt: ago(20 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 4, Status: process.StatusInProgress, AttemptCount: 1, StartedAt: ago(20 * time.Minute)})
+
+	// Process 4 completes once listed, before it is reset
+	f.repositories.processes.listed = func() {
+		if row := f.repositories.processes.rows[4]; row.Status == process.StatusInProgress {
+			row.Status = process.StatusCompleted
+		}
+	}
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	rows := f.repositories.processes.rows
+	assert.Equal(t, process.StatusPending, rows[1].Status, "past the stuck timeout")
+	require.NotNil(t, rows[1].ErrorMessage)
+	assert.Contains(t, *rows[1].ErrorMessage, "worker presumed dead")
+	assert.Equal(t, 1, rows[1].AttemptCount, "the attempt still counts")
+	assert.Equal(t, process.StatusInProgress, rows[2].Status, "within the stuck timeout")
+	assert.Equal(t, process.StatusFailed, rows[3].Status, "no attempt left")
+	assert.Equal(t, process.StatusCompleted, rows[4].Status, "finished meanwhile")
+
+	messages := f.queue.published[testQueues.Processor]
+	require.Len(t, messages, 1)
+	event, ok := messages[0].Body.(*dto.ProcessRequest)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), event.ProcessID)
+}
+
+func TestTick_SchedulesStaleSources(t *testing.T) {
+	scraper := "code4rena"
+	f := newScheduleFixture(t)
+	f.repositories.sources.rows = []*entity.Source{
+		{ID: 1, IndexPageURL: "https://audits.example.com/never", ScraperType: &scraper, IsActive: true},
+		{ID: 2, IndexPageURL: "https://audits.example.com/stale", ScraperType: &scraper, IsActive: true, LastVisitedAt: ago(25 * time.Hour)},
+		{ID: 3, IndexPageURL: "https://audits.example.com/fresh", ScraperType: &scraper, IsActive: true, LastVisitedAt: ago(time.Hour)},
+		{ID: 4, IndexPageURL: "https://audits.example.com/inactive", ScraperType: &scraper, LastVisitedAt: ago(25 * time.Hour)},
+		{ID: 5, IndexPageURL: "https://audits.example.com/no-scraper", IsActive: true},
+		{ID: 6, IndexPageURL: "https://audits.example.com/crawling", ScraperType: &scraper, IsActive: true, CrawlScheduledAt: ago(10 * time.Minute)},
+		{ID: 7, IndexPageURL: "https://audits.example.com/failed", ScraperType: &scraper, IsActive: true, CrawlScheduledAt: ago(2 * time.Hour)},
+	}
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	assert.Equal(t, map[int64]string{
+		1: "https://audits.example.com/never",
+		2: "https://audits.example.com/stale",
+		7: "https://audits.example.com/failed",
+	}, f.publishedCrawls(t), "crawls still within their lease are left alone")
+
+	for _, source := range f.repositories.sources.rows {
+		if source.ID == 1 || source.ID == 2 || source.ID == 7 {
+			require.NotNil(t, source.CrawlScheduledAt)
+			assert.WithinDuration(t, time.Now(), *source.CrawlScheduledAt, time.Second)
+		}
+	}
+	for _, message := range f.repositories.outbox.messages {
+		assert.True(t, message.IsPublished())
+	}
+
+	// The crawls are not visited yet, the next tick does not request them again
+	require.NoError(t, f.usecase.Tick(context.Background()))
+	assert.Len(t, f.queue.published[testQueues.Extractor], 3)
+	assert.Len(t, f.repositories.outbox.messages, 3)
+}
+
+func TestTick_SchedulesSourcesInBatches(t *testing.T) {
+	scraper := "code4rena"
+	f := newScheduleFixture(t)
+	f.usecase.policy.BatchSize = 2
+	for id := int64(1); id <= 3; id++ {
+		f.repositories.sources.rows = append(f.repositories.sources.rows,
+			&entity.Source{ID: id, IndexPageURL: fmt.Sprintf("https://audits.example.com/%d", id), ScraperType: &scraper, IsActive: true})
+	}
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+	assert.Len(t, f.publishedCrawls(t), 2)
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+	assert.Len(t, f.publishedCrawls(t), 3)
+}
+
+func TestTick_RequeuesDueRetries(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.addDownload(&entity.Download{ID: 1, Status: download.StatusFailed, AttemptCount: 1, NextAttemptAt: ago(20 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 2, Status: download.StatusFailed, AttemptCount: 1, NextAttemptAt: ago(5 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 3, Status: download.StatusFailed, AttemptCount: 3, NextAttemptAt: ago(20 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 4, Status: download.StatusPending, UpdatedAt: *ago(20 * time.Minute)})
+	f.addDownload(&entity.Download{ID: 5, Status: download.StatusPending, UpdatedAt: *ago(5 * time.Minute)})
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	assert.Equal(t, map[int64]string{
+		1: dto.DownloadEventRetry,
+		4: dto.DownloadEventRequested,
+	}, f.publishedDownloads(t))
+
+	rows := f.repositories.downloads.rows
+	assert.WithinDuration(t, time.Now(), *rows[1].NextAttemptAt, time.Second, "the grace period starts over")
+	assert.Equal(t, download.StatusFailed, rows[1].Status, "the downloader takes the retry")
+	assert.WithinDuration(t, time.Now(), rows[4].UpdatedAt, time.Second)
+	assert.WithinDuration(t, time.Now().Add(-5*time.Minute), *rows[2].NextAttemptAt, time.Second)
+}
+
+func TestTick_RequeuesPendingProcesses(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.addProcess(&entity.Process{ID: 1, Status: process.StatusPending, UpdatedAt: *ago(20 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 2, Status: process.StatusPending, UpdatedAt: *ago(5 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 3, Status: process.StatusInProgress, UpdatedAt: *ago(20 * time.Minute)})
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	messages := f.queue.published[testQueues.Processor]
+	require.Len(t, messages, 1)
+	event, ok := messages[0].Body.(*dto.ProcessRequest)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), event.ProcessID)
+	assert.Equal(t, dto.ProcessEventRequested, event.EventType)
+
+	rows := f.repositories.processes.rows
+	assert.WithinDuration(t, time.Now(), rows[1].UpdatedAt, time.Second, "the grace period starts over")
+	assert.WithinDuration(t, time.Now().Add(-5*time.Minute), rows[2].UpdatedAt, time.Second)
+}
+
+func TestTick_PublishFailure(t *testing.T) {
+	scraper := "code4rena"
+	f := newScheduleFixture(t)
+	f.queue.failing[testQueues.Downloader] = true
+	f.queue.failing[testQueues.Extractor] = true
+	f.repositories.sources.rows = []*entity.Source{
+		{ID: 1, IndexPageURL: "https://audits.example.com/never", ScraperType: &scraper, IsActive: true},
+	}
+	f.addDownload(&entity.Download{ID: 1, Status: download.StatusFailed, AttemptCount: 1, NextAttemptAt: ago(20 * time.Minute)})
+	f.addProcess(&entity.Process{ID: 1, Status: process.StatusPending, UpdatedAt: *ago(20 * time.Minute)})
+
+	require.NoError(t, f.usecase.Tick(context.Background()))
+
+	// The crawl is scheduled, its event left in the outbox for the relay
+	require.Len(t, f.repositories.outbox.messages, 1)
+	message := f.repositories.outbox.messages[0]
+	assert.False(t, message.IsPublished())
+	assert.Equal(t, testQueues.Extractor, message.Target)
+	assert.NotNil(t, f.repositories.sources.rows[0].CrawlScheduledAt)
+
+	// The retry keeps its due time so that the next tick publishes it again
+	assert.WithinDuration(t, time.Now().Add(-20*time.Minute), *f.repositories.downloads.rows[1].NextAttemptAt, time.Second)
+
+	// The other steps still ran
+	assert.Len(t, f.queue.published[testQueues.Processor], 1)
+
+	// Once the broker is back, the relay publishes the crawl
+	f.queue.failing[testQueues.Extractor] = false
+	message.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, f.usecase.Tick(context.Background()))
+	assert.Equal(t, map[int64]string{1: "https://audits.example.com/never"}, f.publishedCrawls(t))
+}
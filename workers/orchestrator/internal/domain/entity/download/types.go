@@ -0,0 +1,12 @@
+package download
+
+import "shared/domain/entity/download"
+
+type (
+	Download = download.Download
+)
+
+const (
+	StatusPending    = download.StatusPending
+	StatusInProgress = download.StatusInProgress
+)
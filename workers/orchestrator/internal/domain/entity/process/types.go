@@ -0,0 +1,13 @@
+package process
+
+import "shared/domain/entity/process"
+
+type (
+	Process = process.Process
+)
+
+const (
+	StatusPending    = process.StatusPending
+	StatusInProgress = process.StatusInProgress
+	StatusFailed     = process.StatusFailed
+)
@@ -0,0 +1,7 @@
+package source
+
+import "shared/domain/entity/source"
+
+type (
+	Source = source.Source
+)
@@ -0,0 +1,44 @@
+// Package app assembles the handler of the processor, for its own binary and
+// for the local binary running several workers in one process.
+package app
+
+import (
+	"fmt"
+
+	"processor/internal/application/dto"
+	"processor/internal/application/handler"
+	"processor/internal/application/ports"
+	"processor/internal/application/usecase"
+	"processor/internal/domain/service"
+	"shared/infrastructure/config"
+)
+
+// EventTypes are the types of the requests the processor handles
+var EventTypes = []string{
+	dto.ProcessEventRequested,
+}
+
+// Dependencies holds the infrastructure the processor is built on
+type Dependencies struct {
+	Storage      ports.Storage
+	Repositories ports.Repositories
+}
+
+// NewHandler builds the processor handler
+func NewHandler(cfg *config.Config, deps Dependencies, obs ports.Observability) (ports.Handler, error) {
+	extractService := service.NewExtractService(
+		100 * 1024 * 1024, // same limit the downloader applies
+	)
+
+	processFile, err := usecase.NewProcessFile(
+		extractService,
+		deps.Storage,
+		deps.Repositories,
+		obs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("usecase creation: %w", err)
+	}
+
+	return handler.NewProcessHandler(processFile, obs), nil
+}
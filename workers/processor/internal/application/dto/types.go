@@ -0,0 +1,9 @@
+package dto
+
+import shared "shared/application/dto"
+
+type (
+	ProcessRequest = shared.ProcessRequest
+)
+
+const ProcessEventRequested = shared.ProcessEventRequested
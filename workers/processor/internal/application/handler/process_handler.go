@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"processor/internal/application/dto"
+	"processor/internal/application/ports"
+	"processor/internal/application/usecase"
+)
+
+type ProcessHandler struct {
+	usecase *usecase.ProcessFile
+	logger  ports.Logger
+}
+
+func NewProcessHandler(processFile *usecase.ProcessFile, obs ports.Observability) *ProcessHandler {
+	logger, _ := obs.LoggerScoped("handler.process")
+	return &ProcessHandler{
+		usecase: processFile,
+		logger:  logger,
+	}
+}
+
+func (h *ProcessHandler) Handle(ctx context.Context, request ports.RuntimeRequest) (ports.RuntimeResponse, error) {
+	// Parse request
+	processReq, err := h.parseRequest(request)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	// Validate
+	if err := processReq.Validate(); err != nil {
+		return h.handleError(ErrHandlerInvalidPayload(err))
+	}
+
+	// Process
+	if err := h.usecase.Process(ctx, processReq); err != nil {
+		return h.handleProcessError(processReq.ProcessID, err)
+	}
+
+	return h.handleProcessSuccess()
+}
+
+func (h *ProcessHandler) parseRequest(request ports.RuntimeRequest) (*dto.ProcessRequest, error) {
+	var processReq dto.ProcessRequest
+	if err := request.Unmarshal(&processReq); err != nil {
+		return nil, ErrHandlerUnmarshal(err)
+	}
+	return &processReq, nil
+}
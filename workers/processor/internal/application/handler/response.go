@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"processor/internal/domain/entity/process"
+	"shared/application/ports"
+)
+
+func successResponse() ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: true,
+	}
+}
+
+func errorResponse(message string) ports.RuntimeResponse {
+	return ports.RuntimeResponse{
+		Success: false,
+		Error:   message,
+	}
+}
+
+func (h *ProcessHandler) handleProcessSuccess() (ports.RuntimeResponse, error) {
+	h.logger.Info("Process successfully completed!")
+	return successResponse(), nil
+}
+
+func (h *ProcessHandler) handleProcessError(processID int64, err error) (ports.RuntimeResponse, error) {
+	switch {
+	case errors.Is(err, process.ErrAlreadyCompleted):
+		h.logger.Info("Process already completed")
+		return successResponse(), nil
+
+	case errors.Is(err, process.ErrClaimedElsewhere):
+		h.logger.Info("Process claimed by another worker", "process_id", processID)
+		return successResponse(), nil
+
+	default:
+		h.logger.Error("Process failed",
+			"process_id", processID,
+			"error", err.Error())
+		return errorResponse(err.Error()), nil
+	}
+}
+
+func (h *ProcessHandler) handleError(err error) (ports.RuntimeResponse, error) {
+	h.logger.Error("Process failed", "error", err.Error())
+	return errorResponse(err.Error()), nil
+}
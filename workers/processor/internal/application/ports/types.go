@@ -0,0 +1,19 @@
+package ports
+
+import (
+	shared "shared/application/ports"
+)
+
+type (
+	Storage         = shared.Storage
+	ObjectMetadata  = shared.ObjectMetadata
+	Database        = shared.Database
+	Runtime         = shared.Runtime
+	Handler         = shared.Handler
+	Repositories    = shared.Repositories
+	Logger          = shared.Logger
+	Metrics         = shared.Metrics
+	Observability   = shared.Observability
+	RuntimeRequest  = shared.RuntimeRequest
+	RuntimeResponse = shared.RuntimeResponse
+)
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDownloadNotCompleted = errors.New("download has no stored artifact")
+)
+
+func ErrProcessNotFound(err error) error {
+	return fmt.Errorf("failed to get process: %w", err)
+}
+
+func ErrProcessUpdateFailed(err error) error {
+	return fmt.Errorf("failed to update process status: %w", err)
+}
+
+func ErrDownloadNotFound(err error) error {
+	return fmt.Errorf("failed to get download: %w", err)
+}
+
+func ErrArtifactLoadFailed(err error) error {
+	return fmt.Errorf("failed to load artifact: %w", err)
+}
+
+func ErrExtractionFailed(err error) error {
+	return fmt.Errorf("failed to extract text: %w", err)
+}
+
+func ErrReportDetailSaveFailed(err error) error {
+	return fmt.Errorf("failed to save report detail: %w", err)
+}
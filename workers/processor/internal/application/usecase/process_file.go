@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"processor/internal/application/dto"
+	"processor/internal/application/ports"
+	"processor/internal/domain/entity/auditreportdetail"
+	processPkg "processor/internal/domain/entity/process"
+	"processor/internal/domain/service"
+)
+
+type ProcessFile struct {
+	extractService *service.ExtractService
+	storage        ports.Storage
+	repositories   ports.Repositories
+	logger         ports.Logger
+	metrics        ports.Metrics
+}
+
+func NewProcessFile(
+	extractService *service.ExtractService,
+	storage ports.Storage,
+	repositories ports.Repositories,
+	obs ports.Observability,
+) (*ProcessFile, error) {
+	logger, metrics, _ := obs.ComponentsScoped("usecase.process_file")
+	return &ProcessFile{
+		extractService: extractService,
+		storage:        storage,
+		repositories:   repositories,
+		logger:         logger,
+		metrics:        metrics,
+	}, nil
+}
+
+func (p *ProcessFile) Process(ctx context.Context, req *dto.ProcessRequest) error {
+	// 1. Query process record from database
+	process, err := p.repositories.Process().Get(ctx, req.ProcessID)
+	if err != nil {
+		return ErrProcessNotFound(err)
+	}
+
+	// 2. Check current status (skip if completed)
+	if process.IsCompleted() {
+		return processPkg.ErrAlreadyCompleted
+	}
+
+	// 3. Check if can start (business rule in entity)
+	if !process.CanStart() {
+		if process.HasExceededMaxAttempts() {
+			return processPkg.ErrMaxAttemptsExceeded
+		}
+		return processPkg.ErrInvalidStateTransition
+	}
+
+	// 4. Claim the process so no other worker picks it up; a duplicate
+	// delivery racing with us loses here with ErrClaimedElsewhere
+	process, err = p.repositories.Process().Claim(ctx, process.ID)
+	if err != nil {
+		if errors.Is(err, processPkg.ErrClaimedElsewhere) {
+			return err
+		}
+		return ErrProcessUpdateFailed(err)
+	}
+
+	// 5. Query the download that produced the artifact
+	download, err := p.repositories.Download().Get(ctx, process.DownloadID)
+	if err != nil {
+		return p.commitProcessFailWithError(ctx, process, ErrDownloadNotFound(err))
+	}
+	if !download.IsCompleted() || download.StoragePath == nil {
+		return p.commitProcessFailWithError(ctx, process, ErrDownloadNotCompleted)
+	}
+
+	// 6. Load the stored artifact
+	reader, metadata, err := p.storage.GetWithMetadata(ctx, "", *download.StoragePath)
+	if err != nil {
+		return p.commitProcessFailWithError(ctx, process, ErrArtifactLoadFailed(err))
+	}
+	defer reader.Close()
+
+	extension := ""
+	if download.FileExtension != nil {
+		extension = *download.FileExtension
+	}
+
+	// 7. Extract plain text
+	text, err := p.extractService.Extract(ctx, reader, metadata.ContentType, extension)
+	if err != nil {
+		return p.commitProcessFailWithError(ctx, process, ErrExtractionFailed(err))
+	}
+
+	// 8. Store the text alongside the report
+	detail := auditreportdetail.NewAuditReportDetail(download.ReportID)
+	detail.SetFullSummary(text)
+	if err := p.repositories.AuditReportDetail().Upsert(ctx, detail); err != nil {
+		return p.commitProcessFailWithError(ctx, process, ErrReportDetailSaveFailed(err))
+	}
+
+	// 9. Update process record with the version that produced the text
+	if err := process.Complete(p.extractService.Version()); err != nil {
+		// This should never happen, so we don't need a custom error for it
+		return err
+	}
+
+	if err := p.repositories.Process().Update(ctx, process); err != nil {
+		return ErrProcessUpdateFailed(err)
+	}
+
+	p.metrics.RecordHistogram("process.text_length", float64(len(text)), nil)
+	p.logger.Info("Text extracted",
+		"process_id", process.ID,
+		"report_id", download.ReportID,
+		"characters", len(text))
+
+	return nil
+}
+
+func (p *ProcessFile) commitProcessFailWithError(
+	ctx context.Context,
+	process *processPkg.Process,
+	err error,
+) error {
+	if err := process.Fail(err.Error()); err != nil {
+		return err
+	}
+
+	if err := p.repositories.Process().Update(ctx, process); err != nil {
+		return ErrProcessUpdateFailed(err)
+	}
+	return err
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"processor/internal/application/dto"
+	"processor/internal/application/ports"
+	processPkg "processor/internal/domain/entity/process"
+	"processor/internal/domain/service"
+	sharedPorts "shared/application/ports"
+	"shared/domain/entity"
+	"shared/domain/entity/download"
+	"shared/domain/entity/process"
+	"shared/infrastructure/config"
+	"shared/infrastructure/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage serves stored artifacts with their content type
+type memoryStorage struct {
+	ports.Storage
+	objects map[string][]byte
+	types   map[string]string
+	reads   int
+}
+
+func (s *memoryStorage) GetWithMetadata(ctx context.Context, bucket, key string) (io.ReadCloser, *ports.ObjectMetadata, error) {
+	s.reads++
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, nil, sharedPorts.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), &ports.ObjectMetadata{ContentType: s.types[key]}, nil
+}
+
+// fakeRepositories holds the rows of processes, downloads and report details
+type fakeRepositories struct {
+	sharedPorts.Repositories
+	processes map[int64]*entity.Process
+	downloads map[int64]*entity.Download
+	details   map[int64]*entity.AuditReportDetail
+
+	// claimed runs before a claim, to let another worker take the row first
+	claimed func()
+}
+
+func (r *fakeRepositories) Process() sharedPorts.ProcessRepository   { return &fakeProcesses{r: r} }
+func (r *fakeRepositories) Download() sharedPorts.DownloadRepository { return &fakeDownloads{r: r} }
+func (r *fakeRepositories) AuditReportDetail() sharedPorts.AuditReportDetailRepository {
+	return &fakeDetails{r: r}
+}
+
+type fakeProcesses struct {
+	sharedPorts.ProcessRepository
+	r *fakeRepositories
+}
+
+func (p *fakeProcesses) Get(ctx context.Context, id int64) (*entity.Process, error) {
+	proc, ok := p.r.processes[id]
+	if !ok {
+		return nil, sharedPorts.ErrNotFound
+	}
+	copied := *proc
+	return &copied, nil
+}
+
+// Claim starts the stored row the way the conditional update does
+func (p *fakeProcesses) Claim(ctx context.Context, id int64) (*entity.Process, error) {
+	if p.r.claimed != nil {
+		p.r.claimed()
+	}
+	proc := p.r.processes[id]
+	startable := proc.Status == process.StatusPending ||
+		(proc.Status == process.StatusFailed && proc.AttemptCount < process.DefaultMaxAttempts)
+	if !startable {
+		return nil, process.ErrClaimedElsewhere
+	}
+
+	now := time.Now()
+	proc.Status = process.StatusInProgress
+	proc.AttemptCount++
+	proc.StartedAt = &now
+	proc.UpdatedAt = now
+	proc.ErrorMessage = nil
+	copied := *proc
+	return &copied, nil
+}
+
+func (p *fakeProcesses) Update(ctx context.Context, proc *entity.Process) error {
+	copied := *proc
+	p.r.processes[proc.ID] = &copied
+	return nil
+}
+
+type fakeDownloads struct {
+	sharedPorts.DownloadRepository
+	r *fakeRepositories
+}
+
+func (d *fakeDownloads) Get(ctx context.Context, id int64) (*entity.Download, error) {
+	dl, ok := d.r.downloads[id]
+	if !ok {
+		return nil, sharedPorts.ErrNotFound
+	}
+	return dl, nil
+}
+
+type fakeDetails struct {
+	sharedPorts.AuditReportDetailRepository
+	r *fakeRepositories
+}
+
+func (d *fakeDetails) Upsert(ctx context.Context, detail *entity.AuditReportDetail) error {
+	d.r.details[detail.ReportID] = detail
+	return nil
+}
+
+type processFixture struct {
+	usecase      *ProcessFile
+	storage      *memoryStorage
+	repositories *fakeRepositories
+}
+
+func newProcessFixture(t *testing.T) *processFixture {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Adapters.Logger = "stdout"
+	cfg.Adapters.Metrics = "stdout"
+	obs, err := observability.CreateObservability(cfg)
+	require.NoError(t, err)
+
+	storage := &memoryStorage{objects: make(map[string][]byte), types: make(map[string]string)}
+	repositories := &fakeRepositories{
+		processes: make(map[int64]*entity.Process),
+		downloads: make(map[int64]*entity.Download),
+		details:   make(map[int64]*entity.AuditReportDetail),
+	}
+
+	usecase, err := NewProcessFile(service.NewExtractService(1<<20), storage, repositories, obs)
+	require.NoError(t, err)
+	return &processFixture{usecase: usecase, storage: storage, repositories: repositories}
+}
+
+// addArtifact stores a completed download of report 7 and its file
+func (f *processFixture) addArtifact(downloadID int64, contentType, extension string, content []byte) {
+	path := "reports/7/report" + extension
+	f.storage.objects[path] = content
+	f.storage.types[path] = contentType
+	f.repositories.downloads[downloadID] = &entity.Download{
+		ID:            downloadID,
+		ReportID:      7,
+		Kind:          download.ArtifactKindReport,
+		Status:        download.StatusCompleted,
+		StoragePath:   &path,
+		FileExtension: &extension,
+	}
+}
+
+func (f *processFixture) addProcess(proc *entity.Process) {
+	f.repositories.processes[proc.ID] = proc
+}
+
+func (f *processFixture) process(processID int64) error {
+	return f.usecase.Process(context.Background(), &dto.ProcessRequest{
+		EventType: dto.ProcessEventRequested,
+		ProcessID: processID,
+	})
+}
+
+func TestProcess_ExtractsText(t *testing.T) {
+	f := newProcessFixture(t)
+	f.addArtifact(10, "text/plain", ".txt", []byte("Reentrancy   in  withdraw\n"))
+	f.addProcess(&entity.Process{ID: 1, DownloadID: 10, Status: process.StatusPending})
+
+	require.NoError(t, f.process(1))
+
+	proc := f.repositories.processes[1]
+	assert.Equal(t, process.StatusCompleted, proc.Status)
+	assert.Equal(t, 1, proc.AttemptCount)
+	require.NotNil(t, proc.ProcessorVersion)
+	assert.Equal(t, "text-extractor/1.0", *proc.ProcessorVersion)
+	assert.NotNil(t, proc.StartedAt)
+	assert.NotNil(t, proc.CompletedAt)
+	assert.Nil(t, proc.ErrorMessage)
+
+	detail := f.repositories.details[7]
+	require.NotNil(t, detail, "the text is stored with the report")
+	require.NotNil(t, detail.FullSummary)
+	assert.Equal(t, "Reentrancy in withdraw", *detail.FullSummary)
+}
+
+func TestProcess_RetriesFailedProcess(t *testing.T) {
+	f := newProcessFixture(t)
+	f.addArtifact(10, "text/html", ".html", []byte("<html><body><p>Oracle manipulation</p></body></html>"))
+	message := "extraction failed"
+	f.addProcess(&entity.Process{ID: 1, DownloadID: 10, Status: process.StatusFailed, AttemptCount: 1, ErrorMessage: &message})
+
+	require.NoError(t, f.process(1))
+
+	proc := f.repositories.processes[1]
+	assert.Equal(t, process.StatusCompleted, proc.Status)
+	assert.Equal(t, 2, proc.AttemptCount)
+	assert.Nil(t, proc.ErrorMessage)
+	assert.Equal(t, "Oracle manipulation", *f.repositories.details[7].FullSummary)
+}
+
+func TestProcess_ExtractionFailure(t *testing.T) {
+	f := newProcessFixture(t)
+	f.addArtifact(10, "application/zip", ".zip", []byte("PK\x03\x04"))
+	f.addProcess(&entity.Process{ID: 1, DownloadID: 10, Status: process.StatusPending})
+
+	err := f.process(1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to extract text")
+
+	proc := f.repositories.processes[1]
+	assert.Equal(t, process.StatusFailed, proc.Status, "the failure is recorded")
+	assert.Equal(t, 1, proc.AttemptCount)
+	require.NotNil(t, proc.ErrorMessage)
+	assert.Equal(t, err.Error(), *proc.ErrorMessage)
+	assert.Nil(t, proc.ProcessorVersion)
+	assert.Empty(t, f.repositories.details)
+}
+
+func TestProcess_DownloadNotCompleted(t *testing.T) {
+	f := newProcessFixture(t)
+	f.repositories.downloads[10] = &entity.Download{ID: 10, ReportID: 7, Status: download.StatusInProgress}
+	f.addProcess(&entity.Process{ID: 1, DownloadID: 10, Status: process.StatusPending})
+
+	err := f.process(1)
+	assert.ErrorIs(t, err, ErrDownloadNotCompleted)
+	assert.Equal(t, process.StatusFailed, f.repositories.processes[1].Status)
+	assert.Zero(t, f.storage.reads)
+}
+
+func TestProcess_NotStarted(t *testing.T) {
+	message := "extraction failed"
+	version := "text-extractor/1.0"
+	tests := []struct {
+		name string
+		proc *entity.Process
+		err  error
+	}{
+		{
+			name: "already completed",
+			proc: &entity.Process{ID: 1, DownloadID: 10, Status: process.StatusCompleted, AttemptCount: 1, ProcessorVersion: &version},
+			err:  processPkg.ErrAlreadyCompleted,
+		},
+		{
+			name: "max attempts exceeded",
+			proc: &entity.Process{ID: 1, DownloadID: 10, Status: process.StatusFailed, AttemptCount: 3, ErrorMessage: &message},
+			err:  processPkg.ErrMaxAttemptsExceeded,
+		},
+		{
+			name: "in progress elsewhere",
+			proc: &entity.Process{ID: 1, DownloadID: 10, Status: process.StatusInProgress, AttemptCount: 1},
+			err:  processPkg.ErrInvalidStateTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newProcessFixture(t)
+			f.addArtifact(10, "text/plain", ".txt", []byte("Reentrancy"))
+			f.addProcess(tt.proc)
+			stored := *tt.proc
+
+			assert.ErrorIs(t, f.process(1), tt.err)
+			assert.Equal(t, stored, *f.repositories.processes[1], "the process is left as is")
+			assert.Zero(t, f.storage.reads)
+			assert.Empty(t, f.repositories.details)
+		})
+	}
+}
+
+func TestProcess_LosesClaim(t *testing.T) {
+	f := newProcessFixture(t)
+	f.addArtifact(10, "text/plain", ".txt", []byte("Reentrancy"))
+	f.addProcess(&entity.Process{ID: 1, DownloadID: 10, Status: process.StatusPending})
+
+	// A duplicate delivery claims the process between the read and the claim
+	f.repositories.claimed = func() {
+		f.repositories.claimed = nil
+		_, err := f.repositories.Process().Claim(context.Background(), 1)
+		require.NoError(t, err)
+	}
+
+	assert.ErrorIs(t, f.process(1), processPkg.ErrClaimedElsewhere)
+
+	proc := f.repositories.processes[1]
+	assert.Equal(t, process.StatusInProgress, proc.Status, "the other worker keeps it")
+	assert.Equal(t, 1, proc.AttemptCount, "claimed once")
+	assert.Zero(t, f.storage.reads)
+	assert.Empty(t, f.repositories.details)
+}
+
+func TestProcess_NotFound(t *testing.T) {
+	f := newProcessFixture(t)
+
+	err := f.process(99)
+	assert.ErrorIs(t, err, sharedPorts.ErrNotFound)
+	assert.Contains(t, err.Error(), "failed to get process")
+}
@@ -0,0 +1,11 @@
+package auditreportdetail
+
+import "shared/domain/entity/auditreportdetail"
+
+type (
+	AuditReportDetail = auditreportdetail.AuditReportDetail
+)
+
+func NewAuditReportDetail(reportID int64) *AuditReportDetail {
+	return auditreportdetail.NewAuditReportDetail(reportID)
+}
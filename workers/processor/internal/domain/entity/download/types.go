@@ -0,0 +1,7 @@
+package download
+
+import "shared/domain/entity/download"
+
+type (
+	Download = download.Download
+)
@@ -0,0 +1,29 @@
+package process
+
+import "shared/domain/entity/process"
+
+type (
+	Process = process.Process
+)
+
+const (
+	StatusPending    process.ProcessStatus = "pending"
+	StatusInProgress process.ProcessStatus = "in_progress"
+	StatusCompleted  process.ProcessStatus = "completed"
+	StatusFailed     process.ProcessStatus = "failed"
+)
+
+var (
+	// State transition errors
+	ErrAlreadyCompleted       = process.ErrAlreadyCompleted
+	ErrAlreadyInProgress      = process.ErrAlreadyInProgress
+	ErrInvalidStateTransition = process.ErrInvalidStateTransition
+	ErrNotInProgress          = process.ErrNotInProgress
+	ErrClaimedElsewhere       = process.ErrClaimedElsewhere
+
+	// Retry/attempt errors
+	ErrMaxAttemptsExceeded = process.ErrMaxAttemptsExceeded
+
+	// Validation errors
+	ErrEmptyProcessorVersion = process.ErrEmptyProcessorVersion
+)
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFileTooLarge = errors.New("file size exceeds maximum")
+	ErrNoText       = errors.New("no text could be extracted")
+)
+
+// Error wrapping functions with context
+func ErrReadContent(err error) error {
+	return fmt.Errorf("failed to read content: %w", err)
+}
+
+func ErrUnsupportedFormat(contentType, extension string) error {
+	return fmt.Errorf("unsupported format: content type %q, extension %q", contentType, extension)
+}
+
+func ErrParsePDF(err error) error {
+	return fmt.Errorf("failed to parse PDF: %w", err)
+}
+
+func ErrParseHTML(err error) error {
+	return fmt.Errorf("failed to parse HTML: %w", err)
+}
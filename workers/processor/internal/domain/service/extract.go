@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+)
+
+type ExtractService struct {
+	maxFileSize int64
+	version     string
+}
+
+func NewExtractService(maxFileSize int64) *ExtractService {
+	return &ExtractService{
+		maxFileSize: maxFileSize,
+		version:     "text-extractor/1.0",
+	}
+}
+
+// Version identifies the extraction logic, so reports can be reprocessed when it changes
+func (s *ExtractService) Version() string {
+	return s.version
+}
+
+// Extract reads the whole artifact and returns its plain text. The format is
+// picked from the content type first and the file extension second.
+func (s *ExtractService) Extract(ctx context.Context, reader io.Reader, contentType, extension string) (string, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, s.maxFileSize+1))
+	if err != nil {
+		return "", ErrReadContent(err)
+	}
+	if int64(len(content)) > s.maxFileSize {
+		return "", ErrFileTooLarge
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var text string
+	switch detectFormat(contentType, extension) {
+	case formatPDF:
+		text, err = extractPDF(content)
+	case formatHTML:
+		text, err = extractHTML(content)
+	case formatText:
+		text = string(content)
+	default:
+		return "", ErrUnsupportedFormat(contentType, extension)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = normalizeWhitespace(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+
+	return text, nil
+}
+
+// Private helper functions
+
+type format int
+
+const (
+	formatUnknown format = iota
+	formatPDF
+	formatHTML
+	formatText
+)
+
+func detectFormat(contentType, extension string) format {
+	if idx := strings.Index(contentType, ";"); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+
+	switch contentType {
+	case "application/pdf":
+		return formatPDF
+	case "text/html", "application/xhtml+xml":
+		return formatHTML
+	case "text/plain", "text/markdown":
+		return formatText
+	}
+
+	switch strings.ToLower(extension) {
+	case ".pdf":
+		return formatPDF
+	case ".html", ".htm":
+		return formatHTML
+	case ".txt", ".md", ".markdown":
+		return formatText
+	}
+
+	return formatUnknown
+}
+
+func extractPDF(content []byte) (text string, err error) {
+	// The PDF parser panics on some malformed documents
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrParsePDF(fmt.Errorf("%v", r))
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", ErrParsePDF(err)
+	}
+
+	var builder strings.Builder
+	fonts := make(map[string]*pdf.Font)
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		// Cache fonts so charmaps are not parsed again for every page
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+
+		pageText, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", ErrParsePDF(err)
+		}
+		builder.WriteString(pageText)
+		builder.WriteString("\n\n")
+	}
+
+	return builder.String(), nil
+}
+
+// extractHTML keeps the visible text of a page, one block per line
+func extractHTML(content []byte) (string, error) {
+	tokenizer := html.NewTokenizer(bytes.NewReader(content))
+
+	var builder strings.Builder
+	skipDepth := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return "", ErrParseHTML(err)
+			}
+			return builder.String(), nil
+
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			if isSkippedTag(string(name)) {
+				skipDepth++
+			} else if isBlockTag(string(name)) {
+				builder.WriteString("\n")
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if isSkippedTag(string(name)) && skipDepth > 0 {
+				skipDepth--
+			} else if isBlockTag(string(name)) {
+				builder.WriteString("\n")
+			}
+
+		case html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == "br" {
+				builder.WriteString("\n")
+			}
+
+		case html.TextToken:
+			if skipDepth == 0 {
+				builder.Write(tokenizer.Text())
+			}
+		}
+	}
+}
+
+func isSkippedTag(name string) bool {
+	switch name {
+	case "script", "style", "noscript", "template", "svg", "head":
+		return true
+	}
+	return false
+}
+
+func isBlockTag(name string) bool {
+	switch name {
+	case "p", "div", "br", "li", "tr", "section", "article", "header", "footer",
+		"h1", "h2", "h3", "h4", "h5", "h6", "pre", "blockquote", "table", "ul", "ol":
+		return true
+	}
+	return false
+}
+
+// normalizeWhitespace collapses runs of spaces inside lines and drops blank lines
+func normalizeWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
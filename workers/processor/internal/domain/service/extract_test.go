@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract_HTML(t *testing.T) {
+	page := `<html><head><title>ignored</title><style>body{}</style></head>
+<body><h1>Panoptic   Hypovault</h1><script>var x = 1;</script>
+<p>High risk <b>findings</b></p><ul><li>H-01</li><li>H-02</li></ul></body></html>`
+	svc := NewExtractService(1024)
+
+	text, err := svc.Extract(t.Context(), strings.NewReader(page), "text/html; charset=utf-8", ".html")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Panoptic Hypovault\nHigh risk findings\nH-01\nH-02", text)
+}
+
+func TestExtract_PDF(t *testing.T) {
+	svc := NewExtractService(1024 * 1024)
+
+	text, err := svc.Extract(t.Context(), bytes.NewReader(minimalPDF("Hello Audit")), "application/pdf", ".pdf")
+	require.NoError(t, err)
+
+	assert.Contains(t, text, "Hello Audit")
+}
+
+func TestExtract_FallsBackToExtension(t *testing.T) {
+	svc := NewExtractService(1024)
+
+	text, err := svc.Extract(t.Context(), strings.NewReader("# Findings\n\n  M-01  "), "application/octet-stream", ".md")
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Findings\nM-01", text)
+}
+
+func TestExtract_RejectsUnsupportedFormat(t *testing.T) {
+	svc := NewExtractService(1024)
+
+	_, err := svc.Extract(t.Context(), strings.NewReader("PK"), "application/zip", ".zip")
+	assert.Error(t, err)
+}
+
+func TestExtract_RejectsOversizedContent(t *testing.T) {
+	svc := NewExtractService(8)
+
+	_, err := svc.Extract(t.Context(), strings.NewReader(strings.Repeat("a", 64)), "text/plain", ".txt")
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+}
+
+func TestExtract_RejectsEmptyText(t *testing.T) {
+	svc := NewExtractService(1024)
+
+	_, err := svc.Extract(t.Context(), strings.NewReader("<html><script>x</script></html>"), "text/html", ".html")
+	assert.True(t, errors.Is(err, ErrNoText))
+}
+
+// minimalPDF builds a single page PDF with a valid xref table
+func minimalPDF(text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 712 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}